@@ -1,25 +1,54 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/ybouhjira/claude-code-tts/internal/audio"
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+	"github.com/ybouhjira/claude-code-tts/internal/language"
+	"github.com/ybouhjira/claude-code-tts/internal/lexicon"
+	"github.com/ybouhjira/claude-code-tts/internal/normalize"
+	"github.com/ybouhjira/claude-code-tts/internal/redact"
 	"github.com/ybouhjira/claude-code-tts/internal/tts"
+	"github.com/ybouhjira/claude-code-tts/internal/usage"
 )
 
 func main() {
+	// "speak-text usage" reports usage instead of speaking; use
+	// "speak-text -- usage" to say the word
+	if len(os.Args) > 1 && os.Args[1] == "usage" {
+		runUsage(os.Args[2:])
+		return
+	}
+
 	// Parse flags
-	voice := flag.String("voice", "nova", "Voice to use (alloy, echo, fable, onyx, nova, shimmer)")
+	voice := flag.String("voice", "", "Voice to use (default: nova for openai, provider default otherwise)")
+	provider := flag.String("provider", "", "TTS provider to use (default: from config or TTS_PROVIDER)")
+	speed := flag.Float64("speed", 0, "Speaking speed from 0.25 to 4.0 (default: provider default)")
+	format := flag.String("format", "", "Audio format: mp3, opus, aac, flac, wav or pcm (default: mp3)")
+	instructions := flag.String("instructions", "", "Delivery instructions, e.g. \"calm\" (gpt-4o-mini-tts only)")
+	noCache := flag.Bool("no-cache", false, "Always synthesize, bypassing the audio cache")
+	raw := flag.Bool("raw", false, "Speak the text as given, without reading markdown, code, URLs and paths")
+	first := flag.Bool("first", false, "Speak only the first sentence (at most 200 characters)")
+	lang := flag.String("language", "", "Language of the text, e.g. fr (default: detected)")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [OPTIONS] TEXT\n\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "Converts text to speech using OpenAI TTS API and plays it.\n\n")
+		fmt.Fprintf(os.Stderr, "Converts text to speech using the configured TTS provider and plays it.\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExample:\n")
 		fmt.Fprintf(os.Stderr, "  %s \"Build completed\"\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -voice onyx \"Error occurred\"\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -speed 1.5 -instructions urgent \"Tests failed\"\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -first \"$(cat response.md)\"\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -language fr \"Les tests passent\"\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s usage -window week\n", os.Args[0])
 	}
 	flag.Parse()
 
@@ -31,16 +60,110 @@ func main() {
 
 	text := flag.Arg(0)
 
-	// Validate environment
-	if os.Getenv("OPENAI_API_KEY") == "" {
-		fmt.Fprintf(os.Stderr, "Error: OPENAI_API_KEY environment variable is required\n")
+	req := tts.Request{
+		Text:         text,
+		Speed:        *speed,
+		Format:       tts.Format(*format),
+		Instructions: *instructions,
+	}
+	if err := req.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	if *provider != "" {
+		cfg.Provider = *provider
+	}
+	if *noCache {
+		cfg.Cache.Disabled = true
+	}
+
+	// Keep secrets and personal data on this machine
+	redactor, err := redact.New(cfg.Redact)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid redact config: %v\n", err)
+		os.Exit(1)
+	}
+	if redactor != nil {
+		req.Text, _ = redactor.Redact(req.Text)
+	}
+
+	// Normalize unless the text is to be read as it is
+	var normalizer *normalize.Normalizer
+	if !*raw {
+		normalizer, err = normalize.New(cfg.Normalize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid normalize config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Apply the pronunciation lexicon shared with the server; normalizing
+	// leaves its replacements as they are
+	lex, err := lexicon.Load(cfg.Lexicon, config.ProjectDir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: pronunciation lexicon disabled: %v\n", err)
+	}
+	if lex != nil {
+		var mark func(string) string
+		if normalizer != nil {
+			mark = normalize.Protect
+		}
+		req.Text = lex.ApplyMarked(req.Text, mark)
+	}
+
+	// Speak the text's language with the voice and provider routed to it;
+	// the flags still win
+	if *lang == "" {
+		*lang = language.Detect(req.Text)
+	}
+	if route, ok := cfg.Languages[*lang]; ok {
+		if *provider == "" && route.Provider != "" && route.Provider != cfg.Provider {
+			cfg.Provider = route.Provider
+			cfg.Fallbacks = nil
+		}
+		if *voice == "" {
+			*voice = route.Voice
+		}
+	}
+
+	// Read markdown, code, URLs and paths the way a person would
+	if normalizer != nil {
+		req.Text = normalizer.NormalizeIn(req.Text, *lang)
+	}
+	if *first {
+		req.Text = firstSentence(req.Text, maxFirstSentence)
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		// Only code or markup: nothing worth saying
+		return
+	}
+
+	// Create TTS provider
+	client, err := tts.New(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Pick voice
+	if *voice == "" {
+		*voice = string(tts.DefaultVoice(client))
+		if _, ok := client.(*tts.Client); ok && tts.SupportsVoice(client, tts.VoiceNova) {
+			*voice = string(tts.VoiceNova)
+		}
+	}
+
 	// Validate voice
-	if !tts.IsValidVoice(*voice) {
+	if !tts.SupportsVoice(client, tts.Voice(*voice)) {
 		fmt.Fprintf(os.Stderr, "Error: invalid voice '%s'. Valid voices: ", *voice)
-		for i, v := range tts.ValidVoices() {
+		for i, v := range client.(tts.VoiceLister).Voices() {
 			if i > 0 {
 				fmt.Fprintf(os.Stderr, ", ")
 			}
@@ -50,20 +173,109 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create TTS client
-	client := tts.NewClient()
+	// Log the request alongside the server's
+	if usageLog := usage.Open(cfg.Usage, config.ProjectDir()); usageLog != nil {
+		client = tts.WithUsage(client, tts.NewCostTable(cfg), func(u tts.Usage) {
+			if err := usageLog.Add(u); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		})
+	}
+
+	// Apply the rate limits and the spending caps shared with the server
+	budget := tts.NewBudget(cfg)
+	if budget != nil {
+		budget.OnWarn(func(msg string) {
+			fmt.Fprintf(os.Stderr, "Warning: TTS budget: %s\n", msg)
+		})
+	}
+	client, err = tts.WithLimits(client, cfg, budget)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Share the server's audio cache; without it, just synthesize
+	cache, err := tts.NewCache(cfg.Cache)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: audio cache disabled: %v\n", err)
+	}
+	client = tts.WithCache(client, cfg, cache)
+
+	// Ctrl-C aborts the request and stops playback
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	req.Voice = tts.Voice(*voice)
+	retry := tts.NewRetryPolicy(cfg.Retry)
+	player := audio.NewPlayer()
+
+	// Stream when a player can read the audio from stdin, so playback
+	// starts before the download finishes
+	streamFormat := req.Format
+	if streamFormat == "" {
+		streamFormat = tts.FormatPCM
+	}
+	if !cfg.Streaming.Disabled && player.CanStream(string(streamFormat)) {
+		req.Format = streamFormat
+		stream, err := retry.Stream(ctx, client, req, nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error synthesizing speech: %v\n", err)
+			os.Exit(1)
+		}
+		defer stream.Body.Close()
+		if stream.Format == tts.FormatNone {
+			return
+		}
+		if player.CanStream(string(stream.Format)) {
+			err = player.PlayStream(ctx, stream.Body, string(stream.Format), stream.SampleRate)
+		} else {
+			var data []byte
+			if data, err = io.ReadAll(stream.Body); err == nil {
+				err = player.PlayContext(ctx, data, string(stream.Format))
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error playing audio: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Synthesize speech
-	audioData, err := client.Synthesize(text, tts.Voice(*voice))
+	result, err := retry.Synthesize(ctx, client, req, nil)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error synthesizing speech: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Play audio
-	player := audio.NewPlayer()
-	if err := player.Play(audioData); err != nil {
+	// Play audio (unless the provider already played it)
+	if result.Format == tts.FormatNone {
+		return
+	}
+	if err := player.PlayContext(ctx, result.Data, string(result.Format)); err != nil {
 		fmt.Fprintf(os.Stderr, "Error playing audio: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// maxFirstSentence is the most -first speaks
+const maxFirstSentence = 200
+
+// firstSentence returns the first sentence of text, cut at a word
+// boundary if it is longer than limit characters
+func firstSentence(text string, limit int) string {
+	sentence := tts.SplitFirstSentence([]string{strings.TrimSpace(text)})[0]
+	if i := strings.Index(sentence, "\n\n"); i > 0 {
+		sentence = sentence[:i]
+	}
+	runes := []rune(sentence)
+	if len(runes) <= limit {
+		return sentence
+	}
+	cut := string(runes[:limit])
+	if i := strings.LastIndexAny(cut, " \n"); i > 0 {
+		cut = cut[:i]
+	}
+	return cut
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+	"github.com/ybouhjira/claude-code-tts/internal/usage"
+)
+
+// runUsage prints the usage summary for the usage subcommand
+func runUsage(args []string) {
+	fs := flag.NewFlagSet("usage", flag.ExitOnError)
+	window := fs.String("window", usage.Month, "Period to report: day, week or month")
+	asJSON := fs.Bool("json", false, "Print the summary as JSON")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s usage [OPTIONS]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Shows TTS characters, audio and estimated cost by project and provider.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	store := usage.Open(cfg.Usage, "")
+	if store == nil {
+		fmt.Fprintf(os.Stderr, "Error: usage logging is disabled in %s\n", config.Path())
+		os.Exit(1)
+	}
+
+	summary, err := store.Summary(*window)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(summary)
+	} else {
+		err = summary.WriteText(os.Stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
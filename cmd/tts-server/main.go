@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"syscall"
 
+	"github.com/ybouhjira/claude-code-tts/internal/config"
 	"github.com/ybouhjira/claude-code-tts/internal/logging"
 	"github.com/ybouhjira/claude-code-tts/internal/server"
 )
@@ -25,14 +26,21 @@ func main() {
 	logging.Info("Log file: %s", logging.GetLogPath())
 	logging.Info("========================================")
 
-	// Check for required environment variable
-	if os.Getenv("OPENAI_API_KEY") == "" {
-		logging.Fatal("OPENAI_API_KEY environment variable is required")
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Fatal("Failed to load config: %v", err)
+	}
+	logging.Info("Config: %s (provider: %s)", config.Path(), cfg.Provider)
+
+	// Provider credentials are checked when the provider is created, so
+	// local engines such as piper start without an OpenAI key
+	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
+		logging.Info("OPENAI_API_KEY is set (length: %d)", len(key))
 	}
-	logging.Info("OPENAI_API_KEY is set (length: %d)", len(os.Getenv("OPENAI_API_KEY")))
 
 	// Create and start the MCP server
-	srv, err := server.New()
+	srv, err := server.New(cfg)
 	if err != nil {
 		logging.Fatal("Failed to create server: %v", err)
 	}
@@ -1,13 +1,30 @@
 package audio
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
+	"strconv"
 	"sync"
+	"time"
 )
 
+// ErrStreamUnsupported is returned when no installed player can read the
+// format from standard input
+var ErrStreamUnsupported = errors.New("no audio player can stream format")
+
+// formats are the file formats the players can play
+var formats = []string{"mp3", "opus", "ogg", "aac", "m4a", "flac", "wav", "aiff"}
+
+// Formats returns the audio formats Play accepts
+func Formats() []string {
+	return append([]string(nil), formats...)
+}
+
 // Player handles audio playback with mutex protection
 type Player struct {
 	mu        sync.Mutex
@@ -19,17 +36,33 @@ func NewPlayer() *Player {
 	return &Player{}
 }
 
-// Play plays the given audio data
+// Play plays the given MP3 audio data
 // Only one audio can play at a time (mutex protected)
 func (p *Player) Play(audioData []byte) error {
+	return p.PlayFormat(audioData, "mp3")
+}
+
+// PlayFormat plays audio data encoded in the given format (mp3, wav, ...)
+// Only one audio can play at a time (mutex protected)
+func (p *Player) PlayFormat(audioData []byte, format string) error {
+	return p.PlayContext(context.Background(), audioData, format)
+}
+
+// PlayContext plays audio data like PlayFormat, killing the player process
+// if ctx is cancelled before playback ends
+func (p *Player) PlayContext(ctx context.Context, audioData []byte, format string) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	p.isPlaying = true
 	defer func() { p.isPlaying = false }()
 
+	if format == "" {
+		format = "mp3"
+	}
+
 	// Create temporary file
-	tmpFile, err := os.CreateTemp("", "tts-*.mp3")
+	tmpFile, err := os.CreateTemp("", "tts-*."+format)
 	if err != nil {
 		return fmt.Errorf("failed to create temp file: %w", err)
 	}
@@ -41,42 +74,154 @@ func (p *Player) Play(audioData []byte) error {
 	}
 	tmpFile.Close()
 
-	// Play audio based on platform
-	var cmd *exec.Cmd
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cmd, err := playerCommand(ctx, tmpFile.Name(), format)
+	if err != nil {
+		return err
+	}
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("audio playback failed: %w", err)
+	}
+
+	return nil
+}
+
+// playerCommand picks the platform audio player for a file
+func playerCommand(ctx context.Context, path, format string) (*exec.Cmd, error) {
 	switch runtime.GOOS {
 	case "darwin":
-		cmd = exec.Command("afplay", tmpFile.Name())
+		return exec.CommandContext(ctx, "afplay", path), nil
 	case "linux":
 		// Try common Linux audio players
 		if _, err := exec.LookPath("mpv"); err == nil {
-			cmd = exec.Command("mpv", "--no-video", tmpFile.Name())
-		} else if _, err := exec.LookPath("ffplay"); err == nil {
-			cmd = exec.Command("ffplay", "-nodisp", "-autoexit", tmpFile.Name())
-		} else if _, err := exec.LookPath("aplay"); err == nil {
+			return exec.CommandContext(ctx, "mpv", "--no-video", path), nil
+		}
+		if _, err := exec.LookPath("ffplay"); err == nil {
+			return exec.CommandContext(ctx, "ffplay", "-nodisp", "-autoexit", path), nil
+		}
+		if format == "wav" {
+			if _, err := exec.LookPath("aplay"); err == nil {
+				return exec.CommandContext(ctx, "aplay", "-q", path), nil
+			}
+		}
+		if _, err := exec.LookPath("aplay"); err == nil {
 			// aplay requires WAV, so use mpg123 for MP3
 			if _, err := exec.LookPath("mpg123"); err == nil {
-				cmd = exec.Command("mpg123", "-q", tmpFile.Name())
-			} else {
-				return fmt.Errorf("no suitable audio player found on Linux (install mpv, ffplay, or mpg123)")
+				return exec.CommandContext(ctx, "mpg123", "-q", path), nil
 			}
-		} else {
-			return fmt.Errorf("no suitable audio player found on Linux (install mpv, ffplay, or mpg123)")
 		}
+		return nil, fmt.Errorf("no suitable audio player found on Linux (install mpv, ffplay, or mpg123)")
 	case "windows":
 		// Windows Media Player via PowerShell
-		cmd = exec.Command("powershell", "-c",
-			fmt.Sprintf(`(New-Object Media.SoundPlayer '%s').PlaySync()`, tmpFile.Name()))
+		return exec.CommandContext(ctx, "powershell", "-c",
+			fmt.Sprintf(`(New-Object Media.SoundPlayer '%s').PlaySync()`, path)), nil
 	default:
-		return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+		return nil, fmt.Errorf("unsupported platform: %s", runtime.GOOS)
 	}
+}
+
+// CanStream reports whether an installed player can play the format from
+// standard input
+func (p *Player) CanStream(format string) bool {
+	_, err := streamCommand(context.Background(), format, 0)
+	return err == nil
+}
+
+// PlayStream plays audio as it is read from r, so playback starts before
+// the audio has fully arrived. Raw "pcm" is 16-bit mono at sampleRate.
+// Like PlayContext, only one audio plays at a time and cancelling ctx
+// kills the player.
+func (p *Player) PlayStream(ctx context.Context, r io.Reader, format string, sampleRate int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.isPlaying = true
+	defer func() { p.isPlaying = false }()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cmd, err := streamCommand(ctx, format, sampleRate)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = r
+	cmd.WaitDelay = time.Second
 
 	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		return fmt.Errorf("audio playback failed: %w", err)
 	}
 
 	return nil
 }
 
+// streamCommand picks a player that reads the format from stdin. afplay
+// and the Windows player only play files, so streaming needs mpv, ffplay,
+// aplay (WAV and PCM) or mpg123 (MP3).
+func streamCommand(ctx context.Context, format string, sampleRate int) (*exec.Cmd, error) {
+	if format == "" {
+		format = "mp3"
+	}
+	if sampleRate <= 0 {
+		sampleRate = 24000
+	}
+	rate := strconv.Itoa(sampleRate)
+
+	if _, err := exec.LookPath("mpv"); err == nil {
+		args := []string{"--no-video", "--no-terminal", "--cache=no"}
+		if format == "pcm" {
+			args = append(args, "--demuxer=rawaudio", "--demuxer-rawaudio-format=s16le",
+				"--demuxer-rawaudio-channels=1", "--demuxer-rawaudio-rate="+rate)
+		}
+		return exec.CommandContext(ctx, "mpv", append(args, "-")...), nil
+	}
+	if _, err := exec.LookPath("ffplay"); err == nil {
+		args := []string{"-nodisp", "-autoexit", "-loglevel", "quiet"}
+		if format == "pcm" {
+			args = append(args, "-f", "s16le", "-ar", rate, "-ac", "1")
+		}
+		return exec.CommandContext(ctx, "ffplay", append(args, "-")...), nil
+	}
+	switch format {
+	case "pcm":
+		if _, err := exec.LookPath("aplay"); err == nil {
+			return exec.CommandContext(ctx, "aplay", "-q", "-t", "raw", "-f", "S16_LE", "-c", "1", "-r", rate, "-"), nil
+		}
+	case "wav":
+		if _, err := exec.LookPath("aplay"); err == nil {
+			return exec.CommandContext(ctx, "aplay", "-q", "-"), nil
+		}
+	case "mp3":
+		if _, err := exec.LookPath("mpg123"); err == nil {
+			return exec.CommandContext(ctx, "mpg123", "-q", "-"), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrStreamUnsupported, format)
+}
+
+// PlayWith runs play, which plays audio by other means such as an engine
+// that speaks itself, while no other audio plays
+func (p *Player) PlayWith(play func() error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.isPlaying = true
+	defer func() { p.isPlaying = false }()
+	return play()
+}
+
 // IsPlaying returns whether audio is currently playing
 func (p *Player) IsPlaying() bool {
 	p.mu.Lock()
@@ -1,6 +1,13 @@
 package audio
 
 import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -320,3 +327,104 @@ func TestPlayer_StructureInitialization(t *testing.T) {
 		t.Error("mutex should protect isPlaying field")
 	}
 }
+
+func TestPlayer_PlayFormat_WAV(t *testing.T) {
+	player := NewPlayer()
+
+	// A header-only WAV may or may not play depending on the platform player;
+	// this exercises the format-specific temp file and player selection
+	err := player.PlayFormat([]byte("RIFF"), "wav")
+	if err == nil {
+		t.Log("Note: WAV data was accepted - player may vary by platform")
+	}
+	if player.IsPlaying() {
+		t.Error("expected IsPlaying to be false after PlayFormat returns")
+	}
+}
+
+func TestPlayer_PlayContext_Cancelled(t *testing.T) {
+	player := NewPlayer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := player.PlayContext(ctx, []byte("audio"), "mp3")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if player.IsPlaying() {
+		t.Error("expected isPlaying to be reset after cancellation")
+	}
+}
+
+// fakePlayers puts scripts named after the given players first on PATH.
+// Each records its arguments and stdin under dir.
+func fakePlayers(t *testing.T, names ...string) (dir string) {
+	t.Helper()
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat not found")
+	}
+	dir = t.TempDir()
+	for _, name := range names {
+		script := "#!/bin/sh\necho \"$@\" > " + dir + "/" + name + ".args\n" + cat + " > " + dir + "/" + name + ".in\n"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir)
+	return dir
+}
+
+func TestStreamCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake players are shell scripts")
+	}
+
+	fakePlayers(t, "mpv")
+	cmd, err := streamCommand(context.Background(), "pcm", 24000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	args := strings.Join(cmd.Args, " ")
+	if !strings.Contains(args, "--demuxer-rawaudio-rate=24000") || !strings.HasSuffix(args, " -") {
+		t.Errorf("unexpected mpv args %q", args)
+	}
+
+	fakePlayers(t, "aplay")
+	if _, err := streamCommand(context.Background(), "pcm", 24000); err != nil {
+		t.Errorf("expected aplay to stream PCM: %v", err)
+	}
+	if _, err := streamCommand(context.Background(), "mp3", 0); !errors.Is(err, ErrStreamUnsupported) {
+		t.Errorf("expected aplay not to stream MP3, got %v", err)
+	}
+
+	fakePlayers(t)
+	if NewPlayer().CanStream("mp3") {
+		t.Error("expected no streaming without a player")
+	}
+}
+
+func TestPlayer_PlayStream(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake players are shell scripts")
+	}
+	dir := fakePlayers(t, "ffplay")
+
+	player := NewPlayer()
+	if err := player.PlayStream(context.Background(), strings.NewReader("raw-samples"), "pcm", 16000); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	in, _ := os.ReadFile(filepath.Join(dir, "ffplay.in"))
+	if string(in) != "raw-samples" {
+		t.Errorf("expected the stream on the player's stdin, got %q", in)
+	}
+	args, _ := os.ReadFile(filepath.Join(dir, "ffplay.args"))
+	if !strings.Contains(string(args), "-f s16le -ar 16000 -ac 1") {
+		t.Errorf("unexpected ffplay args %q", args)
+	}
+	if player.IsPlaying() {
+		t.Error("expected isPlaying to be reset after playback")
+	}
+}
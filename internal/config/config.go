@@ -0,0 +1,298 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultProvider is the provider used when none is configured
+const DefaultProvider = "openai"
+
+// Config holds the user-level TTS settings
+type Config struct {
+	// Provider is the name of the active provider. It refers either to a
+	// key in Providers or directly to a registered provider type.
+	Provider string `json:"provider"`
+
+	// Providers holds named provider instances and their settings
+	Providers map[string]ProviderConfig `json:"providers,omitempty"`
+
+	// Fallbacks are tried in order when the active provider fails,
+	// e.g. ["kokoro", "espeak"]
+	Fallbacks []string `json:"fallbacks,omitempty"`
+
+	// Retry controls how transient provider failures are retried
+	Retry RetryConfig `json:"retry"`
+
+	// Breaker controls when a failing provider is skipped
+	Breaker BreakerConfig `json:"breaker"`
+
+	// Cache controls the on-disk cache of synthesized audio
+	Cache CacheConfig `json:"cache"`
+
+	// Streaming controls playing audio while it is still downloading
+	Streaming StreamingConfig `json:"streaming"`
+
+	// RateLimit caps the requests and characters sent to each provider.
+	// A provider's own requests_per_minute and chars_per_minute settings
+	// override it.
+	RateLimit RateLimitConfig `json:"rate_limit"`
+
+	// Budget caps the characters and estimated cost spent per day and month
+	Budget BudgetConfig `json:"budget"`
+
+	// Usage controls the log of every synthesis behind tts_usage
+	Usage UsageConfig `json:"usage"`
+
+	// Lexicon controls the pronunciation substitutions applied to the text
+	Lexicon LexiconConfig `json:"lexicon"`
+
+	// Normalize controls how markdown, code, URLs, paths and numbers are read
+	Normalize NormalizeConfig `json:"normalize"`
+
+	// Redact controls the removal of secrets and personal data from the
+	// text before it is sent to a provider
+	Redact RedactConfig `json:"redact"`
+
+	// Languages routes text detected in a language to its own voice and
+	// provider, by ISO 639-1 code: {"fr": {"voice": "nova"}}. Text in
+	// other languages uses the active provider.
+	Languages map[string]LanguageRoute `json:"languages,omitempty"`
+}
+
+// LanguageRoute holds the voice and provider that speak one language
+type LanguageRoute struct {
+	Voice    string `json:"voice,omitempty"`    // default: the provider's default voice
+	Provider string `json:"provider,omitempty"` // default: the active provider
+}
+
+// RedactConfig holds the secret redaction settings
+type RedactConfig struct {
+	Disabled bool `json:"disabled"`
+
+	// Patterns are extra regular expressions to redact, by name:
+	// {"ticket": "PROJ-\\d+"} speaks "redacted ticket". If a pattern has
+	// a group, only the first group is replaced.
+	Patterns map[string]string `json:"patterns,omitempty"`
+}
+
+// NormalizeConfig holds the developer-text clean-up settings
+type NormalizeConfig struct {
+	Disabled   bool   `json:"disabled"`
+	CodeBlocks string `json:"code_blocks"` // summarize (default), skip or read
+
+	// Locale is the language numbers, dates and units are read in:
+	// en (default) or fr
+	Locale string `json:"locale"`
+	// NoVerbalize leaves numbers, dates and units as written
+	NoVerbalize bool `json:"no_verbalize"`
+}
+
+// LexiconConfig holds the pronunciation lexicon settings. A project's
+// .claude/tts-lexicon.json is read on top of the global file.
+type LexiconConfig struct {
+	Disabled bool   `json:"disabled"`
+	Path     string `json:"path"` // global file, default: tts-lexicon.json next to the config file
+}
+
+// UsageConfig holds the usage log settings
+type UsageConfig struct {
+	Disabled bool   `json:"disabled"`
+	Path     string `json:"path"` // default: tts-usage.jsonl next to the config file
+}
+
+// RateLimitConfig holds the per-provider rate limits; zero values use the defaults
+type RateLimitConfig struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	CharsPerMinute    int `json:"chars_per_minute"`
+}
+
+// BudgetConfig holds the spending caps; a zero cap is unlimited.
+// Only providers with a non-zero cost count against the budget.
+type BudgetConfig struct {
+	Path         string  `json:"path"` // default: tts-budget.json next to the config file
+	DailyChars   int     `json:"daily_chars"`
+	MonthlyChars int     `json:"monthly_chars"`
+	DailyUSD     float64 `json:"daily_usd"`
+	MonthlyUSD   float64 `json:"monthly_usd"`
+	WarnPercent  int     `json:"warn_percent"` // share of a cap that logs a warning, default 80
+	// Costs is the price in USD per million characters, keyed by provider
+	// name or model; entries override the built-in table
+	Costs map[string]float64 `json:"costs,omitempty"`
+}
+
+// StreamingConfig holds the low-latency playback settings
+type StreamingConfig struct {
+	Disabled bool `json:"disabled"`
+	// NoFirstSentence synthesizes the start of the text in one request
+	// instead of splitting off the first sentence
+	NoFirstSentence bool `json:"no_first_sentence"`
+}
+
+// CacheConfig holds the audio cache settings; zero values use the defaults
+type CacheConfig struct {
+	Disabled bool   `json:"disabled"`
+	Dir      string `json:"dir"`       // default: <user cache dir>/claude-code-tts/audio
+	MaxMB    int    `json:"max_mb"`    // total size before least recently used entries are evicted
+	TTLHours int    `json:"ttl_hours"` // age after which entries are re-synthesized
+}
+
+// BreakerConfig holds the circuit breaker settings; zero values use the defaults
+type BreakerConfig struct {
+	FailureThreshold int `json:"failure_threshold"` // consecutive failures that trip the breaker
+	CooldownMS       int `json:"cooldown_ms"`       // how long a tripped provider is skipped
+}
+
+// RetryConfig holds the retry policy settings; zero values use the defaults
+type RetryConfig struct {
+	MaxAttempts int `json:"max_attempts"`  // total tries per request, 1 disables retries
+	BaseDelayMS int `json:"base_delay_ms"` // first backoff delay
+	MaxDelayMS  int `json:"max_delay_ms"`  // backoff cap; longer Retry-After waits give up
+}
+
+// ProviderConfig holds the settings of a single provider instance.
+// Only the type is known here; each provider decodes its own settings.
+type ProviderConfig struct {
+	Name string `json:"-"`
+	Type string `json:"type"`
+	raw  json.RawMessage
+}
+
+// UnmarshalJSON keeps the raw object so providers can decode their settings
+func (p *ProviderConfig) UnmarshalJSON(data []byte) error {
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return err
+	}
+	p.Type = head.Type
+	p.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON writes back the raw settings
+func (p ProviderConfig) MarshalJSON() ([]byte, error) {
+	if len(p.raw) == 0 {
+		return json.Marshal(map[string]string{"type": p.Type})
+	}
+	return p.raw, nil
+}
+
+// Decode unmarshals the provider settings into v.
+// It is a no-op when the provider has no settings.
+func (p ProviderConfig) Decode(v interface{}) error {
+	if len(p.raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(p.raw, v); err != nil {
+		return fmt.Errorf("invalid settings for provider %q: %w", p.Name, err)
+	}
+	return nil
+}
+
+// Default returns the configuration used when no file is present
+func Default() *Config {
+	return &Config{
+		Provider:  DefaultProvider,
+		Providers: make(map[string]ProviderConfig),
+	}
+}
+
+// Path returns the location of the config file.
+// CLAUDE_TTS_CONFIG overrides the default ~/.claude/tts.json.
+func Path() string {
+	if p := os.Getenv("CLAUDE_TTS_CONFIG"); p != "" {
+		return p
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "tts.json"
+	}
+	return filepath.Join(homeDir, ".claude", "tts.json")
+}
+
+// ProjectDir returns the project Claude Code runs in (CLAUDE_PROJECT_DIR),
+// or else the working directory
+func ProjectDir() string {
+	if dir := os.Getenv("CLAUDE_PROJECT_DIR"); dir != "" {
+		return dir
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+// Load reads the config file and applies environment overrides.
+// A missing file is not an error; defaults are used instead.
+func Load() (*Config, error) {
+	cfg, err := LoadFile(Path())
+	if err != nil {
+		return nil, err
+	}
+	cfg.applyEnv()
+	return cfg, nil
+}
+
+// LoadFile reads the config from the given path
+func LoadFile(path string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = DefaultProvider
+	}
+	if cfg.Providers == nil {
+		cfg.Providers = make(map[string]ProviderConfig)
+	}
+	return cfg, nil
+}
+
+// applyEnv lets environment variables override file settings
+func (c *Config) applyEnv() {
+	if p := os.Getenv("TTS_PROVIDER"); p != "" {
+		c.Provider = p
+	}
+}
+
+// Lookup returns the settings of the named provider.
+// Names without an entry resolve to a provider type of the same name.
+func (c *Config) Lookup(name string) ProviderConfig {
+	pc, ok := c.Providers[name]
+	if !ok {
+		pc = ProviderConfig{Type: name}
+	}
+	if pc.Type == "" {
+		pc.Type = name
+	}
+	pc.Name = name
+	return pc
+}
+
+// ExpandPath replaces a leading ~ with the user's home directory
+func ExpandPath(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, strings.TrimPrefix(path, "~"))
+}
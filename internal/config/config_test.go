@@ -0,0 +1,166 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	cfg := Default()
+
+	if cfg.Provider != "openai" {
+		t.Errorf("expected default provider 'openai', got %q", cfg.Provider)
+	}
+	if cfg.Providers == nil {
+		t.Error("expected Providers map to be initialized")
+	}
+}
+
+func TestLoadFile_Missing(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Provider != DefaultProvider {
+		t.Errorf("expected default provider, got %q", cfg.Provider)
+	}
+}
+
+func TestLoadFile_Providers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tts.json")
+	data := `{
+		"provider": "local",
+		"providers": {
+			"local": {"type": "openai", "model": "tts-1-hd"}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Provider != "local" {
+		t.Errorf("expected provider 'local', got %q", cfg.Provider)
+	}
+
+	pc := cfg.Lookup("local")
+	if pc.Name != "local" || pc.Type != "openai" {
+		t.Errorf("unexpected provider config: name=%q type=%q", pc.Name, pc.Type)
+	}
+
+	var settings struct {
+		Model string `json:"model"`
+	}
+	if err := pc.Decode(&settings); err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	if settings.Model != "tts-1-hd" {
+		t.Errorf("expected model 'tts-1-hd', got %q", settings.Model)
+	}
+}
+
+func TestLoadFile_FallbacksAndPolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tts.json")
+	data := `{
+		"provider": "openai",
+		"fallbacks": ["kokoro", "espeak"],
+		"retry": {"max_attempts": 2},
+		"breaker": {"failure_threshold": 5, "cooldown_ms": 60000},
+		"cache": {"max_mb": 20, "ttl_hours": 24},
+		"streaming": {"disabled": true, "no_first_sentence": true},
+		"rate_limit": {"requests_per_minute": 30},
+		"budget": {"daily_chars": 50000, "monthly_usd": 10, "costs": {"kokoro": 0}}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Fallbacks) != 2 || cfg.Fallbacks[0] != "kokoro" || cfg.Fallbacks[1] != "espeak" {
+		t.Errorf("unexpected fallbacks %v", cfg.Fallbacks)
+	}
+	if cfg.Retry.MaxAttempts != 2 {
+		t.Errorf("expected retry max_attempts 2, got %d", cfg.Retry.MaxAttempts)
+	}
+	if cfg.Breaker.FailureThreshold != 5 || cfg.Breaker.CooldownMS != 60000 {
+		t.Errorf("unexpected breaker config %+v", cfg.Breaker)
+	}
+	if cfg.Cache.Disabled || cfg.Cache.MaxMB != 20 || cfg.Cache.TTLHours != 24 {
+		t.Errorf("unexpected cache config %+v", cfg.Cache)
+	}
+	if !cfg.Streaming.Disabled || !cfg.Streaming.NoFirstSentence {
+		t.Errorf("unexpected streaming config %+v", cfg.Streaming)
+	}
+	if cfg.RateLimit.RequestsPerMinute != 30 || cfg.RateLimit.CharsPerMinute != 0 {
+		t.Errorf("unexpected rate limit config %+v", cfg.RateLimit)
+	}
+	if cost, ok := cfg.Budget.Costs["kokoro"]; cfg.Budget.DailyChars != 50000 || cfg.Budget.MonthlyUSD != 10 || !ok || cost != 0 {
+		t.Errorf("unexpected budget config %+v", cfg.Budget)
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tts.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestLookup_UnknownNameIsType(t *testing.T) {
+	cfg := Default()
+
+	pc := cfg.Lookup("openai")
+	if pc.Type != "openai" || pc.Name != "openai" {
+		t.Errorf("expected name and type 'openai', got name=%q type=%q", pc.Name, pc.Type)
+	}
+	if err := pc.Decode(&struct{}{}); err != nil {
+		t.Errorf("expected no-op decode, got %v", err)
+	}
+}
+
+func TestLoad_EnvOverrides(t *testing.T) {
+	t.Setenv("CLAUDE_TTS_CONFIG", filepath.Join(t.TempDir(), "tts.json"))
+	t.Setenv("TTS_PROVIDER", "piper")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Provider != "piper" {
+		t.Errorf("expected TTS_PROVIDER override 'piper', got %q", cfg.Provider)
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"~/voices/en.onnx", filepath.Join(home, "voices", "en.onnx")},
+		{"~", home},
+		{"/abs/path", "/abs/path"},
+		{"relative/~path", "relative/~path"},
+	}
+
+	for _, tt := range tests {
+		if got := ExpandPath(tt.in); got != tt.want {
+			t.Errorf("ExpandPath(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package language
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// minLetters is the fewest letters of Latin-script text Detect tells the
+// language of; shorter sentences share too many trigrams across languages
+const minLetters = 12
+
+// minMargin is how much more likely per trigram, in nats, the best
+// language must be than the next one
+const minMargin = 0.1
+
+// minLead is how much more likely, in nats, the best language must be
+// than the next one over the whole text, so a short status line like
+// "Tests pass now." is not taken for French on a few shared trigrams
+const minLead = 4.5
+
+// scripts are the writing systems that identify a language on their own
+var scripts = []struct {
+	table *unicode.RangeTable
+	lang  string
+}{
+	{unicode.Hangul, "ko"},
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+}
+
+// ukrainian are the Cyrillic letters Ukrainian has and Russian does not
+const ukrainian = "іїєґІЇЄҐ"
+
+// profile counts the trigrams of one language's sample text
+type profile struct {
+	counts map[string]int
+	total  int
+}
+
+var (
+	profiles   = make(map[string]*profile)
+	vocabulary int // distinct trigrams over all profiles, for smoothing
+)
+
+func init() {
+	seen := make(map[string]bool)
+	for lang, text := range samples {
+		p := &profile{counts: make(map[string]int)}
+		for _, t := range trigrams(words(text)) {
+			p.counts[t]++
+			p.total++
+			seen[t] = true
+		}
+		profiles[lang] = p
+	}
+	vocabulary = len(seen) + 1
+}
+
+// Languages returns the ISO 639-1 codes Detect can return, sorted
+func Languages() []string {
+	set := map[string]bool{"uk": true}
+	for _, s := range scripts {
+		set[s.lang] = true
+	}
+	for lang := range profiles {
+		set[lang] = true
+	}
+	langs := make([]string, 0, len(set))
+	for lang := range set {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Detect returns the ISO 639-1 code of the language text is written in,
+// or "" if it is too short to tell or no language is clearly ahead.
+// Text in a non-Latin script is recognized by its script; Latin-script
+// text by its letter trigrams. Inline code, URLs and identifiers are
+// ignored.
+func Detect(text string) string {
+	ws := words(text)
+
+	counts := make(map[string]int)
+	latin := 0
+	for _, w := range ws {
+		for _, r := range w {
+			if unicode.Is(unicode.Latin, r) {
+				latin++
+				continue
+			}
+			for _, s := range scripts {
+				if unicode.Is(s.table, r) {
+					counts[s.lang]++
+					break
+				}
+			}
+		}
+	}
+	// Japanese mixes kana with Chinese characters
+	if counts["ja"] > 0 {
+		counts["ja"] += counts["zh"]
+		delete(counts, "zh")
+	}
+
+	best, most := "", 0
+	for lang, n := range counts {
+		if n > most || n == most && lang < best {
+			best, most = lang, n
+		}
+	}
+	if most > latin {
+		if best == "ru" && strings.ContainsAny(text, ukrainian) {
+			return "uk"
+		}
+		return best
+	}
+	if latin < minLetters {
+		return ""
+	}
+	return detectLatin(trigrams(ws))
+}
+
+// detectLatin returns the language whose profile makes ts most likely,
+// if it is clearly ahead of the next one
+func detectLatin(ts []string) string {
+	type score struct {
+		lang string
+		p    float64
+	}
+	scores := make([]score, 0, len(profiles))
+	for lang, p := range profiles {
+		s := score{lang: lang}
+		for _, t := range ts {
+			s.p += math.Log(float64(p.counts[t]+1) / float64(p.total+vocabulary))
+		}
+		scores = append(scores, s)
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].p != scores[j].p {
+			return scores[i].p > scores[j].p
+		}
+		return scores[i].lang < scores[j].lang
+	})
+	lead := scores[0].p - scores[1].p
+	if len(scores) < 2 || lead < minLead || lead/float64(len(ts)) < minMargin {
+		return ""
+	}
+	return scores[0].lang
+}
+
+// skipped is text that is in no natural language: inline code and URLs
+var skipped = regexp.MustCompile("`[^`\n]*`|\\b[A-Za-z][A-Za-z0-9+.-]*://\\S+")
+
+// words returns the lower case words of the prose in text, split at
+// apostrophes and hyphens, without identifiers, paths and numbers
+func words(text string) []string {
+	var out []string
+	for _, f := range strings.Fields(skipped.ReplaceAllString(text, " ")) {
+		f = strings.TrimFunc(f, func(r rune) bool { return !unicode.IsLetter(r) })
+		if f == "" || isCode(f) {
+			continue
+		}
+		for _, w := range strings.FieldsFunc(f, isJoiner) {
+			out = append(out, strings.ToLower(w))
+		}
+	}
+	return out
+}
+
+// isJoiner reports whether r joins the parts of a word, as in "l'erreur"
+// or "est-ce"
+func isJoiner(r rune) bool {
+	return r == '\'' || r == '’' || r == '-'
+}
+
+// isCode reports whether word is an identifier, path or number: it has
+// other characters than letters, or an upper case letter after a lower
+// case one
+func isCode(word string) bool {
+	lower := false
+	for _, r := range word {
+		if !unicode.IsLetter(r) && !isJoiner(r) || unicode.IsUpper(r) && lower {
+			return true
+		}
+		lower = unicode.IsLower(r)
+	}
+	return false
+}
+
+// trigrams returns the three letter sequences of ws, each word padded
+// with spaces so its first and last letters count
+func trigrams(ws []string) []string {
+	var ts []string
+	for _, w := range ws {
+		r := []rune(" " + w + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			ts = append(ts, string(r[i:i+3]))
+		}
+	}
+	return ts
+}
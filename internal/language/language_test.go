@@ -0,0 +1,68 @@
+package language
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Fixed the race condition in the worker pool.", "en"},
+		{"Ready for review.", "en"},
+		{"J'ai corrigé la condition de course dans le pool de workers.", "fr"},
+		{"Le test TestHandleSpeak échoue sur la branche main.", "fr"},
+		{"Attention, la fonction `splitCamel` ne gère pas les acronymes.", "fr"},
+		{"Voir https://example.com/docs/getting-started pour les détails.", "fr"},
+		{"Quiero agregar una prueba para este caso.", "es"},
+		{"Wir müssen noch einen Test für diesen Fall schreiben.", "de"},
+		{"Dobbiamo aggiungere un test per questo caso.", "it"},
+		{"Precisamos adicionar um teste para este caso.", "pt"},
+		{"We moeten nog een test voor dit geval toevoegen.", "nl"},
+		{"Привет, как дела?", "ru"},
+		{"Привіт, як справи?", "uk"},
+		{"これはテストです", "ja"},
+		{"这是一个测试", "zh"},
+		{"테스트가 통과했습니다", "ko"},
+		{"Build succeeded.", ""},
+		{"C'est bon.", ""},
+		{"Tests pass now.", ""},
+		{"Linter passes cleanly.", ""},
+		{"Installation terminée.", ""},
+		{"internal/server/worker.go:142 TestHandleSpeak", ""},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.text); got != tt.want {
+			t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	text := "The build is green. Done.\n" +
+		"Petite remarque : la fonction de relecture ne gère pas les fichiers vides. Merci !\n" +
+		"```go\nfunc main() {}\n```\n" +
+		"Otherwise the change looks good to me."
+	want := []Segment{
+		{Language: "en", Text: "The build is green. Done."},
+		{Language: "fr", Text: "Petite remarque : la fonction de relecture ne gère pas les fichiers vides. Merci !\n```go\nfunc main() {}\n```"},
+		{Language: "en", Text: "Otherwise the change looks good to me."},
+	}
+	if got := Split(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("Split(%q)\n got %q\nwant %q", text, got, want)
+	}
+}
+
+func TestSplit_SingleLanguage(t *testing.T) {
+	if got := Split("OK. Les tests passent maintenant."); !reflect.DeepEqual(got, []Segment{{Language: "fr", Text: "OK. Les tests passent maintenant."}}) {
+		t.Errorf("expected one French segment, got %q", got)
+	}
+	if got := Split("OK."); !reflect.DeepEqual(got, []Segment{{Text: "OK."}}) {
+		t.Errorf("expected one undetected segment, got %q", got)
+	}
+	if got := Split("  "); len(got) != 0 {
+		t.Errorf("expected no segments for blank text, got %q", got)
+	}
+}
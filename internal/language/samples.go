@@ -0,0 +1,116 @@
+package language
+
+// samples are the texts the trigram profiles of the Latin-script languages
+// are built from: everyday prose mixed with the way developers talk about
+// their work, so review comments and build summaries score well
+var samples = map[string]string{
+	"en": `The build passed on the second try after I fixed the flaky test.
+I think we should rename this function, the current name does not say what it does.
+Could you take a look at the pull request when you have a moment? The change is small.
+This breaks the public interface, so we need to bump the major version before the release.
+The tests are failing because the mock returns an empty list instead of an error.
+Nice catch, thanks for the review. I will update the documentation and push again.
+We should not merge this yet, there are still some open questions about the design.
+The deployment finished without errors and the service is healthy again.
+It would be simpler to read the value once at startup and keep it in memory.
+What happens when the file does not exist? We should handle that case and return a clear message.
+I left a few comments, mostly about naming, but the overall approach looks good to me.
+The query is slow because it scans the whole table, we need an index on the user column.
+Everything is working now, let me know if you want me to change anything else.
+She said that they would come back tomorrow morning with the rest of the results.
+There were three people waiting outside, and nobody knew which door was the right one.
+This is only a first draft, feel free to suggest other ideas or a different approach.`,
+
+	"fr": `Le build est passé au deuxième essai après la correction du test instable.
+Je pense qu'il faudrait renommer cette fonction, le nom actuel ne dit pas ce qu'elle fait.
+Est-ce que tu peux regarder la pull request quand tu as un moment ? Le changement est petit.
+Cela casse l'interface publique, donc il faut passer à la version majeure avant la sortie.
+Les tests échouent parce que le mock renvoie une liste vide au lieu d'une erreur.
+Bien vu, merci pour la relecture. Je vais mettre à jour la documentation et pousser à nouveau.
+Il ne faut pas encore fusionner, il reste des questions ouvertes sur la conception.
+Le déploiement s'est terminé sans erreur et le service fonctionne de nouveau.
+Ce serait plus simple de lire la valeur une seule fois au démarrage et de la garder en mémoire.
+Que se passe-t-il quand le fichier n'existe pas ? Il faut gérer ce cas et renvoyer un message clair.
+J'ai laissé quelques commentaires, surtout sur les noms, mais l'approche générale me semble bonne.
+La requête est lente parce qu'elle parcourt toute la table, il nous faut un index sur la colonne des utilisateurs.
+Tout fonctionne maintenant, dis-moi si tu veux que je change autre chose.
+Elle a dit qu'ils reviendraient demain matin avec le reste des résultats.
+Il y avait trois personnes qui attendaient dehors, et personne ne savait quelle était la bonne porte.
+Ce n'est qu'un premier brouillon, n'hésite pas à proposer d'autres idées ou une approche différente.
+C'est corrigé, la branche est à jour et les vérifications sont toutes au vert.`,
+
+	"es": `La compilación pasó en el segundo intento después de corregir la prueba inestable.
+Creo que deberíamos cambiar el nombre de esta función, el nombre actual no dice lo que hace.
+¿Puedes revisar la solicitud de cambios cuando tengas un momento? El cambio es pequeño.
+Esto rompe la interfaz pública, así que hay que subir la versión mayor antes del lanzamiento.
+Las pruebas fallan porque el simulacro devuelve una lista vacía en lugar de un error.
+Buena observación, gracias por la revisión. Voy a actualizar la documentación y subirlo otra vez.
+Todavía no deberíamos fusionar esto, quedan algunas preguntas abiertas sobre el diseño.
+El despliegue terminó sin errores y el servicio vuelve a funcionar bien.
+Sería más sencillo leer el valor una sola vez al arrancar y guardarlo en memoria.
+¿Qué pasa cuando el archivo no existe? Hay que manejar ese caso y devolver un mensaje claro.
+Dejé algunos comentarios, sobre todo de nombres, pero el enfoque general me parece bien.
+La consulta es lenta porque recorre toda la tabla, necesitamos un índice en la columna de usuarios.
+Ahora todo funciona, avísame si quieres que cambie algo más.
+Ella dijo que volverían mañana por la mañana con el resto de los resultados.`,
+
+	"de": `Der Build ist beim zweiten Versuch durchgelaufen, nachdem ich den wackeligen Test repariert habe.
+Ich denke, wir sollten diese Funktion umbenennen, der aktuelle Name sagt nicht, was sie tut.
+Kannst du dir den Pull Request ansehen, wenn du Zeit hast? Die Änderung ist klein.
+Das bricht die öffentliche Schnittstelle, also müssen wir vor der Veröffentlichung die Hauptversion erhöhen.
+Die Tests schlagen fehl, weil das Mock eine leere Liste statt eines Fehlers zurückgibt.
+Guter Fund, danke für das Review. Ich aktualisiere die Dokumentation und pushe noch einmal.
+Wir sollten das noch nicht mergen, es gibt noch offene Fragen zum Entwurf.
+Das Deployment ist ohne Fehler fertig geworden und der Dienst läuft wieder.
+Es wäre einfacher, den Wert einmal beim Start zu lesen und im Speicher zu behalten.
+Was passiert, wenn die Datei nicht existiert? Wir sollten diesen Fall behandeln und eine klare Meldung zurückgeben.
+Ich habe ein paar Kommentare hinterlassen, vor allem zur Benennung, aber der Ansatz gefällt mir.
+Die Abfrage ist langsam, weil sie die ganze Tabelle durchsucht, wir brauchen einen Index auf der Spalte.
+Jetzt funktioniert alles, sag mir Bescheid, wenn ich noch etwas ändern soll.
+Sie sagte, dass sie morgen früh mit dem Rest der Ergebnisse zurückkommen würden.`,
+
+	"it": `La build è passata al secondo tentativo dopo che ho sistemato il test instabile.
+Penso che dovremmo rinominare questa funzione, il nome attuale non dice cosa fa.
+Puoi dare un'occhiata alla pull request quando hai un momento? La modifica è piccola.
+Questo rompe l'interfaccia pubblica, quindi dobbiamo aumentare la versione principale prima del rilascio.
+I test falliscono perché il mock restituisce una lista vuota invece di un errore.
+Ottima osservazione, grazie per la revisione. Aggiorno la documentazione e faccio di nuovo il push.
+Non dovremmo ancora unire questo, ci sono ancora alcune domande aperte sul progetto.
+Il rilascio è terminato senza errori e il servizio funziona di nuovo.
+Sarebbe più semplice leggere il valore una sola volta all'avvio e tenerlo in memoria.
+Cosa succede quando il file non esiste? Dobbiamo gestire questo caso e restituire un messaggio chiaro.
+Ho lasciato qualche commento, soprattutto sui nomi, ma l'approccio generale mi sembra buono.
+La query è lenta perché scorre tutta la tabella, ci serve un indice sulla colonna degli utenti.
+Adesso funziona tutto, fammi sapere se vuoi che cambi qualcos'altro.
+Lei ha detto che sarebbero tornati domani mattina con il resto dei risultati.`,
+
+	"pt": `A compilação passou na segunda tentativa depois que eu corrigi o teste instável.
+Acho que deveríamos renomear esta função, o nome atual não diz o que ela faz.
+Você pode olhar o pull request quando tiver um tempo? A mudança é pequena.
+Isso quebra a interface pública, então precisamos aumentar a versão principal antes do lançamento.
+Os testes estão falhando porque o mock retorna uma lista vazia em vez de um erro.
+Bem observado, obrigado pela revisão. Vou atualizar a documentação e enviar de novo.
+Ainda não deveríamos juntar isso, ainda há algumas perguntas em aberto sobre o projeto.
+A implantação terminou sem erros e o serviço está funcionando de novo.
+Seria mais simples ler o valor uma única vez na inicialização e mantê-lo em memória.
+O que acontece quando o arquivo não existe? Precisamos tratar esse caso e devolver uma mensagem clara.
+Deixei alguns comentários, principalmente sobre nomes, mas a abordagem geral me parece boa.
+A consulta está lenta porque percorre a tabela inteira, precisamos de um índice na coluna de usuários.
+Agora tudo funciona, me avise se quiser que eu mude mais alguma coisa.
+Ela disse que eles voltariam amanhã de manhã com o resto dos resultados.`,
+
+	"nl": `De build slaagde bij de tweede poging nadat ik de onbetrouwbare test had gerepareerd.
+Ik denk dat we deze functie moeten hernoemen, de huidige naam zegt niet wat ze doet.
+Kun je naar de pull request kijken als je even tijd hebt? De wijziging is klein.
+Dit breekt de publieke interface, dus we moeten de hoofdversie verhogen voor de release.
+De tests falen omdat de mock een lege lijst teruggeeft in plaats van een fout.
+Goed gezien, bedankt voor de review. Ik werk de documentatie bij en push het opnieuw.
+We moeten dit nog niet mergen, er zijn nog een paar open vragen over het ontwerp.
+De uitrol is zonder fouten klaar en de dienst werkt weer.
+Het zou eenvoudiger zijn om de waarde één keer bij het opstarten te lezen en in het geheugen te houden.
+Wat gebeurt er als het bestand niet bestaat? We moeten dat geval afhandelen en een duidelijke melding geven.
+Ik heb een paar opmerkingen achtergelaten, vooral over namen, maar de aanpak ziet er goed uit.
+De query is traag omdat hij de hele tabel doorzoekt, we hebben een index op de kolom nodig.
+Nu werkt alles, laat het me weten als ik nog iets moet veranderen.
+Ze zei dat ze morgenochtend terug zouden komen met de rest van de resultaten.`,
+}
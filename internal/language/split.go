@@ -0,0 +1,96 @@
+package language
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Segment is a run of text in one language
+type Segment struct {
+	Language string // "" if it could not be detected
+	Text     string
+}
+
+// Split splits text that mixes languages into runs of sentences in the
+// same language. Sentences too short to tell and code blocks join the run
+// before them, or the first run. Text in a single language yields one
+// segment.
+func Split(text string) []Segment {
+	var segments []Segment
+	pending := "" // text before the first sentence detected
+	for _, u := range units(text) {
+		lang := ""
+		if !u.code {
+			lang = Detect(u.text)
+		}
+		switch {
+		case len(segments) == 0 && lang == "":
+			pending += u.text
+		case len(segments) > 0 && (lang == "" || lang == segments[len(segments)-1].Language):
+			segments[len(segments)-1].Text += u.text
+		default:
+			segments = append(segments, Segment{Language: lang, Text: pending + u.text})
+			pending = ""
+		}
+	}
+	if len(segments) == 0 {
+		segments = []Segment{{Text: pending}}
+	}
+
+	out := segments[:0]
+	for _, s := range segments {
+		if s.Text = strings.TrimSpace(s.Text); s.Text != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// unit is a sentence, a line or a fenced code block
+type unit struct {
+	text string
+	code bool
+}
+
+// fence matches the opening line of a fenced code block
+var fence = regexp.MustCompile("^\\s{0,3}(`{3,}|~{3,})")
+
+// sentenceEnd matches the end of a sentence and the space after it
+var sentenceEnd = regexp.MustCompile(`[.!?…]+["'»)\]]*\s+`)
+
+// units splits text into sentences, lines and code blocks, each with the
+// space that follows it, so they add up to text
+func units(text string) []unit {
+	var out []unit
+	lines := strings.SplitAfter(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		m := fence.FindStringSubmatch(lines[i])
+		if m == nil {
+			out = append(out, sentences(lines[i])...)
+			continue
+		}
+		block := lines[i]
+		for i++; i < len(lines); i++ {
+			block += lines[i]
+			if strings.HasPrefix(strings.TrimSpace(lines[i]), m[1]) {
+				break
+			}
+		}
+		out = append(out, unit{text: block, code: true})
+	}
+	return out
+}
+
+// sentences splits a line after each sentence end
+func sentences(line string) []unit {
+	var out []unit
+	last := 0
+	for _, loc := range sentenceEnd.FindAllStringIndex(line, -1) {
+		out = append(out, unit{text: line[last:loc[1]]})
+		last = loc[1]
+	}
+	if last < len(line) {
+		out = append(out, unit{text: line[last:]})
+	}
+	return out
+}
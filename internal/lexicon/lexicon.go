@@ -0,0 +1,331 @@
+package lexicon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+	"github.com/ybouhjira/claude-code-tts/internal/tts"
+)
+
+// Lexicon scopes
+const (
+	Global  = "global"
+	Project = "project"
+)
+
+// ProjectFile is the lexicon of a project, relative to its directory
+const ProjectFile = ".claude/tts-lexicon.json"
+
+// Entry is one substitution. A literal match is a whole word or phrase;
+// a regex match may use $1 in the replacement.
+type Entry struct {
+	Match         string `json:"match"`
+	Replacement   string `json:"replacement"`
+	Regex         bool   `json:"regex,omitempty"`
+	CaseSensitive bool   `json:"case_sensitive,omitempty"`
+}
+
+// ScopedEntry is an entry and the file it comes from
+type ScopedEntry struct {
+	Entry
+	Scope string `json:"scope"`
+}
+
+// compile returns the regular expression matching e
+func (e Entry) compile() (*regexp.Regexp, error) {
+	if e.Match == "" {
+		return nil, errors.New("match is required")
+	}
+	pattern := e.Match
+	if !e.Regex {
+		pattern = regexp.QuoteMeta(e.Match)
+		// Only anchor at word characters, so "C++" and ".NET" still match
+		if first, _ := utf8.DecodeRuneInString(e.Match); isWordChar(first) {
+			pattern = `\b` + pattern
+		}
+		if last, _ := utf8.DecodeLastRuneInString(e.Match); isWordChar(last) {
+			pattern += `\b`
+		}
+	}
+	if !e.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", e.Match, err)
+	}
+	return re, nil
+}
+
+// isWordChar reports whether r is matched by \w
+func isWordChar(r rune) bool {
+	return r == '_' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
+
+// rule is a compiled entry
+type rule struct {
+	Entry
+	re *regexp.Regexp
+}
+
+// apply replaces the matches of r in text, passing the replacement
+// through mark if it is not nil
+func (r rule) apply(text string, mark func(string) string) string {
+	replacement := r.Replacement
+	if mark != nil {
+		replacement = mark(replacement)
+	}
+	if r.Regex {
+		return r.re.ReplaceAllString(text, replacement)
+	}
+	return r.re.ReplaceAllLiteralString(text, replacement)
+}
+
+// file is the entries of one lexicon file
+type file struct {
+	path  string
+	rules []rule
+}
+
+// Lexicon rewrites words the voices mispronounce, e.g. kubectl to
+// "kube control". Project entries are applied before global ones and
+// replace global entries with the same match.
+type Lexicon struct {
+	mu    sync.RWMutex
+	files map[string]*file
+}
+
+// DefaultPath returns tts-lexicon.json next to the config file
+func DefaultPath() string {
+	return filepath.Join(filepath.Dir(config.Path()), "tts-lexicon.json")
+}
+
+// Load reads the global lexicon and, if projectDir is not empty, the
+// project's. Missing files are empty. It returns nil if the lexicon is
+// disabled.
+func Load(cfg config.LexiconConfig, projectDir string) (*Lexicon, error) {
+	if cfg.Disabled {
+		return nil, nil
+	}
+	path := DefaultPath()
+	if cfg.Path != "" {
+		path = config.ExpandPath(cfg.Path)
+	}
+
+	l := &Lexicon{files: make(map[string]*file)}
+	paths := map[string]string{Global: path}
+	if projectDir != "" {
+		paths[Project] = filepath.Join(projectDir, ProjectFile)
+	}
+	for scope, path := range paths {
+		f, err := loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		l.files[scope] = f
+	}
+	return l, nil
+}
+
+// loadFile reads and compiles a lexicon file
+func loadFile(path string) (*file, error) {
+	f := &file{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lexicon: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse lexicon %s: %w", path, err)
+	}
+	for _, e := range entries {
+		re, err := e.compile()
+		if err != nil {
+			return nil, fmt.Errorf("lexicon %s: %w", path, err)
+		}
+		f.rules = append(f.rules, rule{Entry: e, re: re})
+	}
+	return f, nil
+}
+
+// Apply rewrites text with every entry, project entries first.
+// SSML is returned unchanged, since a pattern could match inside a tag.
+func (l *Lexicon) Apply(text string) string {
+	return l.ApplyMarked(text, nil)
+}
+
+// ApplyMarked is Apply with every replacement passed through mark, e.g.
+// normalize.Protect so normalizing the text leaves the replacements as
+// they are
+func (l *Lexicon) ApplyMarked(text string, mark func(string) string) string {
+	if tts.IsSSML(text) {
+		return text
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	overridden := make(map[string]bool)
+	if f := l.files[Project]; f != nil {
+		for _, r := range f.rules {
+			text = r.apply(text, mark)
+			overridden[r.Match] = true
+		}
+	}
+	for _, r := range l.files[Global].rules {
+		if !overridden[r.Match] {
+			text = r.apply(text, mark)
+		}
+	}
+	return text
+}
+
+// List returns every entry, project entries first
+func (l *Lexicon) List() []ScopedEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]ScopedEntry, 0)
+	for _, scope := range []string{Project, Global} {
+		if f := l.files[scope]; f != nil {
+			for _, r := range f.rules {
+				entries = append(entries, ScopedEntry{Entry: r.Entry, Scope: scope})
+			}
+		}
+	}
+	return entries
+}
+
+// Add adds e to the lexicon of scope and saves it, replacing an entry
+// with the same match
+func (l *Lexicon) Add(scope string, e Entry) error {
+	re, err := e.compile()
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := l.file(scope)
+	if err != nil {
+		return err
+	}
+	rules := make([]rule, 0, len(f.rules)+1)
+	for _, r := range f.rules {
+		if r.Match != e.Match {
+			rules = append(rules, r)
+		}
+	}
+	rules = append(rules, rule{Entry: e, re: re})
+	if err := save(f.path, rules); err != nil {
+		return err
+	}
+	f.rules = rules
+	return nil
+}
+
+// Remove deletes the entry with the given match from the lexicon of scope,
+// or from both if scope is empty. It reports whether an entry was removed.
+func (l *Lexicon) Remove(scope, match string) (bool, error) {
+	scopes := []string{scope}
+	if scope == "" {
+		scopes = []string{Project, Global}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	removed := false
+	for _, scope := range scopes {
+		f, err := l.file(scope)
+		if err != nil {
+			if len(scopes) > 1 {
+				continue
+			}
+			return false, err
+		}
+		rules := make([]rule, 0, len(f.rules))
+		for _, r := range f.rules {
+			if r.Match != match {
+				rules = append(rules, r)
+			}
+		}
+		if len(rules) == len(f.rules) {
+			continue
+		}
+		if err := save(f.path, rules); err != nil {
+			return removed, err
+		}
+		f.rules = rules
+		removed = true
+	}
+	return removed, nil
+}
+
+// Path returns the file of scope, or "" if there is none
+func (l *Lexicon) Path(scope string) string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if f := l.files[scope]; f != nil {
+		return f.path
+	}
+	return ""
+}
+
+// file returns the file of scope
+func (l *Lexicon) file(scope string) (*file, error) {
+	switch scope {
+	case Global, Project:
+	default:
+		return nil, fmt.Errorf("unknown scope %q (use global or project)", scope)
+	}
+	f := l.files[scope]
+	if f == nil {
+		return nil, fmt.Errorf("no %s lexicon: the project directory is unknown", scope)
+	}
+	return f, nil
+}
+
+// save writes rules to path atomically
+func save(path string, rules []rule) error {
+	entries := make([]Entry, 0, len(rules))
+	for _, r := range rules {
+		entries = append(entries, r.Entry)
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to save lexicon: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tts-lexicon-*")
+	if err != nil {
+		return fmt.Errorf("failed to save lexicon: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save lexicon: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save lexicon: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to save lexicon: %w", err)
+	}
+	return nil
+}
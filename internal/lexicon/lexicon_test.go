@@ -0,0 +1,157 @@
+package lexicon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// testLexicon returns a lexicon with its global file and project in
+// temp dirs
+func testLexicon(t *testing.T) (*Lexicon, string) {
+	t.Helper()
+	project := t.TempDir()
+	l, err := Load(config.LexiconConfig{Path: filepath.Join(t.TempDir(), "tts-lexicon.json")}, project)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return l, project
+}
+
+func TestLoad_Disabled(t *testing.T) {
+	l, err := Load(config.LexiconConfig{Disabled: true}, "")
+	if l != nil || err != nil {
+		t.Errorf("expected no lexicon, got %v, %v", l, err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	l, _ := testLexicon(t)
+	for _, e := range []Entry{
+		{Match: "kubectl", Replacement: "kube control"},
+		{Match: "nginx", Replacement: "engine x"},
+		{Match: "SQL", Replacement: "sequel", CaseSensitive: true},
+		{Match: "C++", Replacement: "C plus plus"},
+		{Match: `\bk8s\b`, Replacement: "kubernetes", Regex: true},
+		{Match: `\bPR #(\d+)`, Replacement: "pull request $1", Regex: true},
+	} {
+		if err := l.Add(Global, e); err != nil {
+			t.Fatalf("Add(%q): %v", e.Match, err)
+		}
+	}
+
+	tests := []struct {
+		in, want string
+	}{
+		{"Run kubectl apply", "Run kube control apply"},
+		{"Kubectl and NGINX", "kube control and engine x"},
+		{"kubectlx is not a match", "kubectlx is not a match"},
+		{"SQL but not sql", "sequel but not sql"},
+		{"Rewritten in C++.", "Rewritten in C plus plus."},
+		{"k8s cluster, PR #42 merged", "kubernetes cluster, pull request 42 merged"},
+		{"<speak>kubectl</speak>", "<speak>kubectl</speak>"},
+	}
+	for _, tt := range tests {
+		if got := l.Apply(tt.in); got != tt.want {
+			t.Errorf("Apply(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestApplyMarked(t *testing.T) {
+	l, _ := testLexicon(t)
+	l.Add(Global, Entry{Match: "kubectl", Replacement: "kube control"})
+	l.Add(Global, Entry{Match: `\bPR #(\d+)`, Replacement: "pull request $1", Regex: true})
+
+	mark := func(s string) string { return "[" + s + "]" }
+	if got := l.ApplyMarked("kubectl for PR #7", mark); got != "[kube control] for [pull request 7]" {
+		t.Errorf("unexpected %q", got)
+	}
+}
+
+func TestProjectOverridesGlobal(t *testing.T) {
+	l, project := testLexicon(t)
+	l.Add(Global, Entry{Match: "atlas", Replacement: "at las"})
+	l.Add(Global, Entry{Match: "nginx", Replacement: "engine x"})
+	l.Add(Project, Entry{Match: "atlas", Replacement: "the Atlas service"})
+
+	if got := l.Apply("atlas behind nginx"); got != "the Atlas service behind engine x" {
+		t.Errorf("unexpected %q", got)
+	}
+
+	list := l.List()
+	if len(list) != 3 || list[0].Scope != Project || list[0].Replacement != "the Atlas service" {
+		t.Errorf("unexpected list %+v", list)
+	}
+	if _, err := os.Stat(filepath.Join(project, ProjectFile)); err != nil {
+		t.Errorf("expected the project file to be written: %v", err)
+	}
+}
+
+func TestAddReplacesAndPersists(t *testing.T) {
+	l, project := testLexicon(t)
+	l.Add(Global, Entry{Match: "nginx", Replacement: "n g i n x"})
+	l.Add(Global, Entry{Match: "nginx", Replacement: "engine x"})
+
+	reloaded, err := Load(config.LexiconConfig{Path: l.Path(Global)}, project)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	list := reloaded.List()
+	if len(list) != 1 || list[0].Replacement != "engine x" {
+		t.Errorf("expected one replaced entry after reload, got %+v", list)
+	}
+}
+
+func TestAdd_Invalid(t *testing.T) {
+	l, _ := testLexicon(t)
+	if err := l.Add(Global, Entry{Match: "(unclosed", Regex: true}); err == nil {
+		t.Error("expected an error for an invalid regex")
+	}
+	if err := l.Add("team", Entry{Match: "x"}); err == nil || !strings.Contains(err.Error(), "unknown scope") {
+		t.Errorf("expected an unknown scope error, got %v", err)
+	}
+
+	noProject, err := Load(config.LexiconConfig{Path: l.Path(Global)}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := noProject.Add(Project, Entry{Match: "x"}); err == nil {
+		t.Error("expected an error without a project directory")
+	}
+}
+
+func TestRemove(t *testing.T) {
+	l, _ := testLexicon(t)
+	l.Add(Global, Entry{Match: "nginx", Replacement: "engine x"})
+	l.Add(Project, Entry{Match: "nginx", Replacement: "engine ex"})
+
+	removed, err := l.Remove(Project, "nginx")
+	if err != nil || !removed {
+		t.Fatalf("Remove = %v, %v", removed, err)
+	}
+	if got := l.Apply("nginx"); got != "engine x" {
+		t.Errorf("expected the global entry to apply again, got %q", got)
+	}
+
+	removed, _ = l.Remove("", "nginx")
+	if !removed || len(l.List()) != 0 {
+		t.Errorf("expected every entry removed, got %+v", l.List())
+	}
+	if removed, _ := l.Remove("", "nginx"); removed {
+		t.Error("expected nothing left to remove")
+	}
+}
+
+func TestLoad_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tts-lexicon.json")
+	if err := os.WriteFile(path, []byte(`[{"match": "[", "replacement": "x", "regex": true}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(config.LexiconConfig{Path: path}, ""); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
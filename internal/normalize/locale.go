@@ -0,0 +1,451 @@
+package normalize
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// locale says numbers, units and dates in one language
+type locale struct {
+	cardinal func(n int64) string
+	ordinal  func(n int64, suffix string) string
+	date     func(year int, month time.Month, day int) string
+	// clock says a time from the 24-hour clock, or from the 12-hour
+	// clock when it is not known to be AM or PM, if plain
+	clock func(hour, minute int, plain bool) string
+	// fraction says the digits after the decimal separator
+	fraction func(digits string) string
+	// singular reports whether a quantity takes the singular
+	singular func(whole, frac string) bool
+
+	decimal  string // runes separating the fraction
+	grouping string // runes grouping thousands
+	units    map[string]string
+	words    map[string]string // minus, point, percent, version, dot, line...
+	ordinals string            // ordinal suffixes, as a regexp
+
+	ordinalRe *regexp.Regexp
+	numberRe  *regexp.Regexp
+}
+
+// locales are the supported languages, by ISO 639-1 code
+var locales = map[string]*locale{
+	"en": english,
+	"fr": french,
+}
+
+// lookupLocale returns the locale of a tag like "en", "en-US" or "fr_CA"
+func lookupLocale(tag string) (*locale, error) {
+	if tag == "" {
+		tag = "en"
+	}
+	lang := strings.ToLower(strings.FieldsFunc(tag, func(r rune) bool { return r == '-' || r == '_' })[0])
+	l, ok := locales[lang]
+	if !ok {
+		return nil, fmt.Errorf("unsupported locale %q (use en or fr)", tag)
+	}
+	return l, nil
+}
+
+// number says s, a number written in l's notation
+func (l *locale) number(s string) string {
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac := s, ""
+	if i := strings.LastIndexAny(s, l.decimal); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	whole = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(l.grouping, r) {
+			return -1
+		}
+		return r
+	}, whole)
+
+	var words string
+	if n, err := strconv.ParseInt(whole, 10, 64); err == nil && n < 1e15 && (len(whole) == 1 || whole[0] != '0') {
+		words = l.cardinal(n)
+	} else {
+		// Leading zeros or too long: read it like a code
+		words = l.digits(whole)
+	}
+	if frac != "" {
+		words += " " + l.words["point"] + " " + l.fraction(frac)
+	}
+	if neg {
+		words = l.words["minus"] + " " + words
+	}
+	return words
+}
+
+// digits says each digit of s
+func (l *locale) digits(s string) string {
+	return readDigits(l.cardinal, s)
+}
+
+// readDigits says each digit of s with cardinal
+func readDigits(cardinal func(int64) string, s string) string {
+	words := make([]string, 0, len(s))
+	for _, r := range s {
+		words = append(words, cardinal(int64(r-'0')))
+	}
+	return strings.Join(words, " ")
+}
+
+// time says a time of day, given on the 24-hour clock, or on the 12-hour
+// clock without AM or PM if plain
+func (l *locale) time(hour, minute, second int, plain bool) string {
+	words := l.clock(hour, minute, plain)
+	if second != 0 {
+		words += " " + l.words["and"] + " " + l.quantity(strconv.Itoa(second), "s")
+	}
+	return words
+}
+
+// quantity says a number followed by a unit, in the singular or plural
+func (l *locale) quantity(n, unit string) string {
+	name := l.units[unit]
+	whole, frac := n, ""
+	if i := strings.LastIndexAny(n, l.decimal); i >= 0 {
+		whole, frac = n[:i], n[i+1:]
+	}
+	if !l.singular(strings.TrimPrefix(whole, "-"), frac) {
+		name += "s"
+	}
+	return l.number(n) + " " + name
+}
+
+// list joins words with commas and a final "and"
+func (l *locale) list(words []string) string {
+	if len(words) < 2 {
+		return strings.Join(words, "")
+	}
+	return strings.Join(words[:len(words)-1], ", ") + " " + l.words["and"] + " " + words[len(words)-1]
+}
+
+// English
+
+var (
+	enSmall = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+	}
+	enTens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	enScales = []struct {
+		value int64
+		name  string
+	}{{1e12, "trillion"}, {1e9, "billion"}, {1e6, "million"}, {1e3, "thousand"}}
+	enOrdinals = map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth",
+		"eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+)
+
+var english = &locale{
+	cardinal: enCardinal,
+	ordinal:  func(n int64, _ string) string { return enOrdinal(n) },
+	date: func(year int, month time.Month, day int) string {
+		return fmt.Sprintf("%s %s, %s", month, enOrdinal(int64(day)), enYear(year))
+	},
+	clock: enClock,
+	fraction: func(digits string) string {
+		return readDigits(enCardinal, digits)
+	},
+	singular: func(whole, frac string) bool { return whole == "1" && frac == "" },
+	decimal:  ".",
+	grouping: ",",
+	units: map[string]string{
+		"B": "byte", "KB": "kilobyte", "KiB": "kibibyte", "MB": "megabyte", "MiB": "mebibyte",
+		"GB": "gigabyte", "GiB": "gibibyte", "TB": "terabyte", "TiB": "tebibyte", "PB": "petabyte", "PiB": "pebibyte",
+		"h": "hour", "m": "minute", "s": "second", "ms": "millisecond", "µs": "microsecond", "ns": "nanosecond",
+		"$": "dollar", "€": "euro", "£": "pound",
+	},
+	words: map[string]string{
+		"minus": "minus", "point": "point", "percent": "percent", "of": "of", "and": "and",
+		"version": "version", "version point": "point", "commit": "commit", "at": "at",
+		"dot": "dot", "line": "line", "column": "column",
+	},
+	ordinals: `st|nd|rd|th`,
+}
+
+// enCardinal says n in English: 142 is "one hundred forty-two"
+func enCardinal(n int64) string {
+	switch {
+	case n < 0:
+		return "minus " + enCardinal(-n)
+	case n < 20:
+		return enSmall[n]
+	case n < 100:
+		words := enTens[n/10]
+		if n%10 != 0 {
+			words += "-" + enSmall[n%10]
+		}
+		return words
+	case n < 1000:
+		words := enSmall[n/100] + " hundred"
+		if n%100 != 0 {
+			words += " " + enCardinal(n%100)
+		}
+		return words
+	}
+	for _, scale := range enScales {
+		if n >= scale.value {
+			words := enCardinal(n/scale.value) + " " + scale.name
+			if n%scale.value != 0 {
+				words += " " + enCardinal(n%scale.value)
+			}
+			return words
+		}
+	}
+	return ""
+}
+
+// enOrdinal says n as an English ordinal: 21 is "twenty-first"
+func enOrdinal(n int64) string {
+	words := enCardinal(n)
+	i := strings.LastIndexAny(words, " -") + 1
+	last := words[i:]
+	switch {
+	case enOrdinals[last] != "":
+		last = enOrdinals[last]
+	case strings.HasSuffix(last, "y"):
+		last = strings.TrimSuffix(last, "y") + "ieth"
+	default:
+		last += "th"
+	}
+	return words[:i] + last
+}
+
+// enYear says a year the way it is spoken: 1999 is "nineteen ninety-nine",
+// 2005 is "two thousand five" and 2026 is "twenty twenty-six"
+func enYear(year int) string {
+	century, rest := int64(year/100), int64(year%100)
+	switch {
+	case year < 1000 || year >= 10000, year >= 2000 && year < 2010:
+		return enCardinal(int64(year))
+	case rest == 0:
+		return enCardinal(century) + " hundred"
+	case rest < 10:
+		return enCardinal(century) + " oh " + enCardinal(rest)
+	}
+	return enCardinal(century) + " " + enCardinal(rest)
+}
+
+// enClock says a time on the 12-hour clock: 14:05 is "two oh five PM",
+// and a plain 10:30 "ten thirty"
+func enClock(hour, minute int, plain bool) string {
+	meridiem := "AM"
+	if hour >= 12 {
+		meridiem = "PM"
+	}
+	h := hour % 12
+	if h == 0 {
+		h = 12
+	}
+	words := enCardinal(int64(h))
+	switch {
+	case minute == 0:
+	case minute < 10:
+		words += " oh " + enCardinal(int64(minute))
+	default:
+		words += " " + enCardinal(int64(minute))
+	}
+	if plain {
+		return words
+	}
+	return words + " " + meridiem
+}
+
+// French
+
+var (
+	frSmall = []string{
+		"zéro", "un", "deux", "trois", "quatre", "cinq", "six", "sept", "huit", "neuf",
+		"dix", "onze", "douze", "treize", "quatorze", "quinze", "seize", "dix-sept", "dix-huit", "dix-neuf",
+	}
+	frTens   = []string{"", "", "vingt", "trente", "quarante", "cinquante", "soixante"}
+	frScales = []struct {
+		value int64
+		name  string
+	}{{1e12, "billion"}, {1e9, "milliard"}, {1e6, "million"}}
+	frMonths = []string{
+		"janvier", "février", "mars", "avril", "mai", "juin",
+		"juillet", "août", "septembre", "octobre", "novembre", "décembre",
+	}
+)
+
+var french = &locale{
+	cardinal: frCardinal,
+	ordinal:  frOrdinal,
+	date: func(year int, month time.Month, day int) string {
+		d := frCardinal(int64(day))
+		if day == 1 {
+			d = "premier"
+		}
+		return fmt.Sprintf("%s %s %s", d, frMonths[month-1], frCardinal(int64(year)))
+	},
+	clock: frClock,
+	fraction: func(digits string) string {
+		// "virgule cinq cent trente-deux", but "virgule zéro cinq"
+		if len(digits) <= 3 && digits[0] != '0' {
+			n, _ := strconv.ParseInt(digits, 10, 64)
+			return frCardinal(n)
+		}
+		return readDigits(frCardinal, digits)
+	},
+	singular: func(whole, frac string) bool { return whole == "0" || whole == "1" },
+	decimal:  ",.",
+	grouping: " \u00a0\u202f", // spaces, as in 200 000
+	units: map[string]string{
+		"B": "octet", "KB": "kilooctet", "KiB": "kibioctet", "MB": "mégaoctet", "MiB": "mébioctet",
+		"GB": "gigaoctet", "GiB": "gibioctet", "TB": "téraoctet", "TiB": "tébioctet", "PB": "pétaoctet", "PiB": "pébioctet",
+		"h": "heure", "m": "minute", "s": "seconde", "ms": "milliseconde", "µs": "microseconde", "ns": "nanoseconde",
+		"$": "dollar", "€": "euro", "£": "livre",
+	},
+	words: map[string]string{
+		"minus": "moins", "point": "virgule", "percent": "pour cent", "of": "sur", "and": "et",
+		"version": "version", "version point": "point", "commit": "commit", "at": "à",
+		"dot": "point", "line": "ligne", "column": "colonne",
+	},
+	ordinals: `ère|ème|nde|er|re|nd|e`,
+}
+
+// frCardinal says n in French: 71 is "soixante et onze"
+func frCardinal(n int64) string {
+	switch {
+	case n < 0:
+		return "moins " + frCardinal(-n)
+	case n < 1000:
+		return frHundreds(int(n), true)
+	}
+	for _, scale := range frScales {
+		if n >= scale.value {
+			count := n / scale.value
+			words := frCardinal(count) + " " + scale.name
+			if count > 1 {
+				words += "s"
+			}
+			if n%scale.value != 0 {
+				words += " " + frCardinal(n%scale.value)
+			}
+			return words
+		}
+	}
+	// Thousands: "mille", "deux mille", "quatre-vingt mille"
+	words := "mille"
+	if count := n / 1000; count > 1 {
+		words = frHundreds(int(count), false) + " mille"
+	}
+	if n%1000 != 0 {
+		words += " " + frHundreds(int(n%1000), true)
+	}
+	return words
+}
+
+// frHundreds says n below 1000. Cents and vingts only take an s when
+// last, so final is false before "mille".
+func frHundreds(n int, final bool) string {
+	hundreds, rest := n/100, n%100
+	var words string
+	switch {
+	case hundreds == 0:
+		words = frTensWords(rest)
+		if !final {
+			words = trimVingts(words)
+		}
+		return words
+	case hundreds == 1:
+		words = "cent"
+	default:
+		words = frSmall[hundreds] + " cent"
+		if rest == 0 && final {
+			words += "s"
+		}
+	}
+	if rest != 0 {
+		tens := frTensWords(rest)
+		if !final {
+			tens = trimVingts(tens)
+		}
+		words += " " + tens
+	}
+	return words
+}
+
+// trimVingts drops the s of "quatre-vingts" before another number
+func trimVingts(words string) string {
+	if strings.HasSuffix(words, "vingts") {
+		return strings.TrimSuffix(words, "s")
+	}
+	return words
+}
+
+// frTensWords says n below 100
+func frTensWords(n int) string {
+	switch {
+	case n < 20:
+		return frSmall[n]
+	case n < 70:
+		words := frTens[n/10]
+		switch n % 10 {
+		case 0:
+		case 1:
+			words += " et un"
+		default:
+			words += "-" + frSmall[n%10]
+		}
+		return words
+	case n == 71:
+		return "soixante et onze"
+	case n < 80:
+		return "soixante-" + frSmall[n-60]
+	case n == 80:
+		return "quatre-vingts"
+	}
+	return "quatre-vingt-" + frSmall[n-80]
+}
+
+// frOrdinal says n as a French ordinal: 1er is "premier", 1re "première"
+// and 21e "vingt et unième"
+func frOrdinal(n int64, suffix string) string {
+	if n == 1 {
+		if strings.HasPrefix(suffix, "r") || strings.HasPrefix(suffix, "è") {
+			return "première"
+		}
+		return "premier"
+	}
+	words := frCardinal(n)
+	if !strings.HasSuffix(words, "trois") {
+		words = strings.TrimSuffix(words, "s") // vingts, cents, millions
+	}
+	switch {
+	case strings.HasSuffix(words, "cinq"):
+		words += "u"
+	case strings.HasSuffix(words, "neuf"):
+		words = strings.TrimSuffix(words, "f") + "v"
+	case strings.HasSuffix(words, "e"):
+		words = strings.TrimSuffix(words, "e")
+	}
+	return words + "ième"
+}
+
+// frClock says a time on the 24-hour clock: 14:05 is "quatorze heures
+// cinq". A plain time is said as written.
+func frClock(hour, minute int, _ bool) string {
+	h := frCardinal(int64(hour))
+	if strings.HasSuffix(h, "un") {
+		h += "e" // une heure, vingt et une heures
+	}
+	words := h + " heure"
+	if hour > 1 {
+		words += "s"
+	}
+	if minute != 0 {
+		words += " " + frCardinal(int64(minute))
+	}
+	return words
+}
@@ -0,0 +1,370 @@
+package normalize
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+	"github.com/ybouhjira/claude-code-tts/internal/tts"
+)
+
+// Code block handling
+const (
+	CodeSummarize = "summarize" // "Code block, 12 lines of Go."
+	CodeSkip      = "skip"
+	CodeRead      = "read"
+)
+
+// Normalizer rewrites developer text so it sounds natural when read
+// aloud: markdown syntax is dropped, code blocks are summarized, URLs are
+// shortened to their domain, paths are read by file name, identifiers
+// are split into words and numbers are spelled out in the locale.
+type Normalizer struct {
+	codeBlocks string
+	locale     *locale
+	verbalize  bool
+}
+
+// New creates the normalizer described by cfg. It returns nil if
+// normalization is disabled.
+func New(cfg config.NormalizeConfig) (*Normalizer, error) {
+	if cfg.Disabled {
+		return nil, nil
+	}
+	switch cfg.CodeBlocks {
+	case "":
+		cfg.CodeBlocks = CodeSummarize
+	case CodeSummarize, CodeSkip, CodeRead:
+	default:
+		return nil, fmt.Errorf("unknown code_blocks %q (use summarize, skip or read)", cfg.CodeBlocks)
+	}
+	l, err := lookupLocale(cfg.Locale)
+	if err != nil {
+		return nil, err
+	}
+	return &Normalizer{codeBlocks: cfg.CodeBlocks, locale: l, verbalize: !cfg.NoVerbalize}, nil
+}
+
+// Normalize rewrites text for listening. SSML is returned unchanged.
+func (n *Normalizer) Normalize(text string) string {
+	return n.normalize(text, n.locale)
+}
+
+// NormalizeIn is Normalize for text in the language lang, an ISO 639-1
+// code. Numbers and paths are read in that language if it is supported,
+// else in the configured locale.
+func (n *Normalizer) NormalizeIn(text, lang string) string {
+	if l, ok := locales[lang]; ok {
+		return n.normalize(text, l)
+	}
+	return n.normalize(text, n.locale)
+}
+
+// Protected text: Protect wraps it in these marks, and it is replaced by
+// a private use character from placeholderBase on while text is
+// normalized
+const (
+	protectOpen     = "\uE000"
+	protectClose    = "\uE001"
+	placeholderBase = 0xF0000
+)
+
+// protected matches a protected part of text
+var protected = regexp.MustCompile(protectOpen + "([^" + protectOpen + protectClose + "]*)" + protectClose)
+
+// Protect marks text, such as a lexicon replacement, for Normalize to
+// leave as it is
+func Protect(text string) string {
+	return protectOpen + text + protectClose
+}
+
+// shield replaces the protected parts of text with placeholders and
+// returns them, in order
+func shield(text string) (string, []string) {
+	var kept []string
+	text = protected.ReplaceAllStringFunc(text, func(m string) string {
+		kept = append(kept, protected.FindStringSubmatch(m)[1])
+		return string(rune(placeholderBase + len(kept) - 1))
+	})
+	return strings.NewReplacer(protectOpen, "", protectClose, "").Replace(text), kept
+}
+
+// unshield puts the protected parts of text back
+func unshield(text string, kept []string) string {
+	for i, k := range kept {
+		text = strings.Replace(text, string(rune(placeholderBase+i)), k, 1)
+	}
+	return text
+}
+
+// normalize rewrites text for listening in locale l
+func (n *Normalizer) normalize(text string, l *locale) string {
+	if tts.IsSSML(text) {
+		return text
+	}
+	text, kept := shield(text)
+
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		fence, info, ok := openFence(lines[i])
+		if !ok {
+			if line, keep := blockLine(lines[i]); keep {
+				out = append(out, line)
+			}
+			continue
+		}
+
+		var code []string
+		for i++; i < len(lines) && !closesFence(lines[i], fence); i++ {
+			code = append(code, lines[i])
+		}
+		out = append(out, "")
+		out = append(out, n.codeBlock(info, code)...)
+		out = append(out, "")
+	}
+
+	text = tidy(inline(strings.Join(out, "\n"), l))
+	if n.verbalize {
+		text = verbalize(text, l)
+	}
+	return unshield(text, kept)
+}
+
+// fenceOpen matches the opening line of a fenced code block
+var fenceOpen = regexp.MustCompile("^\\s{0,3}(`{3,}|~{3,})\\s*([^`\\s]*)")
+
+// openFence reports whether line opens a code block, returning the fence
+// and the language given after it
+func openFence(line string) (fence, info string, ok bool) {
+	m := fenceOpen.FindStringSubmatch(line)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// closesFence reports whether line closes a block opened with fence
+func closesFence(line, fence string) bool {
+	t := strings.TrimSpace(line)
+	return strings.HasPrefix(t, fence) && strings.Trim(t, fence[:1]) == ""
+}
+
+// languages are the spoken names of common code block languages
+var languages = map[string]string{
+	"go": "Go", "golang": "Go",
+	"py": "Python", "python": "Python",
+	"js": "JavaScript", "javascript": "JavaScript", "jsx": "JavaScript",
+	"ts": "TypeScript", "typescript": "TypeScript", "tsx": "TypeScript",
+	"sh": "shell", "bash": "shell", "shell": "shell", "zsh": "shell", "console": "shell",
+	"rs": "Rust", "rust": "Rust",
+	"java": "Java", "kt": "Kotlin", "kotlin": "Kotlin", "swift": "Swift",
+	"c": "C", "cpp": "C++", "c++": "C++", "cs": "C#", "csharp": "C#",
+	"rb": "Ruby", "ruby": "Ruby", "php": "PHP",
+	"sql": "SQL", "json": "JSON", "yaml": "YAML", "yml": "YAML", "toml": "TOML",
+	"xml": "XML", "html": "HTML", "css": "CSS", "diff": "diff",
+	"dockerfile": "Dockerfile", "makefile": "Makefile", "proto": "protobuf",
+}
+
+// codeBlock returns what is said for a code block
+func (n *Normalizer) codeBlock(info string, code []string) []string {
+	switch n.codeBlocks {
+	case CodeSkip:
+		return nil
+	case CodeRead:
+		return code
+	}
+
+	lines := 0
+	for _, l := range code {
+		if strings.TrimSpace(l) != "" {
+			lines++
+		}
+	}
+	summary := fmt.Sprintf("Code block, %d lines", lines)
+	if lines == 1 {
+		summary = "Code block, 1 line"
+	}
+	if lang, ok := languages[strings.ToLower(info)]; ok {
+		summary += " of " + lang
+	}
+	return []string{summary + "."}
+}
+
+// Block-level markdown
+var (
+	horizontalRule = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	tableDivider   = regexp.MustCompile(`^\s*\|?(\s*:?-+:?\s*\|)+\s*(:?-+:?)?\s*$`)
+	heading        = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)[\s#]*$`)
+	blockquote     = regexp.MustCompile(`^\s*(>\s?)+`)
+	listItem       = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+(\[[ xX]\]\s+)?`)
+)
+
+// blockLine strips the markdown syntax of one line outside code blocks.
+// Headings, list items and table rows end with a full stop so they are
+// read as separate sentences. It returns false for lines with nothing to
+// say, like table dividers.
+func blockLine(line string) (string, bool) {
+	switch {
+	case strings.TrimSpace(line) == "":
+		return "", true
+	case horizontalRule.MatchString(line), tableDivider.MatchString(line):
+		return "", false
+	}
+
+	if m := heading.FindStringSubmatch(line); m != nil {
+		return endSentence(m[1]), true
+	}
+	line = blockquote.ReplaceAllString(line, "")
+	if loc := listItem.FindStringIndex(line); loc != nil {
+		return endSentence(line[loc[1]:]), true
+	}
+	if t := strings.TrimSpace(line); strings.HasPrefix(t, "|") {
+		var cells []string
+		for _, c := range strings.Split(strings.Trim(t, "|"), "|") {
+			if c = strings.TrimSpace(c); c != "" {
+				cells = append(cells, c)
+			}
+		}
+		return endSentence(strings.Join(cells, ", ")), true
+	}
+	return line, true
+}
+
+// endSentence adds a full stop to text that does not end with punctuation
+func endSentence(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return text
+	}
+	last := []rune(text)[len([]rune(text))-1]
+	if strings.ContainsRune(".!?:;,…", last) {
+		return text
+	}
+	return text + "."
+}
+
+// Inline markdown and developer text
+var (
+	image      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	link       = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	autolink   = regexp.MustCompile(`<((?:https?://|www\.)[^>\s]+)>`)
+	rawURL     = regexp.MustCompile(`\b(?:https?://|www\.)[^\s<>()\[\]"']+`)
+	inlineCode = regexp.MustCompile("`+([^`]+?)`+")
+	bold       = regexp.MustCompile(`\*\*([^*\n]+)\*\*|__([^_\n]+)__`)
+	italic     = regexp.MustCompile(`(^|[^\w*])\*([^*\s][^*\n]*?)\*($|[^\w*])|(^|[^\w])_([^_\s][^_\n]*?)_($|[^\w])`)
+	strike     = regexp.MustCompile(`~~([^~\n]+)~~`)
+	htmlTag    = regexp.MustCompile(`(?i)</?(br|p|div|span|details|summary|sub|sup|kbd|b|i|em|strong|code|pre|img|a|ul|ol|li)\b[^>]*>`)
+	call       = regexp.MustCompile(`(\w)\(\)`)
+	path       = regexp.MustCompile(`(?:[~.]{0,2}/)?(?:[\w.-]+/)*([\w-]+(?:\.[\w-]+)*\.(?:go|mod|sum|py|js|mjs|cjs|ts|tsx|jsx|rs|java|kt|swift|c|h|cc|cpp|hpp|cs|rb|php|sh|bash|zsh|ps1|md|mdx|rst|txt|json|jsonl|yaml|yml|toml|ini|cfg|conf|env|xml|html|css|scss|sql|proto|lock|log|csv|tf|vue|svelte|dart|lua|ex|exs|erl|scala|gradle))(?::(\d+)(?::(\d+))?)?\b`)
+	identifier = regexp.MustCompile(`\b_*[A-Za-z][A-Za-z0-9]*(?:_+[A-Za-z0-9]+)*_*\b`)
+)
+
+// inline rewrites the inline markdown, URLs, paths and identifiers of text
+func inline(text string, l *locale) string {
+	text = image.ReplaceAllString(text, "$1")
+	text = link.ReplaceAllString(text, "$1")
+	text = autolink.ReplaceAllString(text, "$1")
+	text = rawURL.ReplaceAllStringFunc(text, shortenURL)
+	text = inlineCode.ReplaceAllString(text, "$1")
+	text = bold.ReplaceAllString(text, "$1$2")
+	text = italic.ReplaceAllString(text, "$1$2$3$4$5$6")
+	text = strike.ReplaceAllString(text, "$1")
+	text = htmlTag.ReplaceAllString(text, " ")
+	text = call.ReplaceAllString(text, "$1")
+	text = path.ReplaceAllStringFunc(text, func(match string) string {
+		return readPath(match, l)
+	})
+	return identifier.ReplaceAllStringFunc(text, splitIdentifier)
+}
+
+// shortenURL replaces a URL with its domain, keeping trailing punctuation
+func shortenURL(raw string) string {
+	trimmed := strings.TrimRight(raw, ".,;:!?")
+	suffix := raw[len(trimmed):]
+	if strings.HasPrefix(trimmed, "www.") {
+		trimmed = "http://" + trimmed
+	}
+	u, err := url.Parse(trimmed)
+	if err != nil || u.Hostname() == "" {
+		return raw
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.") + suffix
+}
+
+// readPath reads a file path by its file name, with the line and column
+// if given: internal/server/worker.go:142 is "worker dot go line 142"
+func readPath(match string, l *locale) string {
+	m := path.FindStringSubmatch(match)
+	spoken := strings.ReplaceAll(m[1], ".", " "+l.words["dot"]+" ")
+	if m[2] != "" {
+		spoken += " " + l.words["line"] + " " + m[2]
+	}
+	if m[3] != "" {
+		spoken += " " + l.words["column"] + " " + m[3]
+	}
+	return spoken
+}
+
+// splitIdentifier splits snake_case and camelCase identifiers into words:
+// handleSpeak is "handle Speak", HTTPServer is "HTTP Server" and
+// max_text_length is "max text length". Plain words and brand names like
+// iPhone are unchanged.
+func splitIdentifier(word string) string {
+	var parts []string
+	for _, p := range strings.Split(word, "_") {
+		if p != "" {
+			parts = append(parts, splitCamel(p)...)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// splitCamel splits word at lower-to-upper case changes after at least
+// two characters, so "iPhone" and "eBay" are kept whole, and before the
+// last capital of an acronym followed by lower case. A plural acronym
+// like "APIs" or "IDs" is kept whole.
+func splitCamel(word string) []string {
+	runes := []rune(word)
+	var parts []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		split := false
+		switch {
+		case unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			split = i-start >= 2
+		case unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// Keep "APIs": an acronym followed only by a plural s
+			split = !(runes[i+1] == 's' && i+2 == len(runes))
+		}
+		if split {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(parts, string(runes[start:]))
+}
+
+// Whitespace clean-up
+var (
+	spaces     = regexp.MustCompile(`[ \t]+`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+	spacePunct = regexp.MustCompile(` +([.,;:!?])`)
+)
+
+// tidy collapses the whitespace left behind, keeping paragraph breaks
+func tidy(text string) string {
+	text = spaces.ReplaceAllString(text, " ")
+	lines := strings.Split(text, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(l)
+	}
+	text = strings.Join(lines, "\n")
+	text = spacePunct.ReplaceAllString(text, "$1")
+	text = blankLines.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,147 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	if n, err := New(config.NormalizeConfig{Disabled: true}); n != nil || err != nil {
+		t.Errorf("expected no normalizer when disabled, got %v, %v", n, err)
+	}
+	if _, err := New(config.NormalizeConfig{CodeBlocks: "hum"}); err == nil {
+		t.Error("expected an error for an unknown code_blocks mode")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	n, err := New(config.NormalizeConfig{NoVerbalize: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "plain text",
+			in:   "Build completed successfully.",
+			want: "Build completed successfully.",
+		},
+		{
+			name: "emphasis and inline code",
+			in:   "This is **important** and *really* ~~not~~ `simple`.",
+			want: "This is important and really not simple.",
+		},
+		{
+			name: "headings and lists",
+			in:   "## Summary\n\n- Fixed the race\n- Added tests\n1. Build it",
+			want: "Summary.\n\nFixed the race.\nAdded tests.\nBuild it.",
+		},
+		{
+			name: "code block summarized",
+			in:   "Here is the fix:\n\n```go\nfunc main() {\n\n\tfmt.Println(\"hi\")\n}\n```\n\nDone.",
+			want: "Here is the fix:\n\nCode block, 3 lines of Go.\n\nDone.",
+		},
+		{
+			name: "unknown language and unterminated fence",
+			in:   "Run:\n```\nmake build\n",
+			want: "Run:\n\nCode block, 1 line.",
+		},
+		{
+			name: "links and URLs",
+			in:   "See [the docs](https://go.dev/doc) or https://www.github.com/ybouhjira/claude-code-tts/issues/12.",
+			want: "See the docs or github.com.",
+		},
+		{
+			name: "paths with line numbers",
+			in:   "The bug is in internal/server/worker.go:142 and `cmd/speak-text/main.go:10:3`.",
+			want: "The bug is in worker dot go line 142 and main dot go line 10 column 3.",
+		},
+		{
+			name: "identifiers",
+			in:   "Call handleSpeak() on the HTTPServer with max_text_length and two APIs.",
+			want: "Call handle Speak on the HTTP Server with max text length and two APIs.",
+		},
+		{
+			name: "snake case file",
+			in:   "Edit hooks/auto_speak.sh now.",
+			want: "Edit auto speak dot sh now.",
+		},
+		{
+			name: "table",
+			in:   "| Test | Result |\n|------|--------|\n| unit | pass |",
+			want: "Test, Result.\nunit, pass.",
+		},
+		{
+			name: "blockquote and rule",
+			in:   "> Quoted text\n\n---\n\nAfter.",
+			want: "Quoted text\n\nAfter.",
+		},
+		{
+			name: "html",
+			in:   "Line one<br>line two",
+			want: "Line one line two",
+		},
+		{
+			name: "SSML untouched",
+			in:   "<speak>handleSpeak **now**</speak>",
+			want: "<speak>handleSpeak **now**</speak>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := n.Normalize(tt.in); got != tt.want {
+				t.Errorf("Normalize(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalize_CodeBlockModes(t *testing.T) {
+	in := "Before.\n\n```py\nprint_value(x)\n```\n\nAfter."
+
+	skip, _ := New(config.NormalizeConfig{CodeBlocks: CodeSkip})
+	if got := skip.Normalize(in); got != "Before.\n\nAfter." {
+		t.Errorf("skip: got %q", got)
+	}
+	read, _ := New(config.NormalizeConfig{CodeBlocks: CodeRead})
+	if got := read.Normalize(in); got != "Before.\n\nprint value(x)\n\nAfter." {
+		t.Errorf("read: got %q", got)
+	}
+}
+
+func TestNormalize_Protect(t *testing.T) {
+	n, _ := New(config.NormalizeConfig{})
+
+	in := "Use " + Protect("PostgreSQL 16") + " for `user_id` on " + Protect("my_host") + " and an iPhone"
+	if got := n.Normalize(in); got != "Use PostgreSQL 16 for user id on my_host and an iPhone" {
+		t.Errorf("expected the protected text to be kept, got %q", got)
+	}
+}
+
+func TestSplitCamel(t *testing.T) {
+	tests := map[string]string{
+		"handleSpeak":  "handle Speak",
+		"HTTPServer":   "HTTP Server",
+		"NewClient":    "New Client",
+		"utf8String":   "utf8 String",
+		"IDs":          "IDs",
+		"getUserIDs":   "get User IDs",
+		"simple":       "simple",
+		"URL":          "URL",
+		"parseJSONAPI": "parse JSONAPI",
+		"iPhone":       "iPhone",
+		"eBay":         "eBay",
+		"iOS":          "iOS",
+	}
+	for in, want := range tests {
+		if got := splitIdentifier(in); got != want {
+			t.Errorf("splitIdentifier(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package normalize
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Go test output
+var (
+	testPackage = regexp.MustCompile(`(?m)^(ok|FAIL|\?)[ \t]+([\w.\-/]+)[ \t]+(\d+(?:\.\d+)?s|\(cached\)|\[no test files\])(?:[ \t]+coverage: ([\d.]+%) of statements)?[ \t]*$`)
+	testCase    = regexp.MustCompile(`(?m)^[ \t]*--- (PASS|FAIL|SKIP): (\S+) \((\d+(?:\.\d+)?s)\)[ \t]*$`)
+	testCount   = regexp.MustCompile(`(?i)\b(pass(?:ed)?|fail(?:ed)?|ok)(:?[ \t]+)(\d+)[ \t]*/[ \t]*(\d+)|(\d+)[ \t]*/[ \t]*(\d+)([ \t]+(?:tests?|passed|failed|passing|failing|succeeded|checks?)\b)`)
+)
+
+// Numbers and units
+var (
+	isoDate   = regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})(?:T(\d{2}):(\d{2})(?::(\d{2})(?:\.\d+)?)?(Z|[+-]\d{2}:?\d{2})?)?`)
+	clockTime = regexp.MustCompile(`([01]?\d|2[0-3]):([0-5]\d)(?::([0-5]\d))?(?:[ \t]?([AaPp])(?:[Mm]|\.[Mm]\.))?`)
+	version   = regexp.MustCompile(`((?i:version)[ \t]+)?(v)?(\d+(?:\.\d+)*)(-[0-9A-Za-z]+(?:\.[0-9A-Za-z]+)*)?`)
+	hexHash   = regexp.MustCompile(`((?i:commit|sha|hash)[ \t]+)?([0-9a-f]{7,64})`)
+	wordNum   = regexp.MustCompile(`^[a-f]+\d+$`)
+	currency  = regexp.MustCompile(`([$€£])[ \t]?(\d+(?:[.,]\d+)*)|(\d+(?:[.,]\d+)*)[ \t\x{00a0}\x{202f}]?([€£])`)
+	byteSize  = regexp.MustCompile(`(\d+(?:[.,]\d+)?)(?:[ \t]?(kB|[KMGTP]i?B)|[ \t](B))`)
+	duration  = regexp.MustCompile(`(?:\d+(?:\.\d+)?(?:h|ms|µs|us|ns|m|s))+|\d+(?:\.\d+)?[ \t](?:h|ms|µs|ns|s)`)
+	durPart   = regexp.MustCompile(`(\d+(?:\.\d+)?)[ \t]?(h|ms|µs|us|ns|m|s)`)
+	percent   = regexp.MustCompile(`(\d+(?:[.,]\d+)?)[ \t\x{00a0}\x{202f}]?%`)
+)
+
+// verbalize says the numbers of text in l's language: sizes, durations,
+// versions, dates, times, commit hashes and test results included
+func verbalize(text string, l *locale) string {
+	text = testPackage.ReplaceAllStringFunc(text, func(s string) string {
+		m := testPackage.FindStringSubmatch(s)
+		status := m[1] + " "
+		if m[1] == "?" {
+			status = ""
+		}
+		result := strings.Trim(m[3], "()[]")
+		if m[4] != "" {
+			result += ", coverage " + m[4]
+		}
+		return status + packageName(m[2]) + ", " + result + "."
+	})
+	text = testCase.ReplaceAllString(text, "$1: $2, $3.")
+	text = replace(text, testCount, false, func(m []string, _ bool) (string, bool) {
+		if m[3] != "" {
+			return m[1] + m[2] + m[3] + " " + l.words["of"] + " " + m[4], true
+		}
+		return m[5] + " " + l.words["of"] + " " + m[6] + m[7], true
+	})
+
+	text = replace(text, isoDate, false, func(m []string, _ bool) (string, bool) {
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		if month < 1 || month > 12 || day < 1 || day > 31 {
+			return "", false
+		}
+		words := l.date(year, time.Month(month), day)
+		if m[4] != "" {
+			hour, _ := strconv.Atoi(m[4])
+			minute, _ := strconv.Atoi(m[5])
+			second, _ := strconv.Atoi(m[6])
+			words += " " + l.words["at"] + " " + l.time(hour, minute, second, false)
+			if m[7] == "Z" {
+				words += " UTC"
+			}
+		}
+		return words, true
+	})
+	text = replace(text, clockTime, false, func(m []string, _ bool) (string, bool) {
+		hour, _ := strconv.Atoi(m[1])
+		minute, _ := strconv.Atoi(m[2])
+		second, _ := strconv.Atoi(m[3])
+		// 10:30 could be morning or evening, but 09:30 and 14:30 are on
+		// the 24-hour clock
+		plain := m[4] == "" && hour >= 1 && hour <= 12 && m[1][0] != '0'
+		switch strings.ToLower(m[4]) {
+		case "a":
+			if hour > 12 {
+				return "", false
+			}
+			hour %= 12
+		case "p":
+			if hour > 12 {
+				return "", false
+			}
+			hour = hour%12 + 12
+		}
+		return l.time(hour, minute, second, plain), true
+	})
+
+	text = replace(text, version, false, func(m []string, _ bool) (string, bool) {
+		// v1.2 or 1.2.3, but not 3.14 or an IP address
+		dots := strings.Count(m[3], ".")
+		if m[2] == "" && dots != 2 {
+			return "", false
+		}
+		parts := strings.Split(m[3], ".")
+		for i, p := range parts {
+			parts[i] = l.number(p)
+		}
+		words := l.words["version"] + " " + strings.Join(parts, " "+l.words["version point"]+" ")
+		for _, p := range strings.FieldsFunc(m[4], func(r rune) bool { return r == '-' || r == '.' }) {
+			switch {
+			case isDigits(p):
+				words += " " + l.number(p)
+			case strings.EqualFold(p, "rc"):
+				words += " release candidate"
+			default:
+				words += " " + p
+			}
+		}
+		return words, true
+	})
+	text = replace(text, hexHash, false, func(m []string, _ bool) (string, bool) {
+		// It is read by its first six characters, after "commit", "sha"
+		// or "hash", or as a commit if it mixes letters and at least two
+		// digits, unlike "deadbeef" or "1234567", and is not a word and a
+		// number like "cafe123"
+		if m[1] != "" {
+			return m[1] + m[2][:6], true
+		}
+		letters := strings.Map(func(r rune) rune {
+			if unicode.IsDigit(r) {
+				return -1
+			}
+			return r
+		}, m[2])
+		if digits := len(m[2]) - len(letters); digits < 2 || letters == "" || wordNum.MatchString(m[2]) {
+			return "", false
+		}
+		return l.words["commit"] + " " + m[2][:6], true
+	})
+
+	text = replace(text, currency, true, func(m []string, neg bool) (string, bool) {
+		symbol, amount := m[1], m[2]
+		if symbol == "" {
+			amount, symbol = m[3], m[4]
+		}
+		amount = sign(neg) + amount
+		// $4.50 is "four dollars fifty"
+		if i := strings.LastIndexAny(amount, l.decimal); i >= 0 && len(amount)-i == 3 {
+			words := l.quantity(amount[:i], symbol)
+			if cents := strings.TrimLeft(amount[i+1:], "0"); cents != "" {
+				words += " " + l.number(cents)
+			}
+			return words, true
+		}
+		return l.quantity(amount, symbol), true
+	})
+
+	text = replace(text, byteSize, false, func(m []string, _ bool) (string, bool) {
+		unit := m[2] + m[3]
+		if unit == "kB" {
+			unit = "KB"
+		}
+		return l.quantity(m[1], unit), true
+	})
+	text = replace(text, duration, false, func(m []string, _ bool) (string, bool) {
+		return sayDuration(m[0], l), true
+	})
+	text = replace(text, percent, true, func(m []string, neg bool) (string, bool) {
+		return l.number(sign(neg)+m[1]) + " " + l.words["percent"], true
+	})
+	text = replace(text, l.ordinalRe, false, func(m []string, _ bool) (string, bool) {
+		n, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return "", false
+		}
+		return l.ordinal(n, m[2]), true
+	})
+	return replace(text, l.numberRe, true, func(m []string, neg bool) (string, bool) {
+		return l.number(sign(neg) + m[0]), true
+	})
+}
+
+func init() {
+	for _, l := range locales {
+		l.compile()
+	}
+}
+
+// compile builds the patterns that depend on the notation of l: its
+// ordinals, like 21st or 21e, and its numbers, with thousands grouped
+func (l *locale) compile() {
+	l.ordinalRe = regexp.MustCompile(`(\d+)(` + l.ordinals + `)`)
+	group := regexp.QuoteMeta(l.grouping)
+	decimal := regexp.QuoteMeta(l.decimal)
+	l.numberRe = regexp.MustCompile(`\d{1,3}(?:[` + group + `]\d{3})+(?:[` + decimal + `]\d+)?|\d+(?:[` + decimal + `]\d+)?`)
+}
+
+// sayDuration says a Go duration: 3m20s is "three minutes and twenty
+// seconds" and 0.532s is "five hundred thirty-two milliseconds"
+func sayDuration(d string, l *locale) string {
+	parts := durPart.FindAllStringSubmatch(d, -1)
+	if len(parts) == 1 && parts[0][2] == "s" {
+		if ms, ok := milliseconds(parts[0][1]); ok {
+			return l.quantity(ms, "ms")
+		}
+	}
+	words := make([]string, 0, len(parts))
+	for _, p := range parts {
+		unit := p[2]
+		if unit == "us" {
+			unit = "µs"
+		}
+		words = append(words, l.quantity(p[1], unit))
+	}
+	return l.list(words)
+}
+
+// milliseconds converts a number of seconds below one, with at most three
+// decimals, to whole milliseconds
+func milliseconds(seconds string) (string, bool) {
+	whole, frac, ok := strings.Cut(seconds, ".")
+	if !ok || whole != "0" || len(frac) > 3 {
+		return "", false
+	}
+	ms := strings.TrimLeft(frac+strings.Repeat("0", 3-len(frac)), "0")
+	if ms == "" {
+		ms = "0"
+	}
+	return ms, true
+}
+
+// packageName reads the last two elements of a package path:
+// github.com/ybouhjira/claude-code-tts/internal/server is "internal server"
+func packageName(pkg string) string {
+	parts := strings.Split(strings.Trim(pkg, "/"), "/")
+	if n := len(parts); n > 1 && !strings.Contains(parts[n-2], ".") {
+		return parts[n-2] + " " + parts[n-1]
+	}
+	return parts[len(parts)-1]
+}
+
+// replace replaces the standalone matches of re in text, those not part of
+// a longer word or number, with the result of f. f returns false to keep
+// a match. If signed, a minus sign before a match is passed to f as neg
+// and replaced along with it.
+func replace(text string, re *regexp.Regexp, signed bool, f func(m []string, neg bool) (string, bool)) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[0], loc[1]
+		if !standalone(text, start, end) {
+			continue
+		}
+		m := make([]string, len(loc)/2)
+		for i := range m {
+			if loc[2*i] >= 0 {
+				m[i] = text[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		neg := signed && negative(text, start)
+		out, ok := f(m, neg)
+		if !ok {
+			continue
+		}
+		if neg {
+			start--
+		}
+		b.WriteString(text[last:start])
+		b.WriteString(out)
+		last = end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// standalone reports whether text[start:end] is a whole token: "42" in
+// "took 42 tests", but not in "tts42", "1.42.0", "4/42", "1:42" or "42px"
+func standalone(text string, start, end int) bool {
+	if start > 0 {
+		prev, size := utf8.DecodeLastRuneInString(text[:start])
+		if isWord(prev) || prev == '.' || prev == '/' || (prev == ',' || prev == ':') && precededByDigit(text[:start-size]) {
+			return false
+		}
+		// "gpt-4" is a name, but "1-2" is a range
+		if prev == '-' {
+			if before, _ := utf8.DecodeLastRuneInString(text[:start-size]); isWord(before) && !unicode.IsDigit(before) {
+				return false
+			}
+		}
+	}
+	if end < len(text) {
+		next, size := utf8.DecodeRuneInString(text[end:])
+		if isWord(next) || next == '/' || next == '%' {
+			return false
+		}
+		if strings.ContainsRune(".,:", next) && end+size < len(text) && isDigits(text[end+size:end+size+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// negative reports whether the match at start follows a minus sign that
+// starts a word
+func negative(text string, start int) bool {
+	if start == 0 || text[start-1] != '-' {
+		return false
+	}
+	if start == 1 {
+		return true
+	}
+	before, _ := utf8.DecodeLastRuneInString(text[:start-1])
+	return unicode.IsSpace(before) || before == '('
+}
+
+// isWord reports whether r can be part of a word
+func isWord(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// precededByDigit reports whether text ends with a digit
+func precededByDigit(text string) bool {
+	r, _ := utf8.DecodeLastRuneInString(text)
+	return unicode.IsDigit(r)
+}
+
+// isDigits reports whether s is made of ASCII digits only
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// sign returns "-" for a negative number
+func sign(neg bool) string {
+	if neg {
+		return "-"
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func TestVerbalize_English(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"It took 142 retries.", "It took one hundred forty-two retries."},
+		{"We have 1,234,567 rows and -3 left.", "We have one million two hundred thirty-four thousand five hundred sixty-seven rows and minus three left."},
+		{"Pi is 3.14 and the code is 007.", "Pi is three point one four and the code is zero zero seven."},
+		{"The 1st, 2nd, 3rd and 21st runs.", "The first, second, third and twenty-first runs."},
+		{"Coverage is 85.3%.", "Coverage is eighty-five point three percent."},
+		{"The heap hit 512MiB, the image is 1.5 GB and 1 B.", "The heap hit five hundred twelve mebibytes, the image is one point five gigabytes and one byte."},
+		{"Run a 7B model, not 3B.", "Run a 7B model, not 3B."},
+		{"Build took 3m20s, then 1h2m3s.", "Build took three minutes and twenty seconds, then one hour, two minutes and three seconds."},
+		{"A request takes 150ms or 0.532s.", "A request takes one hundred fifty milliseconds or five hundred thirty-two milliseconds."},
+		{"Lookups take 3 ms, startup 2 s.", "Lookups take three milliseconds, startup two seconds."},
+		{"Upgrade to v1.23.4 or 2.0.0-rc.1.", "Upgrade to version one point twenty-three point four or version two point zero point zero release candidate one."},
+		{"Released on 2026-10-16.", "Released on October sixteenth, twenty twenty-six."},
+		{"Deployed 2026-03-01T14:05:00Z.", "Deployed March first, twenty twenty-six at two oh five PM UTC."},
+		{"Meet at 09:30 or 12:00, not 7:15pm.", "Meet at nine thirty AM or twelve, not seven fifteen PM."},
+		{"Standup at 10:30 or 14:30.", "Standup at ten thirty or two thirty PM."},
+		{"Mix at a ratio 1:2.", "Mix at a ratio 1:2."},
+		{"It costs $5, $4.50 or 20 €.", "It costs five dollars, four dollars fifty or twenty euros."},
+		{"Fixed in a1b2c3d4e5f6 and commit 9f8e7d6c.", "Fixed in commit a1b2c3 and commit 9f8e7d."},
+		{"Fixed in a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0.", "Fixed in commit a1b2c3."},
+		{"Order a cafe123, not abcdef1.", "Order a cafe123, not abcdef1."},
+		{"ok  internal/server 0.532s", "ok internal server, five hundred thirty-two milliseconds."},
+		{"?   github.com/ybouhjira/claude-code-tts/internal/logging [no test files]", "internal logging, no test files."},
+		{"ok  github.com/ybouhjira/claude-code-tts/internal/tts (cached) coverage: 81.5% of statements", "ok internal tts, cached, coverage eighty-one point five percent."},
+		{"--- FAIL: TestHandleSpeak (0.01s)", "FAIL: TestHandleSpeak, ten milliseconds."},
+		{"PASS 48/50", "PASS forty-eight of fifty"},
+		{"48/50 tests passed", "forty-eight of fifty tests passed"},
+		{"Use gpt-4o with tts-1 on 192.168.1.10 and sha256.", "Use gpt-4o with tts-1 on 192.168.1.10 and sha256."},
+		{"See pages 3/4 and deadbeef.", "See pages 3/4 and deadbeef."},
+	}
+	for _, tt := range tests {
+		if got := verbalize(tt.in, english); got != tt.want {
+			t.Errorf("verbalize(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestVerbalize_French(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"71 tests, 80 fichiers et 91 lignes.", "soixante et onze tests, quatre-vingts fichiers et quatre-vingt-onze lignes."},
+		{"Il y a 200 000 lignes, 201 erreurs et 1 000 001 appels.", "Il y a deux cent mille lignes, deux cent un erreurs et un million un appels."},
+		{"Le taux est de 12,5 %.", "Le taux est de douze virgule cinq pour cent."},
+		{"Le 1er et la 1re, le 2e et le 21e.", "Le premier et la première, le deuxième et le vingt et unième."},
+		{"La réponse prend 1.5s et 3m20s.", "La réponse prend un virgule cinq seconde et trois minutes et vingt secondes."},
+		{"Publié le 2026-10-01 à 14:05.", "Publié le premier octobre deux mille vingt-six à quatorze heures cinq."},
+		{"Il reste 512MiB.", "Il reste cinq cent douze mébioctets."},
+		{"Ça coûte 1 € ou 4,50 €.", "Ça coûte un euro ou quatre euros cinquante."},
+		{"Rendez-vous à 10:30.", "Rendez-vous à dix heures trente."},
+		{"PASS 48/50", "PASS quarante-huit sur cinquante"},
+		{"Passez à v2.1.0.", "Passez à version deux point un point zéro."},
+	}
+	for _, tt := range tests {
+		if got := verbalize(tt.in, french); got != tt.want {
+			t.Errorf("verbalize(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCardinal(t *testing.T) {
+	tests := []struct {
+		n      int64
+		en, fr string
+	}{
+		{0, "zero", "zéro"},
+		{21, "twenty-one", "vingt et un"},
+		{80, "eighty", "quatre-vingts"},
+		{99, "ninety-nine", "quatre-vingt-dix-neuf"},
+		{100, "one hundred", "cent"},
+		{300, "three hundred", "trois cents"},
+		{1000, "one thousand", "mille"},
+		{3000, "three thousand", "trois mille"},
+		{80000, "eighty thousand", "quatre-vingt mille"},
+		{2000000, "two million", "deux millions"},
+	}
+	for _, tt := range tests {
+		if got := enCardinal(tt.n); got != tt.en {
+			t.Errorf("enCardinal(%d) = %q, want %q", tt.n, got, tt.en)
+		}
+		if got := frCardinal(tt.n); got != tt.fr {
+			t.Errorf("frCardinal(%d) = %q, want %q", tt.n, got, tt.fr)
+		}
+	}
+}
+
+func TestNormalize_Verbalize(t *testing.T) {
+	n, err := New(config.NormalizeConfig{Locale: "fr-FR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := n.Normalize("Erreur dans `internal/server/worker.go:142`.")
+	if want := "Erreur dans worker point go ligne cent quarante-deux."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = n.NormalizeIn("Fixed at 14:30 in `worker.go:142`.", "en")
+	if want := "Fixed at two thirty PM in worker dot go line one hundred forty-two."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := n.NormalizeIn("Il reste 3 tests.", "de"); got != "Il reste trois tests." {
+		t.Errorf("expected the configured locale for an unsupported language, got %q", got)
+	}
+
+	if _, err := New(config.NormalizeConfig{Locale: "de"}); err == nil {
+		t.Error("expected an error for an unsupported locale")
+	}
+}
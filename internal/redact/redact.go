@@ -0,0 +1,212 @@
+package redact
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// Kinds of redacted text
+const (
+	PrivateKey = "private_key"
+	JWT        = "jwt"
+	AWSKey     = "aws_key"
+	APIKey     = "api_key"
+	Email      = "email"
+	Password   = "password"
+	Secret     = "secret"
+	Token      = "token" // any other long random string
+)
+
+// placeholders are what is said instead of each kind
+var placeholders = map[string]string{
+	PrivateKey: "redacted private key",
+	JWT:        "redacted token",
+	AWSKey:     "redacted AWS key",
+	APIKey:     "redacted API key",
+	Email:      "redacted email",
+	Password:   "redacted password",
+	Secret:     "redacted secret",
+	Token:      "redacted token",
+}
+
+// Placeholder returns what is said instead of text of kind
+func Placeholder(kind string) string {
+	if p, ok := placeholders[kind]; ok {
+		return p
+	}
+	return "redacted " + strings.ReplaceAll(kind, "_", " ")
+}
+
+// detector finds one kind of secret
+type detector struct {
+	kind  string
+	re    *regexp.Regexp
+	group int // submatch replaced, 0 for the whole match
+	// accept reports whether a match is a secret; nil accepts all
+	accept func(match string) bool
+}
+
+// builtin are the detectors always run, most specific first, so an API
+// key is not also counted as a random token
+var builtin = []detector{
+	{kind: PrivateKey, re: regexp.MustCompile(`(?s)-----BEGIN[A-Z ]*PRIVATE KEY-----.*?(?:-----END[A-Z ]*PRIVATE KEY-----|$)`)},
+	{kind: JWT, re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]*`)},
+	{kind: AWSKey, re: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA|AGPA|AIDA|AIPA|AROA|ANPA|ANVA|APKA)[A-Z0-9]{16}\b`)},
+	{kind: APIKey, re: regexp.MustCompile(`\b(?:sk-[A-Za-z0-9_-]{20,}|sk_(?:live_|test_)?[A-Za-z0-9]{24,}|rk_live_[A-Za-z0-9]{24,}|gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,}|glpat-[A-Za-z0-9_-]{20,}|xox[abprs]-[A-Za-z0-9-]{10,}|AIza[0-9A-Za-z_-]{35}|SG\.[A-Za-z0-9_-]{22}\.[A-Za-z0-9_-]{43})`)},
+	{
+		// Not after ":" or "/", so the password and host of a connection
+		// string are not read as an address
+		kind: Email, group: 1,
+		re:     regexp.MustCompile(`(?:^|[^\w.%+:/-])([A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,})`),
+		accept: func(m string) bool { return !strings.HasPrefix(m, "git@") },
+	},
+	{kind: Password, group: 1, re: regexp.MustCompile(`\b[A-Za-z][A-Za-z0-9+.-]*://[^\s:/@]*:([^\s@/]+)@`)},
+	{
+		kind: Password, group: 1,
+		re:     regexp.MustCompile(`(?i)(?:password|passwd|pwd)["']?\s*[:=]\s*["']?([^\s"',;]*[^\s"',;.!?)])`),
+		accept: isValue,
+	},
+	{
+		kind: Secret, group: 1,
+		re:     regexp.MustCompile(`(?i)(?:secret|token|api[_-]?key|access[_-]?key)["']?\s*[:=]\s*["']?([^\s"',;]*[^\s"',;.!?)])`),
+		accept: isValue,
+	},
+}
+
+// randomToken is a candidate for the entropy check
+var randomToken = detector{kind: Token, re: regexp.MustCompile(`[A-Za-z0-9+/_=-]{20,}`), accept: isRandom}
+
+// Redactor replaces secrets and personal data with spoken placeholders
+type Redactor struct {
+	detectors []detector
+}
+
+// New creates the redactor described by cfg, with its custom patterns
+// after the built-in detectors. It returns nil if redaction is disabled.
+func New(cfg config.RedactConfig) (*Redactor, error) {
+	if cfg.Disabled {
+		return nil, nil
+	}
+	r := &Redactor{detectors: append([]detector(nil), builtin...)}
+
+	names := make([]string, 0, len(cfg.Patterns))
+	for name := range cfg.Patterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		re, err := regexp.Compile(cfg.Patterns[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", name, err)
+		}
+		d := detector{kind: name, re: re}
+		if re.NumSubexp() > 0 {
+			d.group = 1
+		}
+		r.detectors = append(r.detectors, d)
+	}
+
+	r.detectors = append(r.detectors, randomToken)
+	return r, nil
+}
+
+// Redact replaces every secret in text with its placeholder, e.g.
+// "redacted API key", and returns how many of each kind were found
+func (r *Redactor) Redact(text string) (string, map[string]int) {
+	var counts map[string]int
+	for _, d := range r.detectors {
+		var b strings.Builder
+		last := 0
+		for _, loc := range d.re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[2*d.group], loc[2*d.group+1]
+			if start < 0 || start == end {
+				continue
+			}
+			if d.accept != nil && !d.accept(text[start:end]) {
+				continue
+			}
+			b.WriteString(text[last:start])
+			b.WriteString(Placeholder(d.kind))
+			last = end
+			if counts == nil {
+				counts = make(map[string]int)
+			}
+			counts[d.kind]++
+		}
+		if last > 0 {
+			b.WriteString(text[last:])
+			text = b.String()
+		}
+	}
+	return text, counts
+}
+
+// Summary describes counts for logs and tool results: "1 api_key, 2 email"
+func Summary(counts map[string]int) string {
+	kinds := make([]string, 0, len(counts))
+	for kind := range counts {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	parts := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// isValue reports whether the value of a password or token setting is a
+// real one, not a placeholder or a variable. Any other value is, even a
+// plain word like "hunter".
+func isValue(v string) bool {
+	return v != "" && !strings.HasPrefix(v, "redacted") && !strings.ContainsAny(v[:1], "$<{*%")
+}
+
+// minTokenLength is the shortest string the entropy check considers
+const minTokenLength = 20
+
+// isRandom reports whether s looks like a generated secret: upper and
+// lower case letters and digits, spread close to evenly. Identifiers
+// repeat letters, and hashes and UUIDs have no upper case.
+func isRandom(s string) bool {
+	s = strings.TrimRight(s, "=")
+	if len(s) < minTokenLength {
+		return false
+	}
+	var upper, lower, digit bool
+	for _, r := range s {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	if !upper || !lower || !digit {
+		return false
+	}
+	return entropy(s) >= 0.85*math.Log2(math.Min(float64(len(s)), 64))
+}
+
+// entropy returns the Shannon entropy of s in bits per character
+func entropy(s string) float64 {
+	freq := make(map[rune]int)
+	for _, r := range s {
+		freq[r]++
+	}
+	var h float64
+	n := float64(len(s))
+	for _, c := range freq {
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+	"github.com/ybouhjira/claude-code-tts/internal/language"
+	"github.com/ybouhjira/claude-code-tts/internal/tts"
+)
+
+// route is the voice and synthesizer that speak one language
+type route struct {
+	voice tts.Voice       // empty for the requested voice
+	synth tts.Synthesizer // nil for the active provider
+}
+
+// newRoutes creates the routes of the languages in cfg. A language routed
+// to a fallback of the active chain shares that provider, with its limits
+// and breaker. One routed to any other provider gets its own synthesizer,
+// without fallbacks, wrapped by wrap like the active one; languages routed
+// to the same provider share it. It returns nil if no language is routed.
+func newRoutes(cfg *config.Config, active tts.Synthesizer, wrap func(tts.Synthesizer) (tts.Synthesizer, error)) (map[string]route, error) {
+	if len(cfg.Languages) == 0 {
+		return nil, nil
+	}
+	known := language.Languages()
+	synths := make(map[string]tts.Synthesizer)
+	routes := make(map[string]route, len(cfg.Languages))
+	for lang, lr := range cfg.Languages {
+		if !slices.Contains(known, lang) {
+			return nil, fmt.Errorf("unknown language %q (supported: %s)", lang, strings.Join(known, ", "))
+		}
+
+		r := route{voice: tts.Voice(lr.Voice)}
+		speaker := active
+		if lr.Provider != "" && lr.Provider != cfg.Provider {
+			synth, ok := synths[lr.Provider]
+			if chain, isChain := active.(*tts.Chain); !ok && isChain {
+				if link := chain.Link(lr.Provider); link != nil {
+					synth, ok = link, true
+					synths[lr.Provider] = synth
+				}
+			}
+			if !ok {
+				p, err := tts.NewProvider(cfg, lr.Provider)
+				if err != nil {
+					return nil, fmt.Errorf("language %q: %w", lang, err)
+				}
+				if synth, err = wrap(p); err != nil {
+					return nil, fmt.Errorf("language %q: %w", lang, err)
+				}
+				synths[lr.Provider] = synth
+			}
+			r.synth, speaker = synth, synth
+			if r.voice == "" {
+				r.voice = tts.DefaultVoice(synth)
+			}
+		}
+		if r.voice != "" && !tts.SupportsVoice(speaker, r.voice) {
+			return nil, fmt.Errorf("language %q: invalid voice %q for provider %s", lang, r.voice, speaker.Name())
+		}
+		routes[lang] = r
+	}
+	return routes, nil
+}
+
+// segments splits text by language, or takes all of it to be in lang if
+// given or if it is SSML, which splitting would leave without its tags,
+// and normalizes each part in its language when normalize is set.
+// Parts in a routed language get its voice and provider, the others
+// voice; neighbouring parts spoken alike are merged. It returns the text
+// to speak, and no segments if all of it is spoken with voice.
+func (s *Server) segments(text, lang string, voice tts.Voice, normalize bool) (string, []JobSegment) {
+	parts := []language.Segment{{Language: lang, Text: text}}
+	if lang == "" && !tts.IsSSML(text) {
+		parts = language.Split(text)
+	}
+
+	var segments []JobSegment
+	for _, part := range parts {
+		if normalize {
+			part.Text = s.normalizer.NormalizeIn(part.Text, part.Language)
+		}
+		if part.Text == "" {
+			continue
+		}
+		r, routed := s.routes[part.Language]
+		if !routed {
+			part.Language = ""
+		}
+		if r.voice == "" && r.synth == nil {
+			r.voice = voice
+		}
+		if n := len(segments); n > 0 && segments[n-1].Language == part.Language {
+			part.Text = segments[n-1].text + "\n" + part.Text
+			segments = segments[:n-1]
+		}
+		segments = append(segments, NewJobSegment(part.Language, part.Text, r.voice, r.synth))
+	}
+
+	texts := make([]string, len(segments))
+	for i, seg := range segments {
+		texts[i] = seg.text
+	}
+	if len(segments) == 1 && segments[0].Language == "" {
+		segments = nil
+	}
+	return strings.Join(texts, "\n"), segments
+}
+
+// describeSegments lists the routed languages of segments with their
+// voice and provider, e.g. "fr (voice nova, provider azure)"
+func describeSegments(segments []JobSegment) string {
+	var descs []string
+	seen := make(map[string]bool)
+	for _, seg := range segments {
+		if seg.Language == "" || seen[seg.Language] {
+			continue
+		}
+		seen[seg.Language] = true
+		desc := seg.Language + " (voice " + string(seg.Voice)
+		if seg.Voice == "" {
+			desc = seg.Language + " (provider default voice"
+		}
+		if seg.Provider != "" {
+			desc += ", provider " + seg.Provider
+		}
+		descs = append(descs, desc+")")
+	}
+	return strings.Join(descs, ", ")
+}
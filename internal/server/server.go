@@ -4,25 +4,102 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+	"github.com/ybouhjira/claude-code-tts/internal/language"
+	"github.com/ybouhjira/claude-code-tts/internal/lexicon"
 	"github.com/ybouhjira/claude-code-tts/internal/logging"
+	"github.com/ybouhjira/claude-code-tts/internal/normalize"
+	"github.com/ybouhjira/claude-code-tts/internal/redact"
 	"github.com/ybouhjira/claude-code-tts/internal/tts"
+	"github.com/ybouhjira/claude-code-tts/internal/usage"
 )
 
+// maxTextLength is the most characters a single speak call accepts
+const maxTextLength = 100000
+
 // Server wraps the MCP server and worker pool
 type Server struct {
 	mcpServer  *server.MCPServer
 	workerPool *WorkerPool
+	synth      tts.Synthesizer
+	cache      *tts.Cache
+	budget     *tts.Budget
+	usageLog   *usage.Store
+	lexicon    *lexicon.Lexicon
+	normalizer *normalize.Normalizer
+	redactor   *redact.Redactor
+	routes     map[string]route // by language
 }
 
-// New creates a new TTS MCP server
-func New() (*Server, error) {
+// New creates a new TTS MCP server using the provider selected in cfg
+func New(cfg *config.Config) (*Server, error) {
 	logging.Info("Creating TTS MCP server...")
 
+	synth, err := tts.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TTS provider: %w", err)
+	}
+	logging.Info("Using TTS provider: %s", synth.Name())
+
+	usageLog := usage.Open(cfg.Usage, config.ProjectDir())
+	budget := tts.NewBudget(cfg)
+	if budget != nil {
+		budget.OnWarn(func(msg string) { logging.Warn("TTS budget: %s", msg) })
+	}
+	cache, err := tts.NewCache(cfg.Cache)
+	if err != nil {
+		logging.Warn("Audio cache disabled: %v", err)
+	}
+
+	// wrap adds usage logging, limits and caching to a provider
+	wrap := func(synth tts.Synthesizer) (tts.Synthesizer, error) {
+		// Log every provider request, timed without the rate limit waits
+		if usageLog != nil {
+			synth = tts.WithUsage(synth, tts.NewCostTable(cfg), func(u tts.Usage) {
+				if err := usageLog.Add(u); err != nil {
+					logging.Warn("%v", err)
+				}
+			})
+		}
+		synth, err := tts.WithLimits(synth, cfg, budget)
+		if err != nil {
+			return nil, err
+		}
+		return tts.WithCache(synth, cfg, cache), nil
+	}
+	synth, err = wrap(synth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TTS provider: %w", err)
+	}
+
+	lex, err := lexicon.Load(cfg.Lexicon, config.ProjectDir())
+	if err != nil {
+		logging.Warn("Pronunciation lexicon disabled: %v", err)
+	}
+	normalizer, err := normalize.New(cfg.Normalize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid normalize config: %w", err)
+	}
+	redactor, err := redact.New(cfg.Redact)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redact config: %w", err)
+	}
+	routes, err := newRoutes(cfg, synth, wrap)
+	if err != nil {
+		return nil, fmt.Errorf("invalid languages config: %w", err)
+	}
+
 	// Create worker pool (2 workers, queue size 50)
-	wp := NewWorkerPool(2, 50)
+	wp := NewWorkerPool(synth, 2, 50)
+	wp.SetRetryPolicy(tts.NewRetryPolicy(cfg.Retry))
+	wp.SetStreaming(!cfg.Streaming.Disabled)
+	wp.SetFirstSentence(!cfg.Streaming.NoFirstSentence)
 	wp.Start()
 	logging.Info("Worker pool created and started")
 
@@ -37,11 +114,19 @@ func New() (*Server, error) {
 	s := &Server{
 		mcpServer:  mcpSrv,
 		workerPool: wp,
+		synth:      synth,
+		cache:      cache,
+		budget:     budget,
+		usageLog:   usageLog,
+		lexicon:    lex,
+		normalizer: normalizer,
+		redactor:   redactor,
+		routes:     routes,
 	}
 
 	// Register tools
 	s.registerTools()
-	logging.Info("Tools registered: speak, tts_status, tts_pause, tts_resume, tts_clear")
+	logging.Info("Tools registered: speak, tts_status, tts_usage, tts_pause, tts_resume, tts_clear, tts_lexicon_add, tts_lexicon_list, tts_lexicon_remove")
 
 	return s, nil
 }
@@ -53,10 +138,29 @@ func (s *Server) registerTools() {
 		mcp.WithDescription("Convert text to speech and play it aloud. Use this to provide audio feedback to the user."),
 		mcp.WithString("text",
 			mcp.Required(),
-			mcp.Description("The text to convert to speech (max 4096 characters)"),
+			mcp.Description(fmt.Sprintf("The text to convert to speech (max %d characters; long text is split at sentence boundaries and spoken in order)", maxTextLength)),
 		),
 		mcp.WithString("voice",
-			mcp.Description("Voice to use: alloy, echo, fable, onyx, nova, shimmer (default: alloy)"),
+			mcp.Description(fmt.Sprintf("Voice to use: %s (default: %s)", s.voiceList(), s.defaultVoiceName())),
+		),
+		mcp.WithNumber("speed",
+			mcp.Description("Speaking speed from 0.25 to 4.0 (default: 1.0)"),
+			mcp.Min(tts.MinSpeed),
+			mcp.Max(tts.MaxSpeed),
+		),
+		mcp.WithString("response_format",
+			mcp.Description("Audio format: mp3, opus, aac, flac, wav or pcm (default: mp3)"),
+			mcp.Enum(formatNames()...),
+		),
+		mcp.WithString("instructions",
+			mcp.Description("How to deliver the speech, e.g. \"calm\" or \"urgent\" (gpt-4o-mini-tts only)"),
+		),
+		mcp.WithBoolean("normalize",
+			mcp.Description("Read markdown, code blocks, URLs and file paths the way a person would (default: true); false speaks the text as given"),
+		),
+		mcp.WithString("language",
+			mcp.Description("ISO 639-1 code of the language the whole text is in, e.g. fr (default: detected per sentence)"),
+			mcp.Enum(language.Languages()...),
 		),
 	)
 
@@ -69,6 +173,17 @@ func (s *Server) registerTools() {
 
 	s.mcpServer.AddTool(statusTool, s.handleStatus)
 
+	// tts_usage tool - returns usage and cost over a window
+	usageTool := mcp.NewTool("tts_usage",
+		mcp.WithDescription("Get the characters, audio and estimated cost of TTS for the current day, week or month, broken down by project and provider. Unlike tts_status, this covers every session."),
+		mcp.WithString("window",
+			mcp.Description("Period to report: day, week or month (default: month)"),
+			mcp.Enum(usage.Windows()...),
+		),
+	)
+
+	s.mcpServer.AddTool(usageTool, s.handleUsage)
+
 	// tts_pause tool - pauses job processing
 	pauseTool := mcp.NewTool("tts_pause",
 		mcp.WithDescription("Pause TTS processing. Queued jobs will wait until resumed."),
@@ -89,6 +204,53 @@ func (s *Server) registerTools() {
 	)
 
 	s.mcpServer.AddTool(clearTool, s.handleClear)
+
+	// tts_lexicon_add tool - adds a pronunciation substitution
+	lexiconAddTool := mcp.NewTool("tts_lexicon_add",
+		mcp.WithDescription("Teach TTS how to say a word, e.g. kubectl as \"kube control\" or SQL as \"sequel\". Replaces an existing entry with the same match."),
+		mcp.WithString("match",
+			mcp.Required(),
+			mcp.Description("Word or phrase to replace, or a regular expression if regex is true"),
+		),
+		mcp.WithString("replacement",
+			mcp.Required(),
+			mcp.Description("What to say instead; a regex replacement may use $1 for groups"),
+		),
+		mcp.WithBoolean("regex",
+			mcp.Description("Treat match as a regular expression (default: false, whole words only)"),
+		),
+		mcp.WithBoolean("case_sensitive",
+			mcp.Description("Only replace matches with the same case (default: false)"),
+		),
+		mcp.WithString("scope",
+			mcp.Description("global (all projects) or project (this repository only) (default: global)"),
+			mcp.Enum(lexicon.Global, lexicon.Project),
+		),
+	)
+
+	s.mcpServer.AddTool(lexiconAddTool, s.handleLexiconAdd)
+
+	// tts_lexicon_list tool - lists the pronunciation substitutions
+	lexiconListTool := mcp.NewTool("tts_lexicon_list",
+		mcp.WithDescription("List the pronunciation substitutions applied before speaking, project entries first."),
+	)
+
+	s.mcpServer.AddTool(lexiconListTool, s.handleLexiconList)
+
+	// tts_lexicon_remove tool - removes a pronunciation substitution
+	lexiconRemoveTool := mcp.NewTool("tts_lexicon_remove",
+		mcp.WithDescription("Remove a pronunciation substitution."),
+		mcp.WithString("match",
+			mcp.Required(),
+			mcp.Description("The match of the entry to remove, exactly as listed"),
+		),
+		mcp.WithString("scope",
+			mcp.Description("global or project (default: both)"),
+			mcp.Enum(lexicon.Global, lexicon.Project),
+		),
+	)
+
+	s.mcpServer.AddTool(lexiconRemoveTool, s.handleLexiconRemove)
 }
 
 // handleSpeak processes speak tool calls
@@ -102,43 +264,192 @@ func (s *Server) handleSpeak(ctx context.Context, request mcp.CallToolRequest) (
 		return mcp.NewToolResultError("text parameter is required"), nil
 	}
 
-	// Validate text length
-	if len(text) > 4096 {
-		logging.Warn("speak: text exceeds max length (%d chars)", len(text))
-		return mcp.NewToolResultError("text exceeds maximum length of 4096 characters"), nil
+	// Validate text length; longer than the provider accepts is fine, the
+	// worker splits it into chunks
+	chars := utf8.RuneCountInString(text)
+	if chars > maxTextLength {
+		logging.Warn("speak: text exceeds max length (%d chars)", chars)
+		return mcp.NewToolResultError(fmt.Sprintf("text exceeds maximum length of %d characters", maxTextLength)), nil
 	}
 
-	// Extract voice parameter (default to alloy)
-	voice := "alloy"
+	// Keep secrets and personal data on this machine
+	var redactions map[string]int
+	if s.redactor != nil {
+		text, redactions = s.redactor.Redact(text)
+	}
+
+	// Extract voice parameter (default to the provider's default voice)
+	voice := string(tts.DefaultVoice(s.synth))
 	if v, ok := request.Params.Arguments["voice"].(string); ok && v != "" {
 		voice = v
 	}
 
 	// Validate voice
-	if !tts.IsValidVoice(voice) {
+	if !tts.SupportsVoice(s.synth, tts.Voice(voice)) {
 		logging.Warn("speak: invalid voice '%s'", voice)
-		return mcp.NewToolResultError(fmt.Sprintf("invalid voice '%s'. Valid voices: alloy, echo, fable, onyx, nova, shimmer", voice)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("invalid voice '%s'. Valid voices: %s", voice, s.voiceList())), nil
 	}
 
-	logging.Info("speak: queueing job (voice=%s, text_len=%d, preview='%.50s...')", voice, len(text), text)
+	req := tts.Request{Text: text, Voice: tts.Voice(voice)}
+	if v, ok := request.Params.Arguments["speed"].(float64); ok {
+		req.Speed = v
+	}
+	if v, ok := request.Params.Arguments["response_format"].(string); ok {
+		req.Format = tts.Format(v)
+	}
+	if v, ok := request.Params.Arguments["instructions"].(string); ok {
+		req.Instructions = v
+	}
+	if err := req.Validate(); err != nil {
+		logging.Warn("speak: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	lang, _ := request.Params.Arguments["language"].(string)
+	if lang != "" && !slices.Contains(language.Languages(), lang) {
+		logging.Warn("speak: unknown language '%s'", lang)
+		return mcp.NewToolResultError(fmt.Sprintf("unknown language '%s'. Valid languages: %s", lang, strings.Join(language.Languages(), ", "))), nil
+	}
+	on, ok := request.Params.Arguments["normalize"].(bool)
+	normalizing := s.normalizer != nil && (!ok || on)
+
+	// Say product names and acronyms the way the team does, and keep
+	// normalizing from rewriting what the lexicon says
+	if s.lexicon != nil {
+		var mark func(string) string
+		if normalizing {
+			mark = normalize.Protect
+		}
+		req.Text = s.lexicon.ApplyMarked(req.Text, mark)
+	}
+
+	// Read markdown, code, URLs and paths the way a person would, and
+	// speak each language with the voice and provider routed to it
+	var segments []JobSegment
+	if s.routes != nil || lang != "" {
+		req.Text, segments = s.segments(req.Text, lang, req.Voice, normalizing)
+	} else if normalizing {
+		req.Text = s.normalizer.Normalize(req.Text)
+	}
+	if normalizing && req.Text == "" {
+		logging.Info("speak: nothing left to say after normalizing")
+		return mcp.NewToolResultText("Nothing to speak: the text was only code or markup."), nil
+	}
+
+	// Fail fast while the provider is rejecting our credentials or quota
+	if err := s.workerPool.Blocked(); err != nil {
+		logging.Warn("speak: %v", err)
+		return mcp.NewToolResultError(fmt.Sprintf("TTS %v", err)), nil
+	}
+
+	// Refuse up front once the spending caps are reached, pricing the
+	// text that will be sent, in each language at the provider that will
+	// speak it
+	var budgetWarning string
+	if s.budget != nil {
+		spends := []tts.Spend{{Synth: s.synth, Chars: utf8.RuneCountInString(req.Text)}}
+		if len(segments) > 0 {
+			spends = spends[:0]
+			for _, seg := range segments {
+				synth := seg.synth
+				if synth == nil {
+					synth = s.synth
+				}
+				spends = append(spends, tts.Spend{Synth: synth, Chars: seg.Chars})
+			}
+		}
+		if err := s.budget.Check(spends...); err != nil {
+			logging.Warn("speak: %v", err)
+			return mcp.NewToolResultError(fmt.Sprintf("TTS %v", err)), nil
+		}
+		budgetWarning = s.budget.Warning()
+	}
+
+	logging.Info("speak: queueing job (voice=%s, text_len=%d)", voice, len(req.Text))
+	if len(redactions) > 0 {
+		logging.Info("speak: redacted %s", redact.Summary(redactions))
+	}
+	if len(segments) > 0 {
+		logging.Info("speak: %d language segments: %s", len(segments), describeSegments(segments))
+	}
 
 	// Submit job to worker pool
-	job, err := s.workerPool.Submit(text, tts.Voice(voice))
+	job, err := s.workerPool.SubmitJob(req, JobOptions{Redactions: redactions, Segments: segments})
 	if err != nil {
 		logging.Error("speak: failed to queue job: %v", err)
 		return mcp.NewToolResultError(fmt.Sprintf("failed to queue TTS job: %v", err)), nil
 	}
 
 	logging.Info("speak: job queued successfully (ID: %s)", job.ID)
-	return mcp.NewToolResultText(fmt.Sprintf("TTS job queued successfully (ID: %s, voice: %s)", job.ID, voice)), nil
+	result := fmt.Sprintf("TTS job queued successfully (ID: %s, voice: %s)", job.ID, voice)
+	if langs := describeSegments(segments); langs != "" {
+		result += fmt.Sprintf("\nLanguages: %s", langs)
+	}
+	if len(redactions) > 0 {
+		result += fmt.Sprintf("\nRedacted before sending: %s", redact.Summary(redactions))
+	}
+	if budgetWarning != "" {
+		result += fmt.Sprintf("\nWarning: %s", budgetWarning)
+	}
+	return mcp.NewToolResultText(result), nil
+}
+
+// voiceList returns the provider's voices as a comma separated list
+func (s *Server) voiceList() string {
+	vl, ok := s.synth.(tts.VoiceLister)
+	if !ok {
+		return "any"
+	}
+	names := make([]string, 0)
+	for _, v := range vl.Voices() {
+		names = append(names, string(v))
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatNames returns the accepted response formats as strings
+func formatNames() []string {
+	names := make([]string, 0)
+	for _, f := range tts.ResponseFormats() {
+		names = append(names, string(f))
+	}
+	return names
+}
+
+// defaultVoiceName describes the voice used when none is given
+func (s *Server) defaultVoiceName() string {
+	if v := tts.DefaultVoice(s.synth); v != "" {
+		return string(v)
+	}
+	return "provider default"
+}
+
+// statusReport is the tts_status payload: the pool status plus the
+// server-wide state around it
+type statusReport struct {
+	PoolStatus
+	Cache  *tts.CacheStats   `json:"cache,omitempty"`
+	Budget *tts.BudgetStatus `json:"budget,omitempty"`
 }
 
 // handleStatus processes tts_status tool calls
 func (s *Server) handleStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	logging.Debug("Received tts_status tool call")
 	status := s.workerPool.GetStatus()
+	report := statusReport{PoolStatus: status}
+	if s.cache != nil {
+		stats := s.cache.Stats()
+		report.Cache = &stats
+	}
+	if s.budget != nil {
+		if budget, err := s.budget.Status(); err != nil {
+			logging.Warn("tts_status: %v", err)
+		} else {
+			report.Budget = &budget
+		}
+	}
 
-	jsonData, err := json.MarshalIndent(status, "", "  ")
+	jsonData, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
 		logging.Error("tts_status: failed to marshal: %v", err)
 		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal status: %v", err)), nil
@@ -149,6 +460,31 @@ func (s *Server) handleStatus(ctx context.Context, request mcp.CallToolRequest)
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
+// handleUsage processes tts_usage tool calls
+func (s *Server) handleUsage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logging.Debug("Received tts_usage tool call")
+	if s.usageLog == nil {
+		return mcp.NewToolResultError("usage logging is disabled (see \"usage\" in the config)"), nil
+	}
+
+	window := usage.Month
+	if v, ok := request.Params.Arguments["window"].(string); ok && v != "" {
+		window = v
+	}
+	summary, err := s.usageLog.Summary(window)
+	if err != nil {
+		logging.Warn("tts_usage: %v", err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	jsonData, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		logging.Error("tts_usage: failed to marshal: %v", err)
+		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal usage: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
 // handlePause processes tts_pause tool calls
 func (s *Server) handlePause(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	logging.Debug("Received tts_pause tool call")
@@ -170,6 +506,71 @@ func (s *Server) handleClear(ctx context.Context, request mcp.CallToolRequest) (
 	return mcp.NewToolResultText(fmt.Sprintf("Cleared %d pending jobs from the queue.", cleared)), nil
 }
 
+// handleLexiconAdd processes tts_lexicon_add tool calls
+func (s *Server) handleLexiconAdd(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logging.Debug("Received tts_lexicon_add tool call")
+	if s.lexicon == nil {
+		return mcp.NewToolResultError("the pronunciation lexicon is disabled (see \"lexicon\" in the config)"), nil
+	}
+
+	var entry lexicon.Entry
+	entry.Match, _ = request.Params.Arguments["match"].(string)
+	entry.Replacement, _ = request.Params.Arguments["replacement"].(string)
+	entry.Regex, _ = request.Params.Arguments["regex"].(bool)
+	entry.CaseSensitive, _ = request.Params.Arguments["case_sensitive"].(bool)
+	scope := lexicon.Global
+	if v, ok := request.Params.Arguments["scope"].(string); ok && v != "" {
+		scope = v
+	}
+
+	if err := s.lexicon.Add(scope, entry); err != nil {
+		logging.Warn("tts_lexicon_add: %v", err)
+		return mcp.NewToolResultError(fmt.Sprintf("failed to add lexicon entry: %v", err)), nil
+	}
+	logging.Info("tts_lexicon_add: %q -> %q (%s)", entry.Match, entry.Replacement, scope)
+	return mcp.NewToolResultText(fmt.Sprintf("Added to the %s lexicon: %q is now spoken as %q.", scope, entry.Match, entry.Replacement)), nil
+}
+
+// handleLexiconList processes tts_lexicon_list tool calls
+func (s *Server) handleLexiconList(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logging.Debug("Received tts_lexicon_list tool call")
+	if s.lexicon == nil {
+		return mcp.NewToolResultError("the pronunciation lexicon is disabled (see \"lexicon\" in the config)"), nil
+	}
+
+	jsonData, err := json.MarshalIndent(s.lexicon.List(), "", "  ")
+	if err != nil {
+		logging.Error("tts_lexicon_list: failed to marshal: %v", err)
+		return mcp.NewToolResultError(fmt.Sprintf("failed to marshal lexicon: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// handleLexiconRemove processes tts_lexicon_remove tool calls
+func (s *Server) handleLexiconRemove(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	logging.Debug("Received tts_lexicon_remove tool call")
+	if s.lexicon == nil {
+		return mcp.NewToolResultError("the pronunciation lexicon is disabled (see \"lexicon\" in the config)"), nil
+	}
+
+	match, ok := request.Params.Arguments["match"].(string)
+	if !ok || match == "" {
+		return mcp.NewToolResultError("match parameter is required"), nil
+	}
+	scope, _ := request.Params.Arguments["scope"].(string)
+
+	removed, err := s.lexicon.Remove(scope, match)
+	if err != nil {
+		logging.Warn("tts_lexicon_remove: %v", err)
+		return mcp.NewToolResultError(fmt.Sprintf("failed to remove lexicon entry: %v", err)), nil
+	}
+	if !removed {
+		return mcp.NewToolResultError(fmt.Sprintf("no lexicon entry matches %q", match)), nil
+	}
+	logging.Info("tts_lexicon_remove: %q", match)
+	return mcp.NewToolResultText(fmt.Sprintf("Removed %q from the lexicon.", match)), nil
+}
+
 // Start begins serving MCP requests via stdio
 func (s *Server) Start() error {
 	logging.Info("Starting stdio server (blocking)...")
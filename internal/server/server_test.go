@@ -3,15 +3,21 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+	"github.com/ybouhjira/claude-code-tts/internal/lexicon"
+	"github.com/ybouhjira/claude-code-tts/internal/normalize"
+	"github.com/ybouhjira/claude-code-tts/internal/tts"
+	"github.com/ybouhjira/claude-code-tts/internal/usage"
 )
 
 func TestNew(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -30,7 +36,7 @@ func TestNew(t *testing.T) {
 }
 
 func TestHandleSpeak_Success(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
@@ -64,7 +70,7 @@ func TestHandleSpeak_Success(t *testing.T) {
 }
 
 func TestHandleSpeak_DefaultVoice(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
@@ -91,7 +97,7 @@ func TestHandleSpeak_DefaultVoice(t *testing.T) {
 }
 
 func TestHandleSpeak_MissingText(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
@@ -118,7 +124,7 @@ func TestHandleSpeak_MissingText(t *testing.T) {
 }
 
 func TestHandleSpeak_EmptyText(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
@@ -139,14 +145,13 @@ func TestHandleSpeak_EmptyText(t *testing.T) {
 }
 
 func TestHandleSpeak_TextTooLong(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
 	defer srv.Shutdown()
 
-	// Create text longer than 4096 chars
-	longText := strings.Repeat("a", 4097)
+	longText := strings.Repeat("a", maxTextLength+1)
 
 	request := mcp.CallToolRequest{}
 	request.Params.Arguments = map[string]interface{}{
@@ -162,13 +167,146 @@ func TestHandleSpeak_TextTooLong(t *testing.T) {
 	}
 
 	content := result.Content[0].(mcp.TextContent)
-	if !strings.Contains(content.Text, "4096") {
-		t.Errorf("expected error to mention 4096 limit, got: %s", content.Text)
+	if !strings.Contains(content.Text, "100000") {
+		t.Errorf("expected error to mention the 100000 limit, got: %s", content.Text)
+	}
+}
+
+func TestHandleSpeak_LongTextAccepted(t *testing.T) {
+	srv, err := New(testConfig())
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+
+	// 1800 characters but 5400 bytes
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"text": strings.Repeat("日本語のテキスト。", 200),
+	}
+
+	result, err := srv.handleSpeak(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Errorf("expected long text to be accepted, got: %v", result.Content)
+	}
+}
+
+func TestHandleSpeak_BudgetExceeded(t *testing.T) {
+	cfg := testConfig()
+	cfg.Budget = config.BudgetConfig{
+		Path:       filepath.Join(t.TempDir(), "tts-budget.json"),
+		DailyChars: 10,
+		Costs:      map[string]float64{"fake": 15},
+	}
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"text": "Build completed successfully",
+	}
+
+	result, err := srv.handleSpeak(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected the speak call to be refused")
+	}
+	msg := result.Content[0].(mcp.TextContent).Text
+	if !strings.Contains(msg, "budget exceeded: daily character limit of 10 characters reached") {
+		t.Errorf("expected a clear budget error, got %q", msg)
+	}
+
+	status, err := srv.handleStatus(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var report statusReport
+	if err := json.Unmarshal([]byte(status.Content[0].(mcp.TextContent).Text), &report); err != nil {
+		t.Fatalf("failed to parse status JSON: %v", err)
+	}
+	if report.Budget == nil || report.Budget.DailyChars != 10 {
+		t.Errorf("expected the budget in status, got %+v", report.Budget)
+	}
+}
+
+func TestHandleSpeak_BudgetPricesSpokenText(t *testing.T) {
+	cfg := testConfig()
+	cfg.Normalize.Disabled = false
+	cfg.Budget = config.BudgetConfig{
+		Path:       filepath.Join(t.TempDir(), "tts-budget.json"),
+		DailyChars: 50,
+		Costs:      map[string]float64{"fake": 15},
+	}
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+	srv.workerPool.Pause()
+
+	// The code is summarized as "Code block, three lines of Go.", so
+	// only that counts
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"text": "Fixed it.\n\n```go\nfunc main() {\n\tfmt.Println(\"a long line of code\")\n}\n```",
+	}
+	result, err := srv.handleSpeak(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Errorf("expected the spoken text to fit the budget, got %v", result.Content)
+	}
+}
+
+func TestHandleSpeak_BudgetPerLanguage(t *testing.T) {
+	cfg := testConfig()
+	cfg.Budget = config.BudgetConfig{
+		Path:       filepath.Join(t.TempDir(), "tts-budget.json"),
+		DailyChars: 30,
+		Costs:      map[string]float64{"fake": 15, "fake-fr": 0},
+	}
+	cfg.Providers["fake-fr"] = config.ProviderConfig{Type: "fake"}
+	cfg.Languages = map[string]config.LanguageRoute{"fr": {Provider: "fake-fr"}}
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+	srv.workerPool.Pause()
+
+	speak := func(lang string) *mcp.CallToolResult {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]interface{}{
+			"text":     "La relecture échoue avec trois fichiers vides.",
+			"language": lang,
+		}
+		result, err := srv.handleSpeak(context.Background(), request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if result := speak("fr"); result.IsError {
+		t.Errorf("expected text routed to the free provider to be allowed, got %v", result.Content)
+	}
+	if result := speak("de"); !result.IsError {
+		t.Error("expected the same text on the paid provider to be refused")
 	}
 }
 
 func TestHandleSpeak_InvalidVoice(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
@@ -194,8 +332,88 @@ func TestHandleSpeak_InvalidVoice(t *testing.T) {
 	}
 }
 
+func TestHandleSpeak_SpeedFormatInstructions(t *testing.T) {
+	srv, err := New(testConfig())
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"text":            "Deploy finished",
+		"speed":           1.5,
+		"response_format": "opus",
+		"instructions":    "calm",
+	}
+
+	result, err := srv.handleSpeak(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got error: %v", result.Content)
+	}
+
+	status := srv.workerPool.GetStatus()
+	job := status.RecentJobs[len(status.RecentJobs)-1]
+	if job.Speed != 1.5 || job.Format != "opus" || job.Instructions != "calm" {
+		t.Errorf("expected options on job, got speed=%g format=%s instructions=%q", job.Speed, job.Format, job.Instructions)
+	}
+}
+
+func TestHandleSpeak_InvalidOptions(t *testing.T) {
+	srv, err := New(testConfig())
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+
+	for _, args := range []map[string]interface{}{
+		{"text": "Hello", "speed": 5.0},
+		{"text": "Hello", "speed": 0.1},
+		{"text": "Hello", "response_format": "ogg"},
+	} {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+
+		result, err := srv.handleSpeak(context.Background(), request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.IsError {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestHandleSpeak_ProviderBlocked(t *testing.T) {
+	srv, err := New(testConfig())
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+
+	srv.workerPool.block(&tts.APIError{StatusCode: 401, Kind: tts.ErrUnauthorized, Message: "invalid api key"})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"text": "Hello"}
+
+	result, err := srv.handleSpeak(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error while the provider is blocked")
+	}
+	content := result.Content[0].(mcp.TextContent)
+	if !strings.Contains(content.Text, "invalid api key") {
+		t.Errorf("expected provider error in result, got: %s", content.Text)
+	}
+}
+
 func TestHandleStatus(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
@@ -230,8 +448,385 @@ func TestHandleStatus(t *testing.T) {
 	}
 }
 
+func TestHandleStatus_Cache(t *testing.T) {
+	cfg := testConfig()
+	cfg.Cache = config.CacheConfig{Dir: t.TempDir()}
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+
+	req := tts.Request{Text: "Build completed", Voice: tts.VoiceNova}
+	for i := 0; i < 2; i++ {
+		if _, err := srv.synth.Synthesize(context.Background(), req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	result, err := srv.handleStatus(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var report statusReport
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &report); err != nil {
+		t.Fatalf("failed to parse status JSON: %v", err)
+	}
+	if report.Cache == nil {
+		t.Fatal("expected cache stats in status")
+	}
+	if report.Cache.Hits != 1 || report.Cache.Misses != 1 || report.Cache.Entries != 1 {
+		t.Errorf("unexpected cache stats %+v", *report.Cache)
+	}
+	if report.WorkerCount != 2 {
+		t.Errorf("expected pool status alongside the cache, got worker_count %d", report.WorkerCount)
+	}
+}
+
+func TestHandleUsage(t *testing.T) {
+	cfg := testConfig()
+	cfg.Usage = config.UsageConfig{Path: filepath.Join(t.TempDir(), "tts-usage.jsonl")}
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+
+	req := tts.Request{Text: "Build completed", Voice: tts.VoiceNova}
+	if _, err := srv.synth.Synthesize(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"window": "day"}
+	result, err := srv.handleUsage(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected tool error: %v", result.Content)
+	}
+	var summary usage.Summary
+	if err := json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &summary); err != nil {
+		t.Fatalf("failed to parse usage JSON: %v", err)
+	}
+	if summary.Window != "day" || summary.Total.Requests != 1 || summary.Total.Chars != len(req.Text) {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+	if len(summary.Projects) != 1 || len(summary.Providers) != 1 || summary.Providers[0].Name != "fake" {
+		t.Errorf("expected one project and provider, got %+v and %+v", summary.Projects, summary.Providers)
+	}
+
+	request.Params.Arguments = map[string]interface{}{"window": "year"}
+	if result, _ := srv.handleUsage(context.Background(), request); !result.IsError {
+		t.Error("expected an error for an unknown window")
+	}
+}
+
+func TestHandleUsage_Disabled(t *testing.T) {
+	srv, err := New(testConfig())
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+
+	result, err := srv.handleUsage(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Error("expected an error while usage logging is disabled")
+	}
+}
+
+func TestHandleLexicon(t *testing.T) {
+	t.Setenv("CLAUDE_PROJECT_DIR", t.TempDir())
+	cfg := testConfig()
+	cfg.Lexicon = config.LexiconConfig{Path: filepath.Join(t.TempDir(), "tts-lexicon.json")}
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+	srv.workerPool.Pause()
+
+	call := func(handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := handler(context.Background(), request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if result := call(srv.handleLexiconAdd, map[string]interface{}{"match": "kubectl", "replacement": "kube control"}); result.IsError {
+		t.Fatalf("unexpected error: %v", result.Content)
+	}
+	if result := call(srv.handleLexiconAdd, map[string]interface{}{"match": "SQL", "replacement": "sequel", "scope": "project", "case_sensitive": true}); result.IsError {
+		t.Fatalf("unexpected error: %v", result.Content)
+	}
+	if result := call(srv.handleLexiconAdd, map[string]interface{}{"match": "(", "replacement": "x", "regex": true}); !result.IsError {
+		t.Error("expected an invalid regex to be rejected")
+	}
+
+	var entries []lexicon.ScopedEntry
+	list := call(srv.handleLexiconList, nil)
+	if err := json.Unmarshal([]byte(list.Content[0].(mcp.TextContent).Text), &entries); err != nil {
+		t.Fatalf("failed to parse lexicon JSON: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Scope != lexicon.Project || !entries[0].CaseSensitive {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+
+	call(srv.handleSpeak, map[string]interface{}{"text": "kubectl query in SQL"})
+	jobs := srv.workerPool.GetStatus().RecentJobs
+	if len(jobs) != 1 || jobs[0].Text != "kube control query in sequel" {
+		t.Errorf("expected the lexicon to be applied, got %+v", jobs)
+	}
+
+	// Normalizing leaves the replacements alone
+	srv.normalizer, _ = normalize.New(config.NormalizeConfig{})
+	call(srv.handleLexiconAdd, map[string]interface{}{"match": "pg16", "replacement": "PostgreSQL 16"})
+	call(srv.handleSpeak, map[string]interface{}{"text": "Moved `pg16` to 2 nodes."})
+	if job := srv.workerPool.GetStatus().RecentJobs[1]; job.Text != "Moved PostgreSQL 16 to two nodes." {
+		t.Errorf("expected the replacement to survive normalizing, got %q", job.Text)
+	}
+
+	if result := call(srv.handleLexiconRemove, map[string]interface{}{"match": "kubectl"}); result.IsError {
+		t.Errorf("unexpected error: %v", result.Content)
+	}
+	if result := call(srv.handleLexiconRemove, map[string]interface{}{"match": "kubectl"}); !result.IsError {
+		t.Error("expected an error removing a missing entry")
+	}
+}
+
+func TestHandleSpeak_Normalize(t *testing.T) {
+	cfg := testConfig()
+	cfg.Normalize = config.NormalizeConfig{}
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+	srv.workerPool.Pause()
+
+	speak := func(args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := srv.handleSpeak(context.Background(), request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	text := "**Fixed** the race in `internal/server/worker.go:142`."
+	speak(map[string]interface{}{"text": text})
+	speak(map[string]interface{}{"text": text, "normalize": false})
+	jobs := srv.workerPool.GetStatus().RecentJobs
+	if len(jobs) != 2 {
+		t.Fatalf("expected 2 jobs, got %d", len(jobs))
+	}
+	texts := map[string]bool{jobs[0].Text: true, jobs[1].Text: true}
+	if !texts["Fixed the race in worker dot go line one hundred forty-two."] || !texts[text] {
+		t.Errorf("expected one normalized and one raw job, got %q and %q", jobs[0].Text, jobs[1].Text)
+	}
+
+	srv.normalizer, _ = normalize.New(config.NormalizeConfig{CodeBlocks: normalize.CodeSkip})
+	result := speak(map[string]interface{}{"text": "```go\nfunc main() {}\n```"})
+	if result.IsError || !strings.Contains(result.Content[0].(mcp.TextContent).Text, "Nothing to speak") {
+		t.Errorf("expected nothing to be spoken, got %+v", result.Content)
+	}
+	if n := len(srv.workerPool.GetStatus().RecentJobs); n != 2 {
+		t.Errorf("expected no job for code alone, got %d jobs", n)
+	}
+
+	cfg.Normalize.CodeBlocks = "mumble"
+	if _, err := New(cfg); err == nil {
+		t.Error("expected an unknown code_blocks mode to be rejected")
+	}
+}
+
+func TestHandleSpeak_Redact(t *testing.T) {
+	cfg := testConfig()
+	cfg.Redact = config.RedactConfig{}
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+	srv.workerPool.Pause()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{
+		"text": "Use sk-proj-Abc123def456GHI789jkl012MNO and mail jane@example.com.",
+	}
+	result, err := srv.handleSpeak(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %v", err, result.Content)
+	}
+	if text := result.Content[0].(mcp.TextContent).Text; !strings.Contains(text, "Redacted before sending: 1 api_key, 1 email") {
+		t.Errorf("expected the redactions in the result, got %q", text)
+	}
+
+	jobs := srv.workerPool.GetStatus().RecentJobs
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(jobs))
+	}
+	if jobs[0].Text != "Use redacted API key and mail redacted email." {
+		t.Errorf("expected the secrets to be redacted, got %q", jobs[0].Text)
+	}
+	if jobs[0].Redactions["api_key"] != 1 || jobs[0].Redactions["email"] != 1 {
+		t.Errorf("expected the redactions to be recorded, got %v", jobs[0].Redactions)
+	}
+}
+
+func TestHandleSpeak_Languages(t *testing.T) {
+	cfg := testConfig()
+	cfg.Normalize = config.NormalizeConfig{}
+	cfg.Languages = map[string]config.LanguageRoute{"fr": {Voice: "nova"}}
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+	srv.workerPool.Pause()
+
+	speak := func(args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = args
+		result, err := srv.handleSpeak(context.Background(), request)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	result := speak(map[string]interface{}{
+		"text": "The build is green and all tests pass.\nPetite remarque : la relecture échoue avec 3 fichiers vides.",
+	})
+	if text := result.Content[0].(mcp.TextContent).Text; result.IsError || !strings.Contains(text, "Languages: fr (voice nova)") {
+		t.Errorf("expected the French route in the result, got %q", text)
+	}
+	job := srv.workerPool.GetStatus().RecentJobs[0]
+	if len(job.Segments) != 2 {
+		t.Fatalf("expected 2 segments, got %+v", job.Segments)
+	}
+	if seg := job.Segments[0]; seg.Language != "" || seg.Voice != tts.VoiceAlloy {
+		t.Errorf("expected the English text with the requested voice, got %+v", seg)
+	}
+	if seg := job.Segments[1]; seg.Language != "fr" || seg.Voice != tts.VoiceNova || seg.text != "Petite remarque: la relecture échoue avec trois fichiers vides." {
+		t.Errorf("expected the French text read in French with nova, got %+v %q", seg, seg.text)
+	}
+
+	// Text in one unrouted language is spoken as before
+	speak(map[string]interface{}{"text": "The build is green and all tests pass."})
+	if job := srv.workerPool.GetStatus().RecentJobs[1]; job.Segments != nil || job.Voice != tts.VoiceAlloy {
+		t.Errorf("expected no segments for English text, got %+v", job.Segments)
+	}
+
+	speak(map[string]interface{}{"text": "OK.", "language": "fr"})
+	if job := srv.workerPool.GetStatus().RecentJobs[2]; len(job.Segments) != 1 || job.Segments[0].Voice != tts.VoiceNova {
+		t.Errorf("expected the given language to be routed, got %+v", job.Segments)
+	}
+	if result := speak(map[string]interface{}{"text": "OK.", "language": "xx"}); !result.IsError {
+		t.Error("expected an unknown language to be rejected")
+	}
+
+	// SSML is never split, so each part keeps its tags
+	ssml := "<speak>The build finished without any errors today. Le déploiement est terminé maintenant.</speak>"
+	speak(map[string]interface{}{"text": ssml, "normalize": false})
+	if job := srv.workerPool.GetStatus().RecentJobs[3]; job.Segments != nil || job.Text != ssml {
+		t.Errorf("expected the SSML whole with the requested voice, got %q in %+v", job.Text, job.Segments)
+	}
+	speak(map[string]interface{}{"text": ssml, "normalize": false, "language": "fr"})
+	if job := srv.workerPool.GetStatus().RecentJobs[4]; len(job.Segments) != 1 || job.Segments[0].text != ssml {
+		t.Errorf("expected the SSML whole in French, got %+v", job.Segments)
+	}
+
+	cfg.Providers["fake-fr"] = config.ProviderConfig{Type: "fake"}
+	cfg.Languages = map[string]config.LanguageRoute{"fr": {Provider: "fake-fr"}}
+	other, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer other.Shutdown()
+	if r := other.routes["fr"]; r.synth == nil || r.voice != tts.VoiceAlloy {
+		t.Errorf("expected French on its own provider with its default voice, got %+v", r)
+	}
+
+	cfg.Languages = map[string]config.LanguageRoute{"xx": {Voice: "nova"}}
+	if _, err := New(cfg); err == nil {
+		t.Error("expected an unknown language in the config to be rejected")
+	}
+	cfg.Languages = map[string]config.LanguageRoute{"fr": {Voice: "mumble"}}
+	if _, err := New(cfg); err == nil {
+		t.Error("expected an invalid voice for a language to be rejected")
+	}
+	cfg.Languages = map[string]config.LanguageRoute{"fr": {Provider: "nope"}}
+	if _, err := New(cfg); err == nil {
+		t.Error("expected an unknown provider for a language to be rejected")
+	}
+}
+
+func TestNewRoutes_SharesChainProvider(t *testing.T) {
+	cfg := testConfig()
+	var extra config.Config
+	if err := json.Unmarshal([]byte(`{"providers": {"fake-fr": {"type": "fake", "fail": true}}}`), &extra); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Providers["fake-fr"] = extra.Providers["fake-fr"]
+	cfg.Fallbacks = []string{"fake-fr"}
+	cfg.Breaker = config.BreakerConfig{FailureThreshold: 1}
+	cfg.Languages = map[string]config.LanguageRoute{"fr": {Provider: "fake-fr"}}
+	srv, err := New(cfg)
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+
+	if _, err := srv.routes["fr"].synth.Synthesize(context.Background(), tts.Request{Text: "Bonjour."}); err == nil {
+		t.Fatal("expected the failing provider to fail")
+	}
+	if h := srv.synth.(tts.HealthReporter).Health()[1]; h.Name != "fake-fr" || h.State != "open" {
+		t.Errorf("expected the route to trip the chain's breaker for fake-fr, got %+v", h)
+	}
+}
+
+func TestHandleSpeak_JobOutlivesRequest(t *testing.T) {
+	srv, err := New(testConfig())
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	defer srv.Shutdown()
+	srv.workerPool.Pause()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]interface{}{"text": "Keep going."}
+	if result, err := srv.handleSpeak(ctx, request); err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %v", err, result)
+	}
+	cancel()
+
+	srv.workerPool.historyMu.RLock()
+	job := srv.workerPool.jobHistory[0]
+	srv.workerPool.historyMu.RUnlock()
+	if job.ctx.Err() != nil {
+		t.Fatal("expected the job to outlive the speak request")
+	}
+	srv.workerPool.Clear()
+	if job.ctx.Err() == nil {
+		t.Error("expected tts_clear to cancel the job")
+	}
+}
+
 func TestHandleStatus_AfterJobs(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
@@ -265,7 +860,7 @@ func TestHandleStatus_AfterJobs(t *testing.T) {
 }
 
 func TestServer_Shutdown(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
@@ -287,7 +882,7 @@ func TestServer_Shutdown(t *testing.T) {
 
 // Table-driven tests for handleSpeak with various inputs
 func TestHandleSpeak_TableDriven(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
@@ -345,10 +940,10 @@ func TestHandleSpeak_TableDriven(t *testing.T) {
 		},
 		{
 			name:        "text too long",
-			text:        strings.Repeat("a", 4097),
+			text:        strings.Repeat("a", maxTextLength+1),
 			voice:       "alloy",
 			expectError: true,
-			errorMsg:    "4096",
+			errorMsg:    "maximum length",
 		},
 		{
 			name:        "invalid voice",
@@ -358,8 +953,8 @@ func TestHandleSpeak_TableDriven(t *testing.T) {
 			errorMsg:    "invalid voice",
 		},
 		{
-			name:        "exactly 4096 chars (boundary test)",
-			text:        strings.Repeat("a", 4096),
+			name:        "exactly max chars (boundary test)",
+			text:        strings.Repeat("a", maxTextLength),
 			voice:       "alloy",
 			expectError: false,
 		},
@@ -405,7 +1000,7 @@ func TestHandleSpeak_TableDriven(t *testing.T) {
 }
 
 func TestHandleStatus_ReturnsValidJSON(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
@@ -448,7 +1043,7 @@ func TestHandleStatus_ReturnsValidJSON(t *testing.T) {
 }
 
 func TestHandleSpeak_NonStringVoiceType(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
@@ -478,7 +1073,7 @@ func TestHandleSpeak_NonStringVoiceType(t *testing.T) {
 }
 
 func TestHandleSpeak_NonStringTextType(t *testing.T) {
-	srv, err := New()
+	srv, err := New(testConfig())
 	if err != nil {
 		t.Fatalf("failed to create server: %v", err)
 	}
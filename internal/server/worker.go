@@ -1,10 +1,15 @@
 package server
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/ybouhjira/claude-code-tts/internal/audio"
 	"github.com/ybouhjira/claude-code-tts/internal/logging"
@@ -13,18 +18,128 @@ import (
 
 // Job represents a TTS job in the queue
 type Job struct {
-	ID        string    `json:"id"`
-	Text      string    `json:"text"`
-	Voice     tts.Voice `json:"voice"`
-	CreatedAt time.Time `json:"created_at"`
-	Status    string    `json:"status"` // pending, processing, completed, failed
-	Error     string    `json:"error,omitempty"`
-	mu        sync.RWMutex
+	ID           string     `json:"id"`
+	Text         string     `json:"text"`
+	Voice        tts.Voice  `json:"voice"`
+	Speed        float64    `json:"speed,omitempty"`
+	Format       tts.Format `json:"format,omitempty"`
+	Instructions string     `json:"instructions,omitempty"`
+	Provider     string     `json:"provider,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	Status       string     `json:"status"` // pending, processing, completed, failed
+	Error        string     `json:"error,omitempty"`
+	ErrorKind    string     `json:"error_kind,omitempty"` // unauthorized, rate_limited, quota_exceeded, invalid_input, server_error, budget_exceeded
+	Retries      []JobRetry `json:"retries,omitempty"`
+	Chunks       []JobChunk `json:"chunks,omitempty"` // set when the text is split
+	mu           sync.RWMutex
+
+	// Redactions counts the secrets removed from the text, by kind
+	Redactions map[string]int `json:"redactions,omitempty"`
+
+	// Segments are the parts of the text in each language, set when
+	// languages are routed to their own voice or provider
+	Segments []JobSegment `json:"segments,omitempty"`
+
+	// TimeToFirstAudioMS is the time from the worker picking up the job
+	// until the first audio was ready to play
+	TimeToFirstAudioMS int64 `json:"time_to_first_audio_ms,omitempty"`
+
+	// ctx is cancelled by Stop or Clear; cancel releases it once the job
+	// is done. A job outlives the speak call that queued it, so the MCP
+	// request does not cancel it.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// JobRetry records a synthesis attempt that failed and was retried
+type JobRetry struct {
+	Chunk   int       `json:"chunk,omitempty"` // 1-based, for split jobs
+	Attempt int       `json:"attempt"`
+	Error   string    `json:"error"`
+	WaitMS  int64     `json:"wait_ms"`
+	At      time.Time `json:"at"`
+}
+
+// Chunk states
+const (
+	ChunkPending      = "pending"
+	ChunkSynthesizing = "synthesizing"
+	ChunkReady        = "ready"
+	ChunkPlaying      = "playing"
+	ChunkDone         = "done"
+	ChunkFailed       = "failed"
+)
+
+// JobChunk is the progress of one piece of a long text
+type JobChunk struct {
+	Chars    int    `json:"chars"`
+	Status   string `json:"status"`
+	Language string `json:"language,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// JobSegment is a part of a job's text in one language, spoken with the
+// voice and provider routed to that language
+type JobSegment struct {
+	Language string    `json:"language,omitempty"` // empty for unrouted text
+	Chars    int       `json:"chars"`
+	Voice    tts.Voice `json:"voice"`
+	Provider string    `json:"provider,omitempty"` // set when routed to another provider
+
+	text  string
+	synth tts.Synthesizer // nil for the pool's synthesizer
+}
+
+// NewJobSegment returns a segment of text spoken with voice by synth, or
+// by the pool's synthesizer if synth is nil
+func NewJobSegment(language, text string, voice tts.Voice, synth tts.Synthesizer) JobSegment {
+	seg := JobSegment{
+		Language: language,
+		Chars:    utf8.RuneCountInString(text),
+		Voice:    voice,
+		text:     text,
+		synth:    synth,
+	}
+	if synth != nil {
+		seg.Provider = synth.Name()
+	}
+	return seg
+}
+
+// piece is a chunk of a job's text with the voice and synthesizer that
+// speak it
+type piece struct {
+	text     string
+	language string
+	voice    tts.Voice
+	synth    tts.Synthesizer
+	routed   bool // synth is not the pool's synthesizer
+	plays    bool // synth speaks while synthesizing
 }
 
+// maxParallelChunks bounds how many chunks of a job are synthesized at once
+const maxParallelChunks = 3
+
+// request returns the synthesis request for text with the job's settings
+func (j *Job) request(text string) tts.Request {
+	return tts.Request{
+		Text:         text,
+		Voice:        j.Voice,
+		Speed:        j.Speed,
+		Format:       j.Format,
+		Instructions: j.Instructions,
+	}
+}
+
+// providerBlockDuration is how long jobs are skipped after the provider
+// rejects the credentials or reports an exhausted quota
+const providerBlockDuration = 5 * time.Minute
+
 // WorkerPool manages TTS job processing
 type WorkerPool struct {
-	ttsClient   *tts.Client
+	ttsClient   tts.Synthesizer
+	retry       tts.RetryPolicy
 	audioPlayer *audio.Player
 	jobs        chan *Job
 	jobHistory  []*Job
@@ -36,19 +151,53 @@ type WorkerPool struct {
 	paused      atomic.Bool
 	wg          sync.WaitGroup
 	shutdown    chan struct{}
+	ctx         context.Context
+	cancel      context.CancelFunc
+
+	streaming atomic.Bool
+	firstSent atomic.Bool
+
+	blockMu      sync.Mutex
+	blockErr     error
+	blockedUntil time.Time
 }
 
-// NewWorkerPool creates a new worker pool
-func NewWorkerPool(workerCount, queueSize int) *WorkerPool {
-	return &WorkerPool{
-		ttsClient:   tts.NewClient(),
+// NewWorkerPool creates a new worker pool that synthesizes with synth
+func NewWorkerPool(synth tts.Synthesizer, workerCount, queueSize int) *WorkerPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	wp := &WorkerPool{
+		ttsClient:   synth,
+		retry:       tts.DefaultRetryPolicy(),
 		audioPlayer: audio.NewPlayer(),
 		jobs:        make(chan *Job, queueSize),
 		jobHistory:  make([]*Job, 0),
 		workerCount: workerCount,
 		queueSize:   queueSize,
 		shutdown:    make(chan struct{}),
+		ctx:         ctx,
+		cancel:      cancel,
 	}
+	wp.streaming.Store(true)
+	wp.firstSent.Store(true)
+	return wp
+}
+
+// SetStreaming sets whether the first chunk of each job is played while
+// it is still being synthesized. It is on by default.
+func (wp *WorkerPool) SetStreaming(on bool) {
+	wp.streaming.Store(on)
+}
+
+// SetFirstSentence sets whether the first sentence of a longer text is
+// synthesized on its own, so playback starts sooner. It is on by default.
+func (wp *WorkerPool) SetFirstSentence(on bool) {
+	wp.firstSent.Store(on)
+}
+
+// SetRetryPolicy sets how transient synthesis failures are retried.
+// It must be called before Start.
+func (wp *WorkerPool) SetRetryPolicy(p tts.RetryPolicy) {
+	wp.retry = p
 }
 
 // Start launches the worker goroutines
@@ -60,9 +209,11 @@ func (wp *WorkerPool) Start() {
 	logging.Info("Started %d TTS workers with queue size %d", wp.workerCount, wp.queueSize)
 }
 
-// Stop gracefully shuts down the worker pool
+// Stop shuts down the worker pool, aborting the synthesis or playback
+// in progress
 func (wp *WorkerPool) Stop() {
 	logging.Info("Stopping worker pool...")
+	wp.cancel()
 	close(wp.shutdown)
 	close(wp.jobs)
 	wp.wg.Wait()
@@ -104,35 +255,113 @@ func (wp *WorkerPool) worker(id int) {
 func (wp *WorkerPool) processJob(job *Job) {
 	startTime := time.Now()
 	logging.Info("Job %s: starting (voice=%s, text_len=%d)", job.ID, job.Voice, len(job.Text))
+	defer job.cancel()
 
 	job.mu.Lock()
+	if job.Status == "cancelled" {
+		job.mu.Unlock()
+		logging.Debug("Job %s: cancelled before start", job.ID)
+		return
+	}
 	job.Status = "processing"
 	job.mu.Unlock()
 
-	// Synthesize audio
-	logging.Debug("Job %s: calling OpenAI TTS API...", job.ID)
-	audioData, err := wp.ttsClient.Synthesize(job.Text, job.Voice)
-	if err != nil {
+	// Don't hammer a provider that rejected our credentials or quota
+	if err := wp.Blocked(); err != nil {
 		job.mu.Lock()
 		job.Status = "failed"
 		job.Error = err.Error()
+		job.ErrorKind = tts.ErrorKind(err)
 		job.mu.Unlock()
 		wp.failed.Add(1)
-		logging.Error("Job %s: TTS synthesis failed after %v: %v", job.ID, time.Since(startTime), err)
+		logging.Warn("Job %s: skipped: %v", job.ID, err)
 		return
 	}
-	logging.Debug("Job %s: received %d bytes of audio", job.ID, len(audioData))
 
-	// Play audio (mutex protected - only one plays at a time)
-	logging.Debug("Job %s: starting audio playback...", job.ID)
-	if err := wp.audioPlayer.Play(audioData); err != nil {
+	// Long texts are split below the provider's limit; the chunks are
+	// synthesized in parallel and played in order
+	chunks := wp.pieces(job)
+	if len(chunks) > 1 {
 		job.mu.Lock()
-		job.Status = "failed"
-		job.Error = err.Error()
+		job.Chunks = make([]JobChunk, len(chunks))
+		for i, p := range chunks {
+			job.Chunks[i] = JobChunk{Chars: utf8.RuneCountInString(p.text), Status: ChunkPending, Language: p.language}
+		}
 		job.mu.Unlock()
-		wp.failed.Add(1)
-		logging.Error("Job %s: playback failed after %v: %v", job.ID, time.Since(startTime), err)
-		return
+		logging.Info("Job %s: split into %d chunks", job.ID, len(chunks))
+	}
+
+	synthCtx, cancelSynth := context.WithCancel(job.ctx)
+	results, wait := wp.synthesizeChunks(synthCtx, job, chunks)
+	defer wait()
+	defer cancelSynth()
+
+	for i := range chunks {
+		var r chunkResult
+		if chunks[i].plays {
+			// The engine speaks while synthesizing, so it takes its turn
+			// in order and holds the player like any other audio
+			_ = wp.audioPlayer.PlayWith(func() error {
+				r = wp.synthesizeChunk(synthCtx, job, i, len(chunks), chunks[i])
+				return r.err
+			})
+		} else {
+			r = <-results[i]
+		}
+		if job.ctx.Err() != nil {
+			if r.stream != nil {
+				r.stream.Body.Close()
+			}
+			wp.markCancelled(job, startTime)
+			return
+		}
+		if r.err != nil {
+			err := r.err
+			if len(chunks) > 1 {
+				err = fmt.Errorf("chunk %d/%d: %w", i+1, len(chunks), err)
+			}
+			job.mu.Lock()
+			job.Status = "failed"
+			job.Error = err.Error()
+			job.ErrorKind = tts.ErrorKind(err)
+			job.mu.Unlock()
+			wp.failed.Add(1)
+			logging.Error("Job %s: TTS synthesis failed after %v: %v", job.ID, time.Since(startTime), err)
+			// A failover chain has per-provider breakers instead, and a
+			// language's provider failing says nothing about the pool's
+			_, chained := wp.ttsClient.(tts.HealthReporter)
+			if !chained && !chunks[i].routed && (errors.Is(err, tts.ErrUnauthorized) || errors.Is(err, tts.ErrQuotaExceeded)) {
+				wp.block(err)
+			}
+			return
+		}
+		job.mu.Lock()
+		if i == 0 {
+			job.TimeToFirstAudioMS = time.Since(startTime).Milliseconds()
+		}
+		if r.provider != "" {
+			job.Provider = r.provider
+		}
+		job.mu.Unlock()
+
+		// Play audio (mutex protected - only one plays at a time)
+		logging.Debug("Job %s: starting audio playback...", job.ID)
+		job.setChunk(i, ChunkPlaying, "")
+		if err := wp.play(job, r); err != nil {
+			if job.ctx.Err() != nil {
+				wp.markCancelled(job, startTime)
+				return
+			}
+			job.setChunk(i, ChunkFailed, err.Error())
+			job.mu.Lock()
+			job.Status = "failed"
+			job.Error = err.Error()
+			job.mu.Unlock()
+			wp.failed.Add(1)
+			logging.Error("Job %s: playback failed after %v: %v", job.ID, time.Since(startTime), err)
+			return
+		}
+		job.setChunk(i, ChunkDone, "")
 	}
 
 	job.mu.Lock()
@@ -142,15 +371,257 @@ func (wp *WorkerPool) processJob(job *Job) {
 	logging.Info("Job %s: completed successfully in %v", job.ID, time.Since(startTime))
 }
 
+// pieces splits the job's text, or each of its language segments, below
+// the limit of the provider that speaks it. With the first sentence fast
+// path on, the first sentence is a piece of its own, unless the engine
+// speaks itself and gains nothing from it.
+func (wp *WorkerPool) pieces(job *Job) []piece {
+	segments := job.Segments
+	if len(segments) == 0 {
+		segments = []JobSegment{{text: job.Text}}
+	}
+
+	var pieces []piece
+	for i, seg := range segments {
+		p := piece{language: seg.Language, voice: seg.Voice, synth: seg.synth, routed: seg.synth != nil}
+		if p.voice == "" && !p.routed {
+			p.voice = job.Voice
+		}
+		if p.synth == nil {
+			p.synth = wp.ttsClient
+		}
+		p.plays = tts.PlaysAudio(p.synth)
+		chunks := tts.Chunk(seg.text, tts.MaxTextLength(p.synth))
+		if i == 0 && wp.firstSent.Load() && !p.plays {
+			chunks = tts.SplitFirstSentence(chunks)
+		}
+		for _, text := range chunks {
+			p.text = text
+			pieces = append(pieces, p)
+		}
+	}
+	return pieces
+}
+
+// chunkResult is the outcome of synthesizing one chunk: complete audio,
+// or a stream for the first chunk
+type chunkResult struct {
+	audio    *tts.Audio
+	stream   *tts.AudioStream
+	provider string
+	err      error
+}
+
+// play plays a chunk. A stream is piped to the player as it arrives when
+// the player can read its format from stdin; otherwise it is downloaded
+// first.
+func (wp *WorkerPool) play(job *Job, r chunkResult) error {
+	audio := r.audio
+	if r.stream != nil {
+		defer r.stream.Body.Close()
+		if r.stream.Format != tts.FormatNone && wp.audioPlayer.CanStream(string(r.stream.Format)) {
+			logging.Debug("Job %s: streaming %s audio to the player", job.ID, r.stream.Format)
+			return wp.audioPlayer.PlayStream(job.ctx, r.stream.Body, string(r.stream.Format), r.stream.SampleRate)
+		}
+		data, err := io.ReadAll(r.stream.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read audio: %w", err)
+		}
+		audio = &tts.Audio{Data: data, Format: r.stream.Format}
+	}
+
+	logging.Debug("Job %s: received %d bytes of %s audio", job.ID, len(audio.Data), audio.Format)
+	if audio.Format == tts.FormatNone {
+		logging.Debug("Job %s: provider played the audio itself", job.ID)
+		return nil
+	}
+	return wp.audioPlayer.PlayContext(job.ctx, audio.Data, string(audio.Format))
+}
+
+// synthesizeChunks synthesizes the chunks of job, up to maxParallelChunks
+// at a time, starting with the first. Each chunk's result arrives on its
+// own channel so playback can proceed in order. Chunks whose engine speaks
+// itself are left to processJob, which synthesizes them in turn. wait
+// blocks until every synthesis goroutine has returned.
+func (wp *WorkerPool) synthesizeChunks(ctx context.Context, job *Job, chunks []piece) (results []chan chunkResult, wait func()) {
+	results = make([]chan chunkResult, len(chunks))
+	for i := range results {
+		results[i] = make(chan chunkResult, 1)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallelChunks)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, p := range chunks {
+			if p.plays {
+				continue
+			}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				for _, ch := range results[i:] {
+					ch <- chunkResult{err: ctx.Err()}
+				}
+				return
+			}
+
+			wg.Add(1)
+			go func(i int, p piece) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i] <- wp.synthesizeChunk(ctx, job, i, len(chunks), p)
+			}(i, p)
+		}
+	}()
+	return results, wg.Wait
+}
+
+// synthesizeChunk synthesizes chunk i of n with retries, recording its
+// progress on the job. With streaming on, the first chunk is returned as a
+// stream as soon as the provider starts answering; the others are
+// synthesized completely while earlier chunks play.
+func (wp *WorkerPool) synthesizeChunk(ctx context.Context, job *Job, i, n int, p piece) chunkResult {
+	chunk := 0
+	if n > 1 {
+		chunk = i + 1
+	}
+	job.setChunk(i, ChunkSynthesizing, "")
+	logging.Debug("Job %s: calling %s TTS provider (chunk %d/%d)...", job.ID, p.synth.Name(), i+1, n)
+
+	onRetry := func(a tts.RetryAttempt) {
+		logging.Warn("Job %s: attempt %d failed, retrying in %v: %v", job.ID, a.Attempt, a.Wait, a.Err)
+		job.mu.Lock()
+		job.Retries = append(job.Retries, JobRetry{
+			Chunk:   chunk,
+			Attempt: a.Attempt,
+			Error:   a.Err.Error(),
+			WaitMS:  a.Wait.Milliseconds(),
+			At:      time.Now(),
+		})
+		job.mu.Unlock()
+	}
+
+	var r chunkResult
+	req := job.request(p.text)
+	req.Voice = p.voice
+	if i == 0 && wp.streaming.Load() {
+		// Raw PCM needs no decoding, so playback starts soonest
+		if req.Format == "" && wp.audioPlayer.CanStream(string(tts.FormatPCM)) {
+			req.Format = tts.FormatPCM
+		}
+		r.stream, r.err = wp.retry.Stream(ctx, p.synth, req, onRetry)
+		if r.stream != nil {
+			r.provider = r.stream.Provider
+			// Wait for the first bytes, so time to first audio counts
+			// audio rather than response headers; read errors surface
+			// during playback
+			br := bufio.NewReader(r.stream.Body)
+			_, _ = br.Peek(1)
+			r.stream.Body = struct {
+				io.Reader
+				io.Closer
+			}{br, r.stream.Body}
+		}
+	} else {
+		r.audio, r.err = wp.retry.Synthesize(ctx, p.synth, req, onRetry)
+		if r.audio != nil {
+			r.provider = r.audio.Provider
+		}
+	}
+	if r.err != nil {
+		if ctx.Err() == nil {
+			job.setChunk(i, ChunkFailed, r.err.Error())
+		}
+		return r
+	}
+
+	job.mu.Lock()
+	if i < len(job.Chunks) {
+		job.Chunks[i].Status = ChunkReady
+		job.Chunks[i].Provider = r.provider
+	}
+	job.mu.Unlock()
+	return r
+}
+
+// setChunk updates the progress of chunk i, if the job is split
+func (j *Job) setChunk(i int, status, errMsg string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if i < len(j.Chunks) {
+		j.Chunks[i].Status = status
+		j.Chunks[i].Error = errMsg
+	}
+}
+
+// markCancelled records that a job was aborted mid-flight
+func (wp *WorkerPool) markCancelled(job *Job, startTime time.Time) {
+	job.mu.Lock()
+	job.Status = "cancelled"
+	if job.Error == "" {
+		job.Error = "cancelled"
+	}
+	job.mu.Unlock()
+	logging.Info("Job %s: cancelled after %v", job.ID, time.Since(startTime))
+}
+
+// block stops calling the provider for providerBlockDuration
+func (wp *WorkerPool) block(err error) {
+	wp.blockMu.Lock()
+	defer wp.blockMu.Unlock()
+	wp.blockErr = err
+	wp.blockedUntil = time.Now().Add(providerBlockDuration)
+	logging.Warn("Provider blocked until %s: %v", wp.blockedUntil.Format(time.TimeOnly), err)
+}
+
+// Blocked returns why the provider is not being called, or nil.
+// The returned error wraps the provider error, so tts.ErrorKind works on it.
+func (wp *WorkerPool) Blocked() error {
+	wp.blockMu.Lock()
+	defer wp.blockMu.Unlock()
+	if wp.blockErr == nil || time.Now().After(wp.blockedUntil) {
+		return nil
+	}
+	return fmt.Errorf("provider unavailable until %s: %w", wp.blockedUntil.Format(time.TimeOnly), wp.blockErr)
+}
+
 // Submit adds a new job to the queue
 func (wp *WorkerPool) Submit(text string, voice tts.Voice) (*Job, error) {
+	return wp.SubmitRequest(tts.Request{Text: text, Voice: voice})
+}
+
+// SubmitRequest adds a new job with speed, format and instructions to the
+// queue. Only Clear and Stop abort it once queued.
+func (wp *WorkerPool) SubmitRequest(req tts.Request) (*Job, error) {
+	return wp.SubmitJob(req, JobOptions{})
+}
+
+// JobOptions describe how a job's text was prepared
+type JobOptions struct {
+	// Redactions counts the secrets removed from the text, by kind
+	Redactions map[string]int
+	// Segments split the text by language; empty speaks req.Text with
+	// req.Voice
+	Segments []JobSegment
+}
+
+// SubmitJob is SubmitRequest for text prepared as opts describes
+func (wp *WorkerPool) SubmitJob(req tts.Request, opts JobOptions) (*Job, error) {
 	job := &Job{
-		ID:        fmt.Sprintf("job-%d", time.Now().UnixNano()),
-		Text:      text,
-		Voice:     voice,
-		CreatedAt: time.Now(),
-		Status:    "pending",
+		ID:           fmt.Sprintf("job-%d", time.Now().UnixNano()),
+		Text:         req.Text,
+		Voice:        req.Voice,
+		Speed:        req.Speed,
+		Format:       req.Format,
+		Instructions: req.Instructions,
+		CreatedAt:    time.Now(),
+		Status:       "pending",
+		Redactions:   opts.Redactions,
+		Segments:     opts.Segments,
 	}
+	job.ctx, job.cancel = context.WithCancel(wp.ctx)
 
 	logging.Debug("Submit: created job %s", job.ID)
 
@@ -170,6 +641,7 @@ func (wp *WorkerPool) Submit(text string, voice tts.Voice) (*Job, error) {
 		logging.Debug("Submit: job %s queued (queue_pending=%d)", job.ID, len(wp.jobs))
 		return job, nil
 	default:
+		job.cancel()
 		job.Status = "failed"
 		job.Error = "queue is full"
 		logging.Warn("Submit: queue full, rejecting job %s", job.ID)
@@ -179,14 +651,19 @@ func (wp *WorkerPool) Submit(text string, voice tts.Voice) (*Job, error) {
 
 // Status returns current worker pool statistics
 type PoolStatus struct {
-	WorkerCount    int    `json:"worker_count"`
-	QueueSize      int    `json:"queue_size"`
-	QueuePending   int    `json:"queue_pending"`
-	TotalProcessed int64  `json:"total_processed"`
-	TotalFailed    int64  `json:"total_failed"`
-	IsPlaying      bool   `json:"is_playing"`
-	IsPaused       bool   `json:"is_paused"`
-	RecentJobs     []*Job `json:"recent_jobs,omitempty"`
+	WorkerCount    int                  `json:"worker_count"`
+	QueueSize      int                  `json:"queue_size"`
+	QueuePending   int                  `json:"queue_pending"`
+	TotalProcessed int64                `json:"total_processed"`
+	TotalFailed    int64                `json:"total_failed"`
+	IsPlaying      bool                 `json:"is_playing"`
+	IsPaused       bool                 `json:"is_paused"`
+	ProviderError  string               `json:"provider_error,omitempty"`
+	Providers      []tts.ProviderHealth `json:"providers,omitempty"`
+	RecentJobs     []*Job               `json:"recent_jobs,omitempty"`
+
+	// AvgTimeToFirstAudioMS averages TimeToFirstAudioMS over the job history
+	AvgTimeToFirstAudioMS int64 `json:"avg_time_to_first_audio_ms,omitempty"`
 }
 
 // GetStatus returns the current pool status
@@ -197,22 +674,52 @@ func (wp *WorkerPool) GetStatus() PoolStatus {
 	if start < 0 {
 		start = 0
 	}
+	var ttfaTotal, ttfaCount int64
+	for _, job := range wp.jobHistory {
+		job.mu.RLock()
+		if job.TimeToFirstAudioMS > 0 {
+			ttfaTotal += job.TimeToFirstAudioMS
+			ttfaCount++
+		}
+		job.mu.RUnlock()
+	}
 	// Create deep copies to avoid race conditions with workers modifying jobs
 	for _, job := range wp.jobHistory[start:] {
 		job.mu.RLock()
 		jobCopy := &Job{
-			ID:        job.ID,
-			Text:      job.Text,
-			Voice:     job.Voice,
-			CreatedAt: job.CreatedAt,
-			Status:    job.Status,
-			Error:     job.Error,
+			ID:           job.ID,
+			Text:         job.Text,
+			Voice:        job.Voice,
+			Speed:        job.Speed,
+			Format:       job.Format,
+			Instructions: job.Instructions,
+			Provider:     job.Provider,
+			CreatedAt:    job.CreatedAt,
+			Status:       job.Status,
+			Error:        job.Error,
+			ErrorKind:    job.ErrorKind,
+			Retries:      append([]JobRetry(nil), job.Retries...),
+			Chunks:       append([]JobChunk(nil), job.Chunks...),
+
+			TimeToFirstAudioMS: job.TimeToFirstAudioMS,
+			Redactions:         job.Redactions,
+			Segments:           job.Segments,
 		}
 		job.mu.RUnlock()
 		recentJobs = append(recentJobs, jobCopy)
 	}
 	wp.historyMu.RUnlock()
 
+	providerError := ""
+	if err := wp.Blocked(); err != nil {
+		providerError = err.Error()
+	}
+
+	var providers []tts.ProviderHealth
+	if hr, ok := wp.ttsClient.(tts.HealthReporter); ok {
+		providers = hr.Health()
+	}
+
 	return PoolStatus{
 		WorkerCount:    wp.workerCount,
 		QueueSize:      wp.queueSize,
@@ -221,8 +728,20 @@ func (wp *WorkerPool) GetStatus() PoolStatus {
 		TotalFailed:    wp.failed.Load(),
 		IsPlaying:      wp.audioPlayer.IsPlaying(),
 		IsPaused:       wp.paused.Load(),
+		ProviderError:  providerError,
+		Providers:      providers,
 		RecentJobs:     recentJobs,
+
+		AvgTimeToFirstAudioMS: avg(ttfaTotal, ttfaCount),
+	}
+}
+
+// avg returns total/count, or 0 when count is 0
+func avg(total, count int64) int64 {
+	if count == 0 {
+		return 0
 	}
+	return total / count
 }
 
 // Pause pauses job processing (queued jobs will wait)
@@ -237,7 +756,8 @@ func (wp *WorkerPool) Resume() {
 	logging.Info("Worker pool resumed")
 }
 
-// Clear removes all pending jobs from the queue
+// Clear removes all pending jobs from the queue and aborts the jobs being
+// synthesized or played. It returns the number of pending jobs removed.
 func (wp *WorkerPool) Clear() int {
 	cleared := 0
 	for {
@@ -247,10 +767,27 @@ func (wp *WorkerPool) Clear() int {
 			job.Status = "cancelled"
 			job.Error = "queue cleared"
 			job.mu.Unlock()
+			job.cancel()
 			cleared++
 		default:
 			logging.Info("Cleared %d pending jobs from queue", cleared)
+			wp.abortInFlight()
 			return cleared
 		}
 	}
 }
+
+// abortInFlight cancels the jobs currently being processed
+func (wp *WorkerPool) abortInFlight() {
+	wp.historyMu.RLock()
+	defer wp.historyMu.RUnlock()
+	for _, job := range wp.jobHistory {
+		job.mu.Lock()
+		if job.Status == "processing" {
+			job.Error = "queue cleared"
+			job.cancel()
+			logging.Info("Job %s: aborted by clear", job.ID)
+		}
+		job.mu.Unlock()
+	}
+}
@@ -1,16 +1,98 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/ybouhjira/claude-code-tts/internal/config"
 	"github.com/ybouhjira/claude-code-tts/internal/tts"
 )
 
+func init() {
+	tts.Register("fake", func(cfg config.ProviderConfig) (tts.Synthesizer, error) {
+		var opts struct {
+			Fail bool `json:"fail"`
+		}
+		if err := cfg.Decode(&opts); err != nil {
+			return nil, err
+		}
+		f := newFakeSynthesizer()
+		f.name = cfg.Name
+		if opts.Fail {
+			f.err = &tts.APIError{StatusCode: 503, Kind: tts.ErrServerError, Message: "outage"}
+		}
+		return f, nil
+	})
+}
+
+// fakeSynthesizer is an in-memory Synthesizer for tests
+type fakeSynthesizer struct {
+	name     string
+	mu       sync.Mutex
+	calls    []tts.Request
+	err      error
+	failures []error          // returned by the first calls, in order
+	block    chan struct{}    // if set, calls wait on it or on cancellation
+	started  chan tts.Request // if set, receives each request as it starts
+}
+
+func newFakeSynthesizer() *fakeSynthesizer {
+	return &fakeSynthesizer{name: "fake"}
+}
+
+func (f *fakeSynthesizer) Name() string { return f.name }
+
+func (f *fakeSynthesizer) Voices() []tts.Voice { return tts.OpenAIVoices() }
+
+func (f *fakeSynthesizer) Synthesize(ctx context.Context, req tts.Request) (*tts.Audio, error) {
+	if f.started != nil {
+		f.started <- req
+	}
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, req)
+	if len(f.failures) > 0 {
+		err := f.failures[0]
+		f.failures = f.failures[1:]
+		return nil, err
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &tts.Audio{Data: []byte("fake-audio"), Format: tts.FormatMP3}, nil
+}
+
+// testConfig returns a config that selects the fake provider
+func testConfig() *config.Config {
+	cfg := config.Default()
+	cfg.Provider = "fake"
+	cfg.Cache.Disabled = true
+	cfg.Usage.Disabled = true
+	cfg.Lexicon.Disabled = true
+	cfg.Normalize.Disabled = true
+	cfg.Redact.Disabled = true
+	return cfg
+}
+
 func TestNewWorkerPool(t *testing.T) {
-	wp := NewWorkerPool(3, 100)
+	wp := NewWorkerPool(newFakeSynthesizer(), 3, 100)
 
 	if wp.workerCount != 3 {
 		t.Errorf("expected workerCount 3, got %d", wp.workerCount)
@@ -30,7 +112,7 @@ func TestNewWorkerPool(t *testing.T) {
 }
 
 func TestWorkerPool_Submit(t *testing.T) {
-	wp := NewWorkerPool(2, 10)
+	wp := NewWorkerPool(newFakeSynthesizer(), 2, 10)
 	// Don't start workers - we just want to test submission
 
 	job, err := wp.Submit("Hello, world!", tts.VoiceAlloy)
@@ -60,7 +142,7 @@ func TestWorkerPool_Submit(t *testing.T) {
 
 func TestWorkerPool_Submit_QueueFull(t *testing.T) {
 	// Create a pool with queue size 2
-	wp := NewWorkerPool(1, 2)
+	wp := NewWorkerPool(newFakeSynthesizer(), 1, 2)
 	// Don't start workers so queue fills up
 
 	// Fill the queue
@@ -91,7 +173,7 @@ func TestWorkerPool_Submit_QueueFull(t *testing.T) {
 }
 
 func TestWorkerPool_JobHistory(t *testing.T) {
-	wp := NewWorkerPool(1, 10)
+	wp := NewWorkerPool(newFakeSynthesizer(), 1, 10)
 
 	// Submit multiple jobs
 	for i := 0; i < 5; i++ {
@@ -111,7 +193,7 @@ func TestWorkerPool_JobHistory(t *testing.T) {
 }
 
 func TestWorkerPool_JobHistoryLimit(t *testing.T) {
-	wp := NewWorkerPool(1, 150)
+	wp := NewWorkerPool(newFakeSynthesizer(), 1, 150)
 
 	// Submit more than 100 jobs (history limit)
 	for i := 0; i < 105; i++ {
@@ -131,7 +213,7 @@ func TestWorkerPool_JobHistoryLimit(t *testing.T) {
 }
 
 func TestWorkerPool_GetStatus(t *testing.T) {
-	wp := NewWorkerPool(2, 50)
+	wp := NewWorkerPool(newFakeSynthesizer(), 2, 50)
 
 	// Submit a job without starting workers
 	_, _ = wp.Submit("Test job", tts.VoiceNova)
@@ -162,7 +244,7 @@ func TestWorkerPool_GetStatus(t *testing.T) {
 }
 
 func TestWorkerPool_GetStatus_RecentJobsLimit(t *testing.T) {
-	wp := NewWorkerPool(1, 50)
+	wp := NewWorkerPool(newFakeSynthesizer(), 1, 50)
 
 	// Submit 15 jobs
 	for i := 0; i < 15; i++ {
@@ -178,7 +260,7 @@ func TestWorkerPool_GetStatus_RecentJobsLimit(t *testing.T) {
 }
 
 func TestWorkerPool_StartStop(t *testing.T) {
-	wp := NewWorkerPool(2, 10)
+	wp := NewWorkerPool(newFakeSynthesizer(), 2, 10)
 
 	wp.Start()
 
@@ -238,7 +320,7 @@ func TestPoolStatus_JSON(t *testing.T) {
 }
 
 func TestWorkerPool_ConcurrentSubmit(t *testing.T) {
-	wp := NewWorkerPool(2, 100)
+	wp := NewWorkerPool(newFakeSynthesizer(), 2, 100)
 
 	var wg sync.WaitGroup
 	successCount := 0
@@ -287,7 +369,7 @@ func TestNewWorkerPool_TableDriven(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			wp := NewWorkerPool(tt.workerCount, tt.queueSize)
+			wp := NewWorkerPool(newFakeSynthesizer(), tt.workerCount, tt.queueSize)
 
 			if wp == nil {
 				t.Fatal("expected worker pool to be created")
@@ -315,7 +397,7 @@ func TestNewWorkerPool_TableDriven(t *testing.T) {
 }
 
 func TestWorkerPool_Submit_AllVoices(t *testing.T) {
-	wp := NewWorkerPool(1, 10)
+	wp := NewWorkerPool(newFakeSynthesizer(), 1, 10)
 
 	voices := []tts.Voice{
 		tts.VoiceAlloy,
@@ -341,7 +423,7 @@ func TestWorkerPool_Submit_AllVoices(t *testing.T) {
 
 func TestWorkerPool_Submit_ErrorWhenQueueFullExact(t *testing.T) {
 	// Create a pool with queue size 3 to test exact boundary
-	wp := NewWorkerPool(1, 3)
+	wp := NewWorkerPool(newFakeSynthesizer(), 1, 3)
 
 	// Fill exactly 3 jobs
 	for i := 0; i < 3; i++ {
@@ -368,7 +450,7 @@ func TestWorkerPool_Submit_ErrorWhenQueueFullExact(t *testing.T) {
 }
 
 func TestWorkerPool_GetStatus_Counters(t *testing.T) {
-	wp := NewWorkerPool(2, 50)
+	wp := NewWorkerPool(newFakeSynthesizer(), 2, 50)
 
 	// Submit multiple jobs
 	for i := 0; i < 5; i++ {
@@ -393,7 +475,7 @@ func TestWorkerPool_GetStatus_Counters(t *testing.T) {
 }
 
 func TestWorkerPool_GetStatus_RecentJobsCopy(t *testing.T) {
-	wp := NewWorkerPool(1, 10)
+	wp := NewWorkerPool(newFakeSynthesizer(), 1, 10)
 
 	// Submit a job
 	job, _ := wp.Submit("Test job", tts.VoiceNova)
@@ -436,7 +518,7 @@ func TestWorkerPool_StartStop_MultipleWorkers(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			wp := NewWorkerPool(tt.workerCount, 10)
+			wp := NewWorkerPool(newFakeSynthesizer(), tt.workerCount, 10)
 			wp.Start()
 
 			// Give workers time to start
@@ -504,7 +586,7 @@ func TestJob_ThreadSafeStatusUpdate(t *testing.T) {
 }
 
 func TestWorkerPool_SubmitReturnsJobWithTimestamp(t *testing.T) {
-	wp := NewWorkerPool(1, 10)
+	wp := NewWorkerPool(newFakeSynthesizer(), 1, 10)
 
 	beforeSubmit := time.Now()
 	job, err := wp.Submit("Test", tts.VoiceAlloy)
@@ -523,3 +605,596 @@ func TestWorkerPool_SubmitReturnsJobWithTimestamp(t *testing.T) {
 			job.CreatedAt, beforeSubmit, afterSubmit)
 	}
 }
+
+func TestWorkerPool_ProcessJob_UsesSynthesizer(t *testing.T) {
+	synth := newFakeSynthesizer()
+	wp := NewWorkerPool(synth, 1, 10)
+
+	job, err := wp.Submit("Injected provider", tts.VoiceEcho)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wp.processJob(<-wp.jobs)
+
+	synth.mu.Lock()
+	defer synth.mu.Unlock()
+	if len(synth.calls) != 1 {
+		t.Fatalf("expected 1 synthesize call, got %d", len(synth.calls))
+	}
+	if synth.calls[0].Text != job.Text || synth.calls[0].Voice != tts.VoiceEcho {
+		t.Errorf("unexpected request: %+v", synth.calls[0])
+	}
+}
+
+func TestWorkerPool_ProcessJob_SynthesizeError(t *testing.T) {
+	synth := newFakeSynthesizer()
+	synth.err = errors.New("provider down")
+	wp := NewWorkerPool(synth, 1, 10)
+
+	job, _ := wp.Submit("Will fail", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+
+	job.mu.RLock()
+	defer job.mu.RUnlock()
+	if job.Status != "failed" {
+		t.Errorf("expected status 'failed', got %s", job.Status)
+	}
+	if job.Error != "provider down" {
+		t.Errorf("expected error 'provider down', got %q", job.Error)
+	}
+}
+
+func TestWorkerPool_ProcessJob_PassesOptions(t *testing.T) {
+	synth := newFakeSynthesizer()
+	wp := NewWorkerPool(synth, 1, 10)
+
+	if _, err := wp.SubmitRequest(tts.Request{Text: "hi", Voice: tts.VoiceNova, Speed: 1.5, Format: tts.FormatWAV, Instructions: "urgent"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wp.processJob(<-wp.jobs)
+
+	synth.mu.Lock()
+	defer synth.mu.Unlock()
+	if len(synth.calls) != 1 {
+		t.Fatalf("expected 1 synthesize call, got %d", len(synth.calls))
+	}
+	got := synth.calls[0]
+	if got.Speed != 1.5 || got.Format != tts.FormatWAV || got.Instructions != "urgent" {
+		t.Errorf("options not passed to synthesizer: %+v", got)
+	}
+}
+
+func TestWorkerPool_ProcessJob_RecordsRetries(t *testing.T) {
+	synth := newFakeSynthesizer()
+	synth.failures = []error{
+		&tts.APIError{StatusCode: 429, Kind: tts.ErrRateLimited, Message: "rate limited"},
+		&tts.APIError{StatusCode: 503, Kind: tts.ErrServerError, Message: "overloaded"},
+	}
+	wp := NewWorkerPool(synth, 1, 10)
+	wp.SetRetryPolicy(tts.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+
+	if _, err := wp.Submit("Retry me", tts.VoiceNova); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wp.processJob(<-wp.jobs)
+
+	synth.mu.Lock()
+	calls := len(synth.calls)
+	synth.mu.Unlock()
+	if calls != 3 {
+		t.Errorf("expected 3 synthesize calls, got %d", calls)
+	}
+
+	job := wp.GetStatus().RecentJobs[0]
+	if len(job.Retries) != 2 {
+		t.Fatalf("expected 2 recorded retries, got %d", len(job.Retries))
+	}
+	if job.Retries[0].Attempt != 1 || job.Retries[0].Error != "rate limited" {
+		t.Errorf("unexpected first retry: %+v", job.Retries[0])
+	}
+	if job.Retries[1].Attempt != 2 || job.Retries[1].Error != "overloaded" {
+		t.Errorf("unexpected second retry: %+v", job.Retries[1])
+	}
+}
+
+func TestWorkerPool_QuotaErrorBlocksProvider(t *testing.T) {
+	synth := newFakeSynthesizer()
+	synth.err = &tts.APIError{StatusCode: 429, Code: "insufficient_quota", Kind: tts.ErrQuotaExceeded, Message: "quota exceeded"}
+	wp := NewWorkerPool(synth, 1, 10)
+
+	first, _ := wp.Submit("First", tts.VoiceAlloy)
+	second, _ := wp.Submit("Second", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+	wp.processJob(<-wp.jobs)
+
+	synth.mu.Lock()
+	calls := len(synth.calls)
+	synth.mu.Unlock()
+	if calls != 1 {
+		t.Errorf("expected the provider to be called once, got %d", calls)
+	}
+
+	first.mu.RLock()
+	if first.ErrorKind != "quota_exceeded" {
+		t.Errorf("expected error kind quota_exceeded, got %q", first.ErrorKind)
+	}
+	first.mu.RUnlock()
+
+	second.mu.RLock()
+	if second.Status != "failed" || second.ErrorKind != "quota_exceeded" || !strings.Contains(second.Error, "provider unavailable") {
+		t.Errorf("expected second job to be skipped, got status=%s kind=%s error=%q", second.Status, second.ErrorKind, second.Error)
+	}
+	second.mu.RUnlock()
+
+	if status := wp.GetStatus(); !strings.Contains(status.ProviderError, "quota exceeded") {
+		t.Errorf("expected provider error in status, got %q", status.ProviderError)
+	}
+}
+
+func TestWorkerPool_InvalidInputDoesNotBlock(t *testing.T) {
+	synth := newFakeSynthesizer()
+	synth.failures = []error{&tts.APIError{StatusCode: 400, Kind: tts.ErrInvalidInput, Message: "bad input"}}
+	wp := NewWorkerPool(synth, 1, 10)
+
+	job, _ := wp.Submit("Bad", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+
+	job.mu.RLock()
+	kind := job.ErrorKind
+	job.mu.RUnlock()
+	if kind != "invalid_input" {
+		t.Errorf("expected invalid_input, got %q", kind)
+	}
+	if err := wp.Blocked(); err != nil {
+		t.Errorf("expected provider not to be blocked, got %v", err)
+	}
+}
+
+// newBlockingPool returns a started pool whose synthesizer blocks until
+// cancelled, and a channel reporting when synthesis has begun
+func newBlockingPool(t *testing.T) (*WorkerPool, chan tts.Request) {
+	t.Helper()
+	synth := newFakeSynthesizer()
+	synth.block = make(chan struct{})
+	synth.started = make(chan tts.Request, 10)
+	wp := NewWorkerPool(synth, 1, 10)
+	wp.Start()
+	return wp, synth.started
+}
+
+// waitForStatus polls a job until it reaches status or the test times out
+func waitForStatus(t *testing.T, job *Job, status string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job.mu.RLock()
+		got := job.Status
+		job.mu.RUnlock()
+		if got == status {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not reach status %q", job.ID, status)
+}
+
+func TestWorkerPool_Stop_AbortsInFlight(t *testing.T) {
+	wp, started := newBlockingPool(t)
+
+	job, _ := wp.Submit("Long synthesis", tts.VoiceAlloy)
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		wp.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop() waited for the in-flight request")
+	}
+	waitForStatus(t, job, "cancelled")
+}
+
+func TestWorkerPool_Clear_AbortsInFlight(t *testing.T) {
+	wp, started := newBlockingPool(t)
+	defer wp.Stop()
+
+	job, _ := wp.Submit("Long synthesis", tts.VoiceAlloy)
+	<-started
+
+	wp.Clear()
+	waitForStatus(t, job, "cancelled")
+
+	job.mu.RLock()
+	defer job.mu.RUnlock()
+	if job.Error != "queue cleared" {
+		t.Errorf("expected 'queue cleared' error, got %q", job.Error)
+	}
+	if wp.failed.Load() != 0 {
+		t.Errorf("expected cancelled job not to count as failed, got %d", wp.failed.Load())
+	}
+}
+
+func TestWorkerPool_ChainHealthInStatus(t *testing.T) {
+	primary := newFakeSynthesizer()
+	primary.err = &tts.APIError{StatusCode: 503, Kind: tts.ErrServerError, Message: "outage"}
+	chain := tts.NewChain(config.BreakerConfig{FailureThreshold: 1}, primary, &namedSynthesizer{newFakeSynthesizer(), "espeak"})
+
+	wp := NewWorkerPool(chain, 1, 10)
+	wp.SetRetryPolicy(tts.RetryPolicy{MaxAttempts: 1})
+
+	job, _ := wp.Submit("Fail over", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+
+	job.mu.RLock()
+	provider := job.Provider
+	job.mu.RUnlock()
+	if provider != "espeak" {
+		t.Errorf("expected job to record the fallback provider, got %q", provider)
+	}
+
+	status := wp.GetStatus()
+	if len(status.Providers) != 2 {
+		t.Fatalf("expected 2 providers in status, got %d", len(status.Providers))
+	}
+	if status.Providers[0].Name != "fake" || status.Providers[0].State != tts.BreakerOpen {
+		t.Errorf("expected the failing provider to be open, got %+v", status.Providers[0])
+	}
+	if status.Providers[1].State != tts.BreakerClosed {
+		t.Errorf("expected the fallback to be closed, got %+v", status.Providers[1])
+	}
+}
+
+// namedSynthesizer renames a fake synthesizer
+type namedSynthesizer struct {
+	*fakeSynthesizer
+	name string
+}
+
+func (n *namedSynthesizer) Name() string { return n.name }
+
+// chunkSynthesizer has a small input limit and returns audio that needs
+// no playback, so split jobs can complete in tests
+type chunkSynthesizer struct {
+	mu      sync.Mutex
+	limit   int
+	texts   []string
+	voices  []tts.Voice
+	fail    string        // text that fails with a server error
+	delay   time.Duration // added to every call
+	block   chan struct{} // if set, calls wait on it
+	started chan string   // if set, receives each text as it starts
+}
+
+func (c *chunkSynthesizer) Name() string { return "chunky" }
+
+func (c *chunkSynthesizer) MaxTextLength() int { return c.limit }
+
+func (c *chunkSynthesizer) Synthesize(ctx context.Context, req tts.Request) (*tts.Audio, error) {
+	time.Sleep(c.delay)
+	if c.started != nil {
+		c.started <- req.Text
+	}
+	if c.block != nil {
+		select {
+		case <-c.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.texts = append(c.texts, req.Text)
+	c.voices = append(c.voices, req.Voice)
+	if req.Text == c.fail {
+		return nil, &tts.APIError{StatusCode: 400, Kind: tts.ErrInvalidInput, Message: "bad chunk"}
+	}
+	return &tts.Audio{Format: tts.FormatNone}, nil
+}
+
+func TestWorkerPool_SplitsLongText(t *testing.T) {
+	synth := &chunkSynthesizer{limit: 21}
+	wp := NewWorkerPool(synth, 1, 10)
+
+	job, _ := wp.Submit("First sentence here. Second sentence here. Third one.", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+
+	status := wp.GetStatus()
+	got := status.RecentJobs[0]
+	if got.ID != job.ID || got.Status != "completed" {
+		t.Fatalf("expected job to complete, got %q: %s", got.Status, got.Error)
+	}
+	if len(got.Chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %+v", got.Chunks)
+	}
+	for i, c := range got.Chunks {
+		if c.Status != ChunkDone {
+			t.Errorf("chunk %d: expected status %q, got %q", i, ChunkDone, c.Status)
+		}
+	}
+	if got.Chunks[0].Chars != len("First sentence here.") {
+		t.Errorf("unexpected chunk length %d", got.Chunks[0].Chars)
+	}
+	if len(synth.texts) != 3 {
+		t.Errorf("expected 3 synthesis calls, got %v", synth.texts)
+	}
+}
+
+func TestWorkerPool_ShortTextNotSplit(t *testing.T) {
+	wp := NewWorkerPool(&chunkSynthesizer{limit: 100}, 1, 10)
+
+	wp.Submit("Short.", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+
+	got := wp.GetStatus().RecentJobs[0]
+	if got.Status != "completed" || got.Chunks != nil {
+		t.Errorf("expected an unsplit completed job, got %q with chunks %+v", got.Status, got.Chunks)
+	}
+}
+
+func TestWorkerPool_ChunksSynthesizedInParallel(t *testing.T) {
+	synth := &chunkSynthesizer{limit: 12, block: make(chan struct{}), started: make(chan string, 10)}
+	wp := NewWorkerPool(synth, 1, 10)
+
+	wp.Submit("One two. Three four. Five six. Seven eight.", tts.VoiceAlloy)
+	done := make(chan struct{})
+	go func() {
+		wp.processJob(<-wp.jobs)
+		close(done)
+	}()
+
+	// maxParallelChunks requests start before any finishes
+	for i := 0; i < maxParallelChunks; i++ {
+		select {
+		case <-synth.started:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected %d chunks in flight, got %d", maxParallelChunks, i)
+		}
+	}
+	close(synth.block)
+	<-done
+
+	got := wp.GetStatus().RecentJobs[0]
+	if got.Status != "completed" || len(got.Chunks) != 4 {
+		t.Errorf("expected 4 completed chunks, got %q with %+v", got.Status, got.Chunks)
+	}
+}
+
+func TestWorkerPool_ChunkFailureFailsJob(t *testing.T) {
+	synth := &chunkSynthesizer{limit: 21, fail: "Second sentence here."}
+	wp := NewWorkerPool(synth, 1, 10)
+	wp.SetRetryPolicy(tts.RetryPolicy{MaxAttempts: 1})
+
+	wp.Submit("First sentence here. Second sentence here. Third one.", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+
+	got := wp.GetStatus().RecentJobs[0]
+	if got.Status != "failed" {
+		t.Fatalf("expected job to fail, got %q", got.Status)
+	}
+	if !strings.HasPrefix(got.Error, "chunk 2/3: ") || got.ErrorKind != "invalid_input" {
+		t.Errorf("unexpected error %q (%s)", got.Error, got.ErrorKind)
+	}
+	if got.Chunks[0].Status != ChunkDone || got.Chunks[1].Status != ChunkFailed {
+		t.Errorf("unexpected chunk progress %+v", got.Chunks)
+	}
+}
+
+// streamingChunkSynthesizer also streams, recording the requests
+type streamingChunkSynthesizer struct {
+	chunkSynthesizer
+	streamed []tts.Request
+}
+
+func (s *streamingChunkSynthesizer) Stream(ctx context.Context, req tts.Request) (*tts.AudioStream, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.streamed = append(s.streamed, req)
+	return &tts.AudioStream{Body: io.NopCloser(strings.NewReader("pcm:" + req.Text)), Format: req.Format, SampleRate: 24000}, nil
+}
+
+// fakeMPV puts an mpv script on PATH that copies its stdin to the
+// returned file
+func fakeMPV(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake player is a shell script")
+	}
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip("cat not found")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "played")
+	script := "#!/bin/sh\n" + cat + " >> " + out + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "mpv"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	return out
+}
+
+func TestWorkerPool_StreamsFirstChunk(t *testing.T) {
+	played := fakeMPV(t)
+	synth := &streamingChunkSynthesizer{chunkSynthesizer: chunkSynthesizer{limit: 21}}
+	wp := NewWorkerPool(synth, 1, 10)
+
+	wp.Submit("First sentence here. Second sentence here. Third one.", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+
+	got := wp.GetStatus().RecentJobs[0]
+	if got.Status != "completed" {
+		t.Fatalf("expected job to complete, got %q: %s", got.Status, got.Error)
+	}
+	if len(synth.streamed) != 1 || synth.streamed[0].Format != tts.FormatPCM {
+		t.Fatalf("expected the first chunk to be streamed as PCM, got %+v", synth.streamed)
+	}
+	if len(synth.texts) != 2 {
+		t.Errorf("expected the other chunks to be synthesized, got %v", synth.texts)
+	}
+	data, _ := os.ReadFile(played)
+	if string(data) != "pcm:First sentence here." {
+		t.Errorf("expected the stream to be piped to the player, got %q", data)
+	}
+}
+
+func TestWorkerPool_StreamingDisabled(t *testing.T) {
+	fakeMPV(t)
+	synth := &streamingChunkSynthesizer{chunkSynthesizer: chunkSynthesizer{limit: 100}}
+	wp := NewWorkerPool(synth, 1, 10)
+	wp.SetStreaming(false)
+
+	wp.Submit("Short.", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+
+	if len(synth.streamed) != 0 || len(synth.texts) != 1 {
+		t.Errorf("expected a plain synthesis, got %d streams and %d calls", len(synth.streamed), len(synth.texts))
+	}
+}
+
+func TestWorkerPool_FirstSentenceFastPath(t *testing.T) {
+	synth := &chunkSynthesizer{limit: 4096, delay: 5 * time.Millisecond}
+	wp := NewWorkerPool(synth, 1, 10)
+	wp.SetStreaming(false)
+
+	wp.Submit("Done. The build passed and every test ran green.", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+
+	status := wp.GetStatus()
+	got := status.RecentJobs[0]
+	if got.Status != "completed" || len(got.Chunks) != 2 {
+		t.Fatalf("expected 2 completed chunks, got %q with %+v", got.Status, got.Chunks)
+	}
+	if !containsText(synth.texts, "Done.") || !containsText(synth.texts, "The build passed and every test ran green.") {
+		t.Errorf("expected the first sentence on its own, got %q", synth.texts)
+	}
+	if got.TimeToFirstAudioMS < 5 {
+		t.Errorf("expected time to first audio to be recorded, got %dms", got.TimeToFirstAudioMS)
+	}
+	if status.AvgTimeToFirstAudioMS != got.TimeToFirstAudioMS {
+		t.Errorf("expected the average over one job to be %d, got %d", got.TimeToFirstAudioMS, status.AvgTimeToFirstAudioMS)
+	}
+
+	wp.SetFirstSentence(false)
+	wp.Submit("Done. The build passed and every test ran green.", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+	if got := wp.GetStatus().RecentJobs[1]; got.Chunks != nil {
+		t.Errorf("expected the text in one request without the fast path, got %+v", got.Chunks)
+	}
+}
+
+func TestWorkerPool_LanguageSegments(t *testing.T) {
+	synth := &chunkSynthesizer{limit: 4096}
+	french := &chunkSynthesizer{limit: 4096}
+	wp := NewWorkerPool(synth, 1, 10)
+	wp.SetStreaming(false)
+
+	wp.SubmitJob(tts.Request{Text: "The build is green.\nMerci pour la relecture.", Voice: tts.VoiceAlloy}, JobOptions{
+		Segments: []JobSegment{
+			NewJobSegment("", "The build is green.", tts.VoiceAlloy, nil),
+			NewJobSegment("fr", "Merci pour la relecture.", tts.VoiceNova, french),
+		},
+	})
+	wp.processJob(<-wp.jobs)
+
+	got := wp.GetStatus().RecentJobs[0]
+	if got.Status != "completed" || len(got.Chunks) != 2 {
+		t.Fatalf("expected 2 completed chunks, got %q with %+v", got.Status, got.Chunks)
+	}
+	if got.Chunks[0].Language != "" || got.Chunks[1].Language != "fr" {
+		t.Errorf("expected the chunk languages to be recorded, got %+v", got.Chunks)
+	}
+	if len(got.Segments) != 2 || got.Segments[1].Provider != "chunky" || got.Segments[1].Chars != 24 {
+		t.Errorf("unexpected segments %+v", got.Segments)
+	}
+	if len(synth.texts) != 1 || synth.texts[0] != "The build is green." || synth.voices[0] != tts.VoiceAlloy {
+		t.Errorf("expected the English text with alloy, got %q %q", synth.texts, synth.voices)
+	}
+	if len(french.texts) != 1 || french.texts[0] != "Merci pour la relecture." || french.voices[0] != tts.VoiceNova {
+		t.Errorf("expected the French text with nova on its own provider, got %q %q", french.texts, french.voices)
+	}
+}
+
+// selfPlayingSynthesizer speaks its chunks itself, recording whether two
+// calls, or a call and other playback, ever overlap
+type selfPlayingSynthesizer struct {
+	chunkSynthesizer
+	active  atomic.Int32
+	overlap atomic.Bool
+}
+
+func (s *selfPlayingSynthesizer) PlaysAudio() bool { return true }
+
+func (s *selfPlayingSynthesizer) speak() {
+	if s.active.Add(1) > 1 {
+		s.overlap.Store(true)
+	}
+	time.Sleep(5 * time.Millisecond)
+	s.active.Add(-1)
+}
+
+func (s *selfPlayingSynthesizer) Synthesize(ctx context.Context, req tts.Request) (*tts.Audio, error) {
+	s.speak()
+	return s.chunkSynthesizer.Synthesize(ctx, req)
+}
+
+func TestWorkerPool_SelfPlayingEngineSpeaksInTurn(t *testing.T) {
+	synth := &selfPlayingSynthesizer{chunkSynthesizer: chunkSynthesizer{limit: 21}}
+	wp := NewWorkerPool(synth, 1, 10)
+
+	// Other audio plays meanwhile, through the same player
+	stop := make(chan struct{})
+	var other sync.WaitGroup
+	other.Add(1)
+	go func() {
+		defer other.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = wp.audioPlayer.PlayWith(func() error {
+					synth.speak()
+					return nil
+				})
+			}
+		}
+	}()
+
+	wp.Submit("First sentence here. Second sentence here. Third one.", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+	close(stop)
+	other.Wait()
+
+	got := wp.GetStatus().RecentJobs[0]
+	if got.Status != "completed" || len(got.Chunks) != 3 {
+		t.Fatalf("expected 3 completed chunks, got %q with %+v", got.Status, got.Chunks)
+	}
+	want := []string{"First sentence here.", "Second sentence here.", "Third one."}
+	if strings.Join(synth.texts, "|") != strings.Join(want, "|") {
+		t.Errorf("expected the chunks spoken in order, got %q", synth.texts)
+	}
+	if synth.overlap.Load() {
+		t.Error("expected calls never to overlap each other or other playback")
+	}
+
+	// The first sentence is not split off for an engine that speaks itself
+	synth.texts = nil
+	wp.Submit("Done. Build passed.", tts.VoiceAlloy)
+	wp.processJob(<-wp.jobs)
+	if len(synth.texts) != 1 {
+		t.Errorf("expected one call without the first sentence fast path, got %q", synth.texts)
+	}
+}
+
+func containsText(texts []string, want string) bool {
+	for _, text := range texts {
+		if text == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,268 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func init() {
+	Register("azure", newAzureProvider)
+}
+
+// AzureConfig holds the settings of the Azure Speech provider
+type AzureConfig struct {
+	Region       string  `json:"region"`        // Speech resource region, e.g. westeurope
+	Key          string  `json:"key"`           // subscription key (default: from KeyEnv)
+	KeyEnv       string  `json:"key_env"`       // variable holding the key (default: AZURE_SPEECH_KEY)
+	Endpoint     string  `json:"endpoint"`      // override https://<region>.tts.speech.microsoft.com
+	Voice        string  `json:"voice"`         // default voice (default: en-US-JennyNeural)
+	Language     string  `json:"language"`      // xml:lang (default: derived from the voice)
+	Style        string  `json:"style"`         // mstts:express-as style, e.g. cheerful
+	StyleDegree  float64 `json:"style_degree"`  // style intensity, 0.01-2
+	Role         string  `json:"role"`          // role-play, e.g. OlderAdultFemale
+	Rate         string  `json:"rate"`          // prosody rate, e.g. +10% or slow
+	Pitch        string  `json:"pitch"`         // prosody pitch, e.g. -5% or high
+	OutputFormat string  `json:"output_format"` // X-Microsoft-OutputFormat value
+}
+
+// Azure handles Azure Cognitive Services Speech requests
+type Azure struct {
+	name       string
+	cfg        AzureConfig
+	httpClient *http.Client
+
+	voices voiceCache[Voice]
+}
+
+// NewAzure creates an Azure Speech client
+func NewAzure(cfg AzureConfig) (*Azure, error) {
+	if cfg.KeyEnv == "" {
+		cfg.KeyEnv = "AZURE_SPEECH_KEY"
+	}
+	if cfg.Key == "" {
+		cfg.Key = os.Getenv(cfg.KeyEnv)
+	}
+	if cfg.Key == "" {
+		return nil, fmt.Errorf("azure: key is required (set key in the config or %s)", cfg.KeyEnv)
+	}
+	if cfg.Endpoint == "" {
+		if cfg.Region == "" {
+			return nil, fmt.Errorf("azure: region is required")
+		}
+		cfg.Endpoint = fmt.Sprintf("https://%s.tts.speech.microsoft.com", cfg.Region)
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.Voice == "" {
+		cfg.Voice = "en-US-JennyNeural"
+	}
+	if cfg.StyleDegree != 0 && (cfg.StyleDegree < 0.01 || cfg.StyleDegree > 2) {
+		return nil, fmt.Errorf("azure: style_degree must be between 0.01 and 2, got %g", cfg.StyleDegree)
+	}
+	if cfg.OutputFormat == "" {
+		cfg.OutputFormat = "audio-24khz-48kbitrate-mono-mp3"
+	}
+	if !strings.HasSuffix(cfg.OutputFormat, "-mp3") {
+		return nil, fmt.Errorf("azure: output_format must be an MP3 format, got %q", cfg.OutputFormat)
+	}
+
+	return &Azure{
+		name: "azure",
+		cfg:  cfg,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+// newAzureProvider is the registry factory for the "azure" provider type
+func newAzureProvider(pc config.ProviderConfig) (Synthesizer, error) {
+	var cfg AzureConfig
+	if err := pc.Decode(&cfg); err != nil {
+		return nil, err
+	}
+	a, err := NewAzure(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if pc.Name != "" {
+		a.name = pc.Name
+	}
+	return a, nil
+}
+
+// Name returns the provider name
+func (a *Azure) Name() string {
+	return a.name
+}
+
+// MaxTextLength keeps requests well under Azure's ten minutes of audio
+// per request
+func (a *Azure) MaxTextLength() int {
+	return 5000
+}
+
+// Voices returns the region's voice short names, default voice first.
+// It returns nil if the voice list cannot be fetched.
+func (a *Azure) Voices() []Voice {
+	return a.voices.get(a.fetchVoices)
+}
+
+// fetchVoices requests the region's voice list
+func (a *Azure) fetchVoices(ctx context.Context) ([]Voice, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", a.cfg.Endpoint+"/cognitiveservices/voices/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Ocp-Apim-Subscription-Key", a.cfg.Key)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("azure: voice list returned status %d", resp.StatusCode)
+	}
+
+	var list []struct {
+		ShortName string `json:"ShortName"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("azure: failed to decode voices: %w", err)
+	}
+
+	voices := []Voice{Voice(a.cfg.Voice)}
+	for _, v := range list {
+		if v.ShortName != a.cfg.Voice {
+			voices = append(voices, Voice(v.ShortName))
+		}
+	}
+	return voices, nil
+}
+
+// voiceLanguage derives the locale from a voice name like en-US-JennyNeural
+func voiceLanguage(voice string) string {
+	parts := strings.SplitN(voice, "-", 3)
+	if len(parts) < 3 {
+		return "en-US"
+	}
+	return parts[0] + "-" + parts[1]
+}
+
+// Azure voices speak from half to twice their normal rate
+const (
+	azureMinSpeed = 0.5
+	azureMaxSpeed = 2
+)
+
+// buildSSML wraps text in <speak>, <voice>, optional mstts:express-as and
+// optional <prosody> elements. A speed other than 0 or 1 replaces the
+// configured rate.
+func (a *Azure) buildSSML(text, voice string, speed float64) string {
+	lang := a.cfg.Language
+	if lang == "" {
+		lang = voiceLanguage(voice)
+	}
+
+	var escaped bytes.Buffer
+	_ = xml.EscapeText(&escaped, []byte(text))
+	body := escaped.String()
+
+	rate := a.cfg.Rate
+	if speed > 0 && speed != 1 {
+		rate = fmt.Sprintf("%+.0f%%", (max(azureMinSpeed, min(azureMaxSpeed, speed))-1)*100)
+	}
+	if rate != "" || a.cfg.Pitch != "" {
+		attrs := ""
+		if rate != "" {
+			attrs += fmt.Sprintf(` rate="%s"`, xmlAttr(rate))
+		}
+		if a.cfg.Pitch != "" {
+			attrs += fmt.Sprintf(` pitch="%s"`, xmlAttr(a.cfg.Pitch))
+		}
+		body = "<prosody" + attrs + ">" + body + "</prosody>"
+	}
+
+	if a.cfg.Style != "" || a.cfg.Role != "" {
+		attrs := ""
+		if a.cfg.Style != "" {
+			attrs += fmt.Sprintf(` style="%s"`, xmlAttr(a.cfg.Style))
+		}
+		if a.cfg.StyleDegree != 0 {
+			attrs += fmt.Sprintf(` styledegree="%g"`, a.cfg.StyleDegree)
+		}
+		if a.cfg.Role != "" {
+			attrs += fmt.Sprintf(` role="%s"`, xmlAttr(a.cfg.Role))
+		}
+		body = "<mstts:express-as" + attrs + ">" + body + "</mstts:express-as>"
+	}
+
+	return fmt.Sprintf(`<speak version="1.0" xmlns="http://www.w3.org/2001/10/synthesis" `+
+		`xmlns:mstts="https://www.w3.org/2001/mstts" xml:lang="%s"><voice name="%s">%s</voice></speak>`,
+		xmlAttr(lang), xmlAttr(voice), body)
+}
+
+// xmlAttr escapes a value for use inside a double-quoted XML attribute
+func xmlAttr(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// Synthesize converts text to speech and returns MP3 audio data.
+// Text that is already SSML is sent unchanged and sets its own rate.
+func (a *Azure) Synthesize(ctx context.Context, r Request) (*Audio, error) {
+	voice := string(r.Voice)
+	if voice == "" || containsVoice(OpenAIVoices(), r.Voice) {
+		voice = a.cfg.Voice
+	}
+
+	ssml := r.Text
+	switch {
+	case !IsSSML(ssml):
+		ssml = a.buildSSML(r.Text, voice, r.Speed)
+	case r.Speed > 0 && r.Speed != 1:
+		return nil, fmt.Errorf("azure: speed cannot be applied to SSML, use <prosody rate> instead: %w", ErrInvalidInput)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.cfg.Endpoint+"/cognitiveservices/v1", strings.NewReader(ssml))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Ocp-Apim-Subscription-Key", a.cfg.Key)
+	req.Header.Set("Content-Type", "application/ssml+xml")
+	req.Header.Set("X-Microsoft-OutputFormat", a.cfg.OutputFormat)
+	req.Header.Set("User-Agent", "claude-code-tts")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+		return nil, newAPIError(resp, "Azure Speech API error (status %d): %s", resp.StatusCode, msg)
+	}
+
+	audioData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return &Audio{Data: audioData, Format: FormatMP3}, nil
+}
@@ -0,0 +1,192 @@
+package tts
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewAzure_Validation(t *testing.T) {
+	t.Setenv("AZURE_SPEECH_KEY", "")
+
+	if _, err := NewAzure(AzureConfig{Region: "westeurope"}); err == nil {
+		t.Error("expected error without a key")
+	}
+	if _, err := NewAzure(AzureConfig{Key: "k"}); err == nil {
+		t.Error("expected error without a region")
+	}
+	if _, err := NewAzure(AzureConfig{Key: "k", Region: "westeurope", StyleDegree: 3}); err == nil {
+		t.Error("expected error for style_degree > 2")
+	}
+	if _, err := NewAzure(AzureConfig{Key: "k", Region: "westeurope", OutputFormat: "riff-24khz-16bit-mono-pcm"}); err == nil {
+		t.Error("expected error for non-MP3 output format")
+	}
+
+	t.Setenv("AZURE_SPEECH_KEY", "env-key")
+	a, err := NewAzure(AzureConfig{Region: "westeurope"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.cfg.Key != "env-key" || a.cfg.Endpoint != "https://westeurope.tts.speech.microsoft.com" {
+		t.Errorf("unexpected config: %+v", a.cfg)
+	}
+}
+
+func TestVoiceLanguage(t *testing.T) {
+	tests := map[string]string{
+		"en-US-JennyNeural":          "en-US",
+		"fr-FR-DeniseNeural":         "fr-FR",
+		"zh-CN-shaanxi-XiaoniNeural": "zh-CN",
+		"custom":                     "en-US",
+	}
+	for voice, want := range tests {
+		if got := voiceLanguage(voice); got != want {
+			t.Errorf("voiceLanguage(%q) = %q, want %q", voice, got, want)
+		}
+	}
+}
+
+func TestAzure_BuildSSML(t *testing.T) {
+	a, err := NewAzure(AzureConfig{
+		Key:         "k",
+		Region:      "westeurope",
+		Style:       "cheerful",
+		StyleDegree: 1.5,
+		Role:        "YoungAdultFemale",
+		Rate:        "+10%",
+		Pitch:       "-5%",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ssml := a.buildSSML(`Tests passed & "deployed" <now>`, "fr-FR-DeniseNeural", 0)
+
+	// Must be well-formed XML
+	if err := xml.Unmarshal([]byte(ssml), new(struct{})); err != nil {
+		t.Fatalf("invalid SSML: %v\n%s", err, ssml)
+	}
+	for _, want := range []string{
+		`xml:lang="fr-FR"`,
+		`<voice name="fr-FR-DeniseNeural">`,
+		`<mstts:express-as style="cheerful" styledegree="1.5" role="YoungAdultFemale">`,
+		`<prosody rate="+10%" pitch="-5%">`,
+		`Tests passed &amp; &#34;deployed&#34; &lt;now&gt;`,
+	} {
+		if !strings.Contains(ssml, want) {
+			t.Errorf("expected SSML to contain %q, got:\n%s", want, ssml)
+		}
+	}
+}
+
+func TestAzure_BuildSSML_Plain(t *testing.T) {
+	a, _ := NewAzure(AzureConfig{Key: "k", Region: "eastus"})
+
+	ssml := a.buildSSML("Hello", "en-US-JennyNeural", 0)
+	if strings.Contains(ssml, "express-as") || strings.Contains(ssml, "prosody") {
+		t.Errorf("expected no style or prosody elements, got %s", ssml)
+	}
+}
+
+func TestAzure_BuildSSML_Speed(t *testing.T) {
+	a, _ := NewAzure(AzureConfig{Key: "k", Region: "eastus", Rate: "slow"})
+
+	if ssml := a.buildSSML("Hello", "en-US-JennyNeural", 1.5); !strings.Contains(ssml, `<prosody rate="+50%">`) {
+		t.Errorf("expected the speed to replace the configured rate, got %s", ssml)
+	}
+	if ssml := a.buildSSML("Hello", "en-US-JennyNeural", 0.25); !strings.Contains(ssml, `<prosody rate="-50%">`) {
+		t.Errorf("expected the speed to be limited to half the rate, got %s", ssml)
+	}
+	if ssml := a.buildSSML("Hello", "en-US-JennyNeural", 1); !strings.Contains(ssml, `<prosody rate="slow">`) {
+		t.Errorf("expected the configured rate at normal speed, got %s", ssml)
+	}
+	_, err := a.Synthesize(context.Background(), Request{Text: "<speak>Hi</speak>", Speed: 2})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected a speed with SSML to be rejected, got %v", err)
+	}
+}
+
+func TestAzure_Synthesize(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		voice Voice
+		want  string
+	}{
+		{"builds ssml", "Hello", "en-GB-SoniaNeural", `<voice name="en-GB-SoniaNeural">Hello</voice>`},
+		{"openai voice falls back", "Hello", VoiceAlloy, `<voice name="en-US-JennyNeural">`},
+		{"ssml passthrough", `<speak version="1.0">Raw</speak>`, "", `<speak version="1.0">Raw</speak>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/cognitiveservices/v1" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if r.Header.Get("Ocp-Apim-Subscription-Key") != "test-key" {
+					t.Error("expected subscription key header")
+				}
+				if r.Header.Get("Content-Type") != "application/ssml+xml" {
+					t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+				}
+				if !strings.HasSuffix(r.Header.Get("X-Microsoft-OutputFormat"), "-mp3") {
+					t.Errorf("expected MP3 output format, got %q", r.Header.Get("X-Microsoft-OutputFormat"))
+				}
+				body, _ := io.ReadAll(r.Body)
+				if !strings.Contains(string(body), tt.want) {
+					t.Errorf("expected body to contain %q, got %s", tt.want, body)
+				}
+				_, _ = w.Write([]byte("azure-audio"))
+			}))
+			defer server.Close()
+
+			a, err := NewAzure(AzureConfig{Key: "test-key", Endpoint: server.URL})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			audio, err := a.Synthesize(context.Background(), Request{Text: tt.text, Voice: tt.voice})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(audio.Data) != "azure-audio" || audio.Format != FormatMP3 {
+				t.Errorf("unexpected audio %s %q", audio.Format, audio.Data)
+			}
+		})
+	}
+}
+
+func TestAzure_SynthesizeError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	a, _ := NewAzure(AzureConfig{Key: "bad", Endpoint: server.URL})
+	_, err := a.Synthesize(context.Background(), Request{Text: "Hello"})
+	if err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Errorf("expected 401 error, got: %v", err)
+	}
+}
+
+func TestAzure_Voices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cognitiveservices/voices/list" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[{"ShortName": "en-GB-SoniaNeural"}, {"ShortName": "en-US-JennyNeural"}]`))
+	}))
+	defer server.Close()
+
+	a, _ := NewAzure(AzureConfig{Key: "k", Endpoint: server.URL})
+	voices := a.Voices()
+	if len(voices) != 2 || voices[0] != "en-US-JennyNeural" {
+		t.Errorf("expected default voice first, got %v", voices)
+	}
+}
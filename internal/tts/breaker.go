@@ -0,0 +1,140 @@
+package tts
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// Circuit breaker defaults
+const (
+	DefaultFailureThreshold = 3
+	DefaultCooldown         = 30 * time.Second
+)
+
+// Breaker states
+const (
+	BreakerClosed   = "closed"    // healthy, requests flow
+	BreakerOpen     = "open"      // tripped, requests are skipped
+	BreakerHalfOpen = "half-open" // cooldown over, one trial request allowed
+)
+
+// ErrCircuitOpen is returned for a provider whose breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Breaker stops calling a provider after repeated failures. After the
+// cooldown it lets a single trial request through: success closes the
+// breaker again, failure re-opens it.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	state     string
+	failures  int
+	openedAt  time.Time
+	lastError string
+	trial     bool // a half-open trial request is in flight
+}
+
+// NewBreaker creates a closed breaker from the config, filling in defaults
+func NewBreaker(cfg config.BreakerConfig) *Breaker {
+	b := &Breaker{
+		threshold: DefaultFailureThreshold,
+		cooldown:  DefaultCooldown,
+		now:       time.Now,
+		state:     BreakerClosed,
+	}
+	if cfg.FailureThreshold > 0 {
+		b.threshold = cfg.FailureThreshold
+	}
+	if cfg.CooldownMS > 0 {
+		b.cooldown = time.Duration(cfg.CooldownMS) * time.Millisecond
+	}
+	return b
+}
+
+// Allow reports whether a request may be sent now
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		b.trial = true
+		return true
+	case BreakerHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	}
+	return true
+}
+
+// Success records a successful request and closes the breaker
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = BreakerClosed
+	b.failures = 0
+	b.lastError = ""
+	b.trial = false
+}
+
+// Release gives back a half-open trial whose request was cancelled
+// without an outcome
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// Failure records a failed request, tripping the breaker at the threshold
+// or immediately when a half-open trial fails
+func (b *Breaker) Failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.lastError = err.Error()
+	b.trial = false
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// ProviderHealth is a snapshot of one provider's breaker
+type ProviderHealth struct {
+	Name      string     `json:"name"`
+	State     string     `json:"state"`
+	Failures  int        `json:"consecutive_failures"`
+	LastError string     `json:"last_error,omitempty"`
+	RetryAt   *time.Time `json:"retry_at,omitempty"`
+}
+
+// health returns the breaker state for the named provider
+func (b *Breaker) health(name string) ProviderHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	h := ProviderHealth{
+		Name:      name,
+		State:     b.state,
+		Failures:  b.failures,
+		LastError: b.lastError,
+	}
+	if b.state == BreakerOpen {
+		retryAt := b.openedAt.Add(b.cooldown)
+		h.RetryAt = &retryAt
+	}
+	return h
+}
@@ -0,0 +1,105 @@
+package tts
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// testBreaker returns a breaker with a controllable clock
+func testBreaker(threshold int, cooldown time.Duration) (*Breaker, *time.Time) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewBreaker(config.BreakerConfig{FailureThreshold: threshold, CooldownMS: int(cooldown / time.Millisecond)})
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestNewBreaker_Defaults(t *testing.T) {
+	b := NewBreaker(config.BreakerConfig{})
+	if b.threshold != DefaultFailureThreshold || b.cooldown != DefaultCooldown {
+		t.Errorf("expected defaults, got threshold=%d cooldown=%v", b.threshold, b.cooldown)
+	}
+	if h := b.health("openai"); h.State != BreakerClosed {
+		t.Errorf("expected closed, got %s", h.State)
+	}
+}
+
+func TestBreaker_TripsAtThreshold(t *testing.T) {
+	b, _ := testBreaker(3, time.Minute)
+	boom := errors.New("boom")
+
+	b.Failure(boom)
+	b.Failure(boom)
+	if !b.Allow() {
+		t.Fatal("expected breaker to stay closed below the threshold")
+	}
+	b.Failure(boom)
+	if b.Allow() {
+		t.Fatal("expected breaker to trip at the threshold")
+	}
+
+	h := b.health("openai")
+	if h.State != BreakerOpen || h.Failures != 3 || h.LastError != "boom" || h.RetryAt == nil {
+		t.Errorf("unexpected health %+v", h)
+	}
+}
+
+func TestBreaker_SuccessResets(t *testing.T) {
+	b, _ := testBreaker(2, time.Minute)
+
+	b.Failure(errors.New("boom"))
+	b.Success()
+	b.Failure(errors.New("boom"))
+	if !b.Allow() {
+		t.Error("expected success to reset the failure count")
+	}
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	b, now := testBreaker(1, time.Minute)
+
+	b.Failure(errors.New("down"))
+	if b.Allow() {
+		t.Fatal("expected open breaker to refuse requests")
+	}
+
+	*now = now.Add(time.Minute)
+	if !b.Allow() {
+		t.Fatal("expected a trial request after the cooldown")
+	}
+	if b.Allow() {
+		t.Fatal("expected only one trial request while half-open")
+	}
+	if h := b.health("openai"); h.State != BreakerHalfOpen {
+		t.Errorf("expected half-open, got %s", h.State)
+	}
+
+	// A failed trial re-opens immediately
+	b.Failure(errors.New("still down"))
+	if b.Allow() {
+		t.Fatal("expected failed trial to re-open the breaker")
+	}
+
+	*now = now.Add(time.Minute)
+	if !b.Allow() {
+		t.Fatal("expected another trial after the cooldown")
+	}
+	b.Success()
+	if h := b.health("openai"); h.State != BreakerClosed || h.Failures != 0 || h.LastError != "" {
+		t.Errorf("expected closed and reset after a successful trial, got %+v", h)
+	}
+}
+
+func TestBreaker_Release(t *testing.T) {
+	b, now := testBreaker(1, time.Minute)
+
+	b.Failure(errors.New("down"))
+	*now = now.Add(time.Minute)
+	b.Allow()
+	b.Release()
+	if !b.Allow() {
+		t.Error("expected a released trial to be available again")
+	}
+}
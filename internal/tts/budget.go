@@ -0,0 +1,460 @@
+package tts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// defaultCosts is the list price in USD per million characters of the
+// hosted providers, keyed by model or provider type. Providers that are
+// not listed, like the local engines, are free.
+var defaultCosts = map[string]float64{
+	// OpenAI
+	"tts-1":           15,
+	"tts-1-hd":        30,
+	"gpt-4o-mini-tts": 12,
+	// ElevenLabs, at the pay-as-you-go overage rate
+	"eleven_multilingual_v2": 300,
+	"eleven_v3":              300,
+	"eleven_turbo_v2_5":      150,
+	"eleven_flash_v2_5":      150,
+	// Amazon Polly engines
+	"standard":   4,
+	"neural":     16,
+	"generative": 30,
+	// Azure and Google neural voices
+	"azure":  16,
+	"google": 16,
+}
+
+// defaultBudgetWarnPercent is the share of a cap that triggers a warning
+const defaultBudgetWarnPercent = 80
+
+// CostTable prices synthesis in USD per million characters
+type CostTable struct {
+	overrides map[string]float64
+	cfg       *config.Config // resolves provider names to types
+}
+
+// NewCostTable returns the built-in prices with the ones configured in
+// cfg's budget applied
+func NewCostTable(cfg *config.Config) CostTable {
+	return CostTable{overrides: cfg.Budget.Costs, cfg: cfg}
+}
+
+// PerMillion returns the price per million characters of the provider
+// with the given name and model. A configured price for the name comes
+// first, so a self-hosted server can be marked free; then the model and
+// the provider's type are looked up, in configured prices before
+// built-in ones, so a renamed instance is priced like its type.
+func (t CostTable) PerMillion(name, model string) float64 {
+	if cost, ok := t.overrides[name]; ok {
+		return cost
+	}
+	typ := t.cfg.Lookup(name).Type
+	for _, table := range []map[string]float64{t.overrides, defaultCosts} {
+		if cost, ok := table[model]; ok && model != "" {
+			return cost
+		}
+		if cost, ok := table[typ]; ok {
+			return cost
+		}
+	}
+	return 0
+}
+
+// Estimate returns the estimated cost in USD of sending chars characters
+// to s
+func (t CostTable) Estimate(s Synthesizer, chars int) float64 {
+	return t.PerMillion(s.Name(), providerModel(s)) * float64(chars) / 1e6
+}
+
+// BudgetUsage is what was spent in the current day and month
+type BudgetUsage struct {
+	Day        string  `json:"day"`
+	DayChars   int     `json:"day_chars"`
+	DayUSD     float64 `json:"day_usd"`
+	Month      string  `json:"month"`
+	MonthChars int     `json:"month_chars"`
+	MonthUSD   float64 `json:"month_usd"`
+}
+
+// BudgetStatus reports the spending against the configured caps
+type BudgetStatus struct {
+	BudgetUsage
+	DailyChars   int     `json:"daily_chars_limit,omitempty"`
+	MonthlyChars int     `json:"monthly_chars_limit,omitempty"`
+	DailyUSD     float64 `json:"daily_usd_limit,omitempty"`
+	MonthlyUSD   float64 `json:"monthly_usd_limit,omitempty"`
+	Warning      string  `json:"warning,omitempty"`
+}
+
+// Budget enforces daily and monthly caps on the characters sent to paid
+// providers and their estimated cost. Usage is kept in a JSON file so the
+// caps hold across restarts and are shared with speak-text.
+type Budget struct {
+	cfg   config.BudgetConfig
+	path  string
+	costs CostTable
+	now   func() time.Time
+
+	mu     sync.Mutex
+	onWarn func(string)
+}
+
+// DefaultBudgetPath returns tts-budget.json next to the config file
+func DefaultBudgetPath() string {
+	return filepath.Join(filepath.Dir(config.Path()), "tts-budget.json")
+}
+
+// NewBudget creates the budget described by cfg. It returns nil if no cap
+// is configured.
+func NewBudget(c *config.Config) *Budget {
+	cfg := c.Budget
+	if cfg.DailyChars <= 0 && cfg.MonthlyChars <= 0 && cfg.DailyUSD <= 0 && cfg.MonthlyUSD <= 0 {
+		return nil
+	}
+	if cfg.WarnPercent <= 0 || cfg.WarnPercent > 100 {
+		cfg.WarnPercent = defaultBudgetWarnPercent
+	}
+	path := DefaultBudgetPath()
+	if cfg.Path != "" {
+		path = config.ExpandPath(cfg.Path)
+	}
+	return &Budget{cfg: cfg, path: path, costs: NewCostTable(c), now: time.Now}
+}
+
+// OnWarn sets the function told when spending crosses the warning
+// threshold of a cap
+func (b *Budget) OnWarn(f func(string)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onWarn = f
+}
+
+// Cost returns the estimated cost in USD of sending chars characters to s
+func (b *Budget) Cost(s Synthesizer, chars int) float64 {
+	return b.costs.Estimate(s, chars)
+}
+
+// Allow returns an error wrapping ErrBudgetExceeded if spending chars
+// characters at a cost of usd would go over a cap. Free requests are
+// always allowed. Nothing is recorded; use Reserve to spend.
+func (b *Budget) Allow(chars int, usd float64) error {
+	if usd <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	u, err := b.load()
+	if err != nil {
+		return err
+	}
+	return b.exceeded(u, chars, usd)
+}
+
+// Spend is text of Chars characters to be spoken by Synth
+type Spend struct {
+	Synth Synthesizer
+	Chars int
+}
+
+// Check returns an error if the budget cannot cover all of spends
+// together. A chain is priced at its cheapest provider, since it falls
+// back to it once the others are refused.
+func (b *Budget) Check(spends ...Spend) error {
+	var chars int
+	var usd float64
+	for _, sp := range spends {
+		providers := []Synthesizer{sp.Synth}
+		if chain, ok := sp.Synth.(*Chain); ok {
+			providers = providers[:0]
+			for _, l := range chain.links {
+				providers = append(providers, l.synth)
+			}
+		}
+		cost := b.Cost(providers[0], sp.Chars)
+		for _, p := range providers[1:] {
+			cost = min(cost, b.Cost(p, sp.Chars))
+		}
+		if cost > 0 {
+			chars += sp.Chars
+			usd += cost
+		}
+	}
+	return b.Allow(chars, usd)
+}
+
+// Reserve records chars characters spent at a cost of usd, or returns an
+// error wrapping ErrBudgetExceeded if that would go over a cap. Checking
+// and recording is one step, so concurrent requests, in this process or
+// another, cannot overshoot a cap together. Free requests are always
+// allowed.
+func (b *Budget) Reserve(chars int, usd float64) error {
+	if usd <= 0 {
+		return nil
+	}
+	return b.update(func(u *BudgetUsage) error {
+		if err := b.exceeded(*u, chars, usd); err != nil {
+			return err
+		}
+		u.add(chars, usd)
+		return nil
+	})
+}
+
+// Refund gives back a reservation whose request failed. Failing to record
+// it calls the OnWarn function.
+func (b *Budget) Refund(chars int, usd float64) {
+	if usd <= 0 {
+		return
+	}
+	if err := b.update(func(u *BudgetUsage) error {
+		u.add(-chars, -usd)
+		return nil
+	}); err != nil {
+		b.warn(err.Error())
+	}
+}
+
+// update applies f to the usage file while holding its lock, and saves
+// the result unless f fails. Crossing a cap's warning threshold calls the
+// OnWarn function.
+func (b *Budget) update(f func(*BudgetUsage) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	unlock, err := lockFile(b.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	u, err := b.load()
+	if err != nil {
+		return err
+	}
+	before := b.caps(u)
+	if err := f(&u); err != nil {
+		return err
+	}
+	if err := b.save(u); err != nil {
+		return err
+	}
+	share := float64(b.cfg.WarnPercent) / 100
+	for i, c := range b.caps(u) {
+		if c.limit > 0 && before[i].used/c.limit < share && c.used/c.limit >= share {
+			b.warn(b.warning(u))
+			break
+		}
+	}
+	return nil
+}
+
+// exceeded returns an error wrapping ErrBudgetExceeded if adding chars
+// characters at a cost of usd to u would go over a cap
+func (b *Budget) exceeded(u BudgetUsage, chars int, usd float64) error {
+	for _, c := range b.caps(u) {
+		if c.limit > 0 && c.used+c.value(chars, usd) > c.limit {
+			return fmt.Errorf("%w: %s limit of %s reached (%s used; resets %s)",
+				ErrBudgetExceeded, c.period, c.format(c.limit), c.format(c.used), c.resets)
+		}
+	}
+	return nil
+}
+
+// add adds chars characters at a cost of usd to the day and month, which
+// never go below zero
+func (u *BudgetUsage) add(chars int, usd float64) {
+	u.DayChars = max(u.DayChars+chars, 0)
+	u.DayUSD = max(u.DayUSD+usd, 0)
+	u.MonthChars = max(u.MonthChars+chars, 0)
+	u.MonthUSD = max(u.MonthUSD+usd, 0)
+}
+
+// warn passes msg to the OnWarn function, if set
+func (b *Budget) warn(msg string) {
+	if b.onWarn != nil {
+		b.onWarn(msg)
+	}
+}
+
+// Status returns the current spending and caps
+func (b *Budget) Status() (BudgetStatus, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	u, err := b.load()
+	if err != nil {
+		return BudgetStatus{}, err
+	}
+	return BudgetStatus{
+		BudgetUsage:  u,
+		DailyChars:   b.cfg.DailyChars,
+		MonthlyChars: b.cfg.MonthlyChars,
+		DailyUSD:     b.cfg.DailyUSD,
+		MonthlyUSD:   b.cfg.MonthlyUSD,
+		Warning:      b.warning(u),
+	}, nil
+}
+
+// Warning describes the cap closest to being reached, or returns "" if
+// all are below the warning threshold
+func (b *Budget) Warning() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	u, err := b.load()
+	if err != nil {
+		return ""
+	}
+	return b.warning(u)
+}
+
+// budgetCap is one configured cap and its usage
+type budgetCap struct {
+	period string // e.g. "daily character"
+	resets string
+	used   float64
+	limit  float64
+	usd    bool
+}
+
+// value returns what a request adds to the cap
+func (c budgetCap) value(chars int, usd float64) float64 {
+	if c.usd {
+		return usd
+	}
+	return float64(chars)
+}
+
+// format renders an amount in the cap's unit
+func (c budgetCap) format(v float64) string {
+	if c.usd {
+		return fmt.Sprintf("$%.2f", v)
+	}
+	return fmt.Sprintf("%.0f characters", v)
+}
+
+// caps returns the configured caps with their usage in u
+func (b *Budget) caps(u BudgetUsage) []budgetCap {
+	return []budgetCap{
+		{period: "daily character", resets: "at midnight", used: float64(u.DayChars), limit: float64(b.cfg.DailyChars)},
+		{period: "daily cost", resets: "at midnight", used: u.DayUSD, limit: b.cfg.DailyUSD, usd: true},
+		{period: "monthly character", resets: "next month", used: float64(u.MonthChars), limit: float64(b.cfg.MonthlyChars)},
+		{period: "monthly cost", resets: "next month", used: u.MonthUSD, limit: b.cfg.MonthlyUSD, usd: true},
+	}
+}
+
+// warning describes the most used cap at or above the warning threshold
+func (b *Budget) warning(u BudgetUsage) string {
+	var worst *budgetCap
+	share := float64(b.cfg.WarnPercent) / 100
+	for _, c := range b.caps(u) {
+		if c.limit > 0 && c.used/c.limit >= share {
+			c := c
+			worst = &c
+			share = c.used / c.limit
+		}
+	}
+	if worst == nil {
+		return ""
+	}
+	return fmt.Sprintf("%.0f%% of the %s budget used (%s of %s)",
+		share*100, worst.period, worst.format(worst.used), worst.format(worst.limit))
+}
+
+// load reads the usage file, starting a new day or month when the
+// recorded one has passed
+func (b *Budget) load() (BudgetUsage, error) {
+	var u BudgetUsage
+	data, err := os.ReadFile(b.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return u, fmt.Errorf("failed to read budget: %w", err)
+	default:
+		if err := json.Unmarshal(data, &u); err != nil {
+			return u, fmt.Errorf("failed to parse budget %s: %w", b.path, err)
+		}
+	}
+
+	now := b.now()
+	if day := now.Format(time.DateOnly); u.Day != day {
+		u.Day, u.DayChars, u.DayUSD = day, 0, 0
+	}
+	if month := now.Format("2006-01"); u.Month != month {
+		u.Month, u.MonthChars, u.MonthUSD = month, 0, 0
+	}
+	return u, nil
+}
+
+// Budget file lock timing: a lock older than budgetLockStale was left by a
+// process that died and is broken; after budgetLockTimeout, give up
+const (
+	budgetLockStale   = 2 * time.Second
+	budgetLockTimeout = 5 * time.Second
+)
+
+// lockFile takes the lock file at path, shared by every process that
+// uses it, waiting while another holds it. It returns the function that
+// releases it.
+func lockFile(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to lock budget: %w", err)
+	}
+	deadline := time.Now().Add(budgetLockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock budget: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > budgetLockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock budget: %s is held by another process", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// save writes the usage file atomically
+func (b *Budget) save(u BudgetUsage) error {
+	data, err := json.MarshalIndent(u, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return fmt.Errorf("failed to save budget: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(b.path), ".tts-budget-*")
+	if err != nil {
+		return fmt.Errorf("failed to save budget: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save budget: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save budget: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), b.path); err != nil {
+		return fmt.Errorf("failed to save budget: %w", err)
+	}
+	return nil
+}
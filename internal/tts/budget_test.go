@@ -0,0 +1,243 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// testBudget returns a budget in a temp dir with a settable clock
+func testBudget(t *testing.T, cfg config.BudgetConfig) (*Budget, *time.Time) {
+	t.Helper()
+	cfg.Path = filepath.Join(t.TempDir(), "tts-budget.json")
+	b := NewBudget(&config.Config{Budget: cfg})
+	if b == nil {
+		t.Fatal("expected a budget")
+	}
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.Local)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestNewBudget_NoCaps(t *testing.T) {
+	if b := NewBudget(&config.Config{Budget: config.BudgetConfig{Costs: map[string]float64{"tts-1": 20}}}); b != nil {
+		t.Error("expected no budget without caps")
+	}
+}
+
+func TestCostTable(t *testing.T) {
+	var cfg config.Config
+	if err := json.Unmarshal([]byte(`{"providers": {"work-azure": {"type": "azure"}, "kokoro": {"type": "openai"}}}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Budget.Costs = map[string]float64{"kokoro": 0, "tts-1-hd": 40}
+	costs := NewCostTable(&cfg)
+
+	tests := []struct {
+		name, model string
+		want        float64
+	}{
+		{"openai", "tts-1", 15},
+		{"openai", "tts-1-hd", 40},
+		{"kokoro", "tts-1", 0},
+		{"azure", "", 16},
+		{"work-azure", "", 16},
+		{"espeak", "", 0},
+	}
+	for _, tt := range tests {
+		if got := costs.PerMillion(tt.name, tt.model); got != tt.want {
+			t.Errorf("PerMillion(%q, %q) = %v, want %v", tt.name, tt.model, got, tt.want)
+		}
+	}
+
+	if usd := costs.Estimate(NewClient(), 100000); usd != 1.5 {
+		t.Errorf("expected $1.50 for 100000 characters of tts-1, got %v", usd)
+	}
+}
+
+func TestBudget_RefusesAtCap(t *testing.T) {
+	b, _ := testBudget(t, config.BudgetConfig{DailyChars: 1000})
+
+	if err := b.Reserve(900, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Allow(100, 0.01); err != nil {
+		t.Fatalf("expected the last 100 characters to be allowed, got %v", err)
+	}
+	err := b.Allow(101, 0.01)
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected ErrBudgetExceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "daily character limit of 1000 characters reached") {
+		t.Errorf("unexpected message %q", err)
+	}
+	if err := b.Allow(5000, 0); err != nil {
+		t.Errorf("expected free providers to be allowed, got %v", err)
+	}
+}
+
+func TestBudget_CostCap(t *testing.T) {
+	b, _ := testBudget(t, config.BudgetConfig{MonthlyUSD: 1})
+
+	if err := b.Reserve(50000, 0.75); err != nil {
+		t.Fatal(err)
+	}
+	err := b.Allow(20000, 0.30)
+	if !errors.Is(err, ErrBudgetExceeded) || !strings.Contains(err.Error(), "monthly cost limit of $1.00") {
+		t.Errorf("expected the monthly cost cap, got %v", err)
+	}
+}
+
+func TestBudget_Persists(t *testing.T) {
+	b, now := testBudget(t, config.BudgetConfig{DailyChars: 1000, MonthlyChars: 5000})
+	if err := b.Reserve(600, 0.01); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewBudget(&config.Config{Budget: b.cfg})
+	reloaded.now = b.now
+	status, err := reloaded.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if status.DayChars != 600 || status.MonthChars != 600 || status.DailyChars != 1000 {
+		t.Errorf("unexpected status after reload %+v", status)
+	}
+
+	// The next day starts a new daily count but keeps the month
+	*now = now.Add(24 * time.Hour)
+	status, _ = reloaded.Status()
+	if status.DayChars != 0 || status.MonthChars != 600 {
+		t.Errorf("expected a new day, got %+v", status)
+	}
+
+	*now = now.AddDate(0, 1, 0)
+	status, _ = reloaded.Status()
+	if status.MonthChars != 0 {
+		t.Errorf("expected a new month, got %+v", status)
+	}
+}
+
+func TestBudget_WarnsOnceAtThreshold(t *testing.T) {
+	b, _ := testBudget(t, config.BudgetConfig{DailyChars: 1000, WarnPercent: 50})
+	var warnings []string
+	b.OnWarn(func(msg string) { warnings = append(warnings, msg) })
+
+	if err := b.Reserve(400, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 0 || b.Warning() != "" {
+		t.Fatalf("expected no warning below the threshold, got %q", warnings)
+	}
+	if err := b.Reserve(200, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Reserve(100, 0.01); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "60% of the daily character budget") {
+		t.Errorf("expected one warning, got %q", warnings)
+	}
+	if w := b.Warning(); !strings.Contains(w, "70%") {
+		t.Errorf("expected the current warning, got %q", w)
+	}
+}
+
+func TestBudget_CheckChain(t *testing.T) {
+	b, _ := testBudget(t, config.BudgetConfig{DailyChars: 100})
+
+	paid := NewClient()
+	if err := b.Check(Spend{paid, 500}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected the paid provider to be refused, got %v", err)
+	}
+	chain := NewChain(config.BreakerConfig{}, paid, &stubSynthesizer{name: "espeak"})
+	if err := b.Check(Spend{chain, 500}); err != nil {
+		t.Errorf("expected the free fallback to pass, got %v", err)
+	}
+}
+
+func TestBudget_CheckSpends(t *testing.T) {
+	b, _ := testBudget(t, config.BudgetConfig{DailyChars: 100})
+
+	paid, free := NewClient(), &stubSynthesizer{name: "espeak"}
+	if err := b.Check(Spend{paid, 60}, Spend{free, 500}); err != nil {
+		t.Errorf("expected text for the free provider not to count, got %v", err)
+	}
+	if err := b.Check(Spend{paid, 60}, Spend{paid, 60}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected the parts to add up, got %v", err)
+	}
+}
+
+func TestBudget_ReserveIsAtomic(t *testing.T) {
+	b, _ := testBudget(t, config.BudgetConfig{DailyChars: 1000})
+	// A second process sharing the file
+	other := NewBudget(&config.Config{Budget: b.cfg})
+	other.now = b.now
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	granted := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(b *Budget) {
+			defer wg.Done()
+			if b.Reserve(100, 0.01) == nil {
+				mu.Lock()
+				granted++
+				mu.Unlock()
+			}
+		}([]*Budget{b, other}[i%2])
+	}
+	wg.Wait()
+
+	status, _ := b.Status()
+	if granted != 10 || status.DayChars != 1000 {
+		t.Errorf("expected exactly 10 reservations up to the cap, got %d for %d characters", granted, status.DayChars)
+	}
+
+	b.Refund(100, 0.01)
+	if err := other.Reserve(100, 0.01); err != nil {
+		t.Errorf("expected a refund to free its characters, got %v", err)
+	}
+}
+
+func TestWithLimits_Budget(t *testing.T) {
+	b, _ := testBudget(t, config.BudgetConfig{DailyChars: 10, Costs: map[string]float64{"paid": 100}})
+	primary := &scriptedSynthesizer{name: "paid"}
+	fallback := &scriptedSynthesizer{name: "espeak"}
+	s, err := WithLimits(NewChain(config.BreakerConfig{}, primary, fallback), config.Default(), b)
+	if err != nil {
+		t.Fatalf("WithLimits: %v", err)
+	}
+
+	if _, err := s.Synthesize(context.Background(), Request{Text: "0123456789"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	audio, err := s.Synthesize(context.Background(), Request{Text: "more text"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if audio.Provider != "espeak" || len(primary.calls) != 1 {
+		t.Errorf("expected the fallback once the budget ran out, got %q after %d primary calls", audio.Provider, len(primary.calls))
+	}
+	if h := s.(HealthReporter).Health()[0]; h.Failures != 0 {
+		t.Errorf("expected the budget not to count as a provider failure, got %+v", h)
+	}
+
+	// A paid request that fails gives its characters back
+	b.Refund(10, 0.001)
+	primary.err = &APIError{StatusCode: 503, Kind: ErrServerError}
+	if _, err := s.Synthesize(context.Background(), Request{Text: "0123456789"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status, _ := b.Status(); status.DayChars != 0 || len(primary.calls) != 2 {
+		t.Errorf("expected the failed paid request to be refunded, got %d characters used", status.DayChars)
+	}
+}
@@ -0,0 +1,347 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// Cache defaults
+const (
+	DefaultCacheMaxBytes = 100 << 20 // 100 MiB
+	DefaultCacheTTL      = 30 * 24 * time.Hour
+)
+
+// Cache is a content-addressed store of synthesized audio on disk.
+// Entries are named <key>.<created>.<format>; the file's modification
+// time is its last use, so eviction is least recently used first.
+// Several processes (the server and speak-text) can share a directory.
+type Cache struct {
+	dir      string
+	maxBytes int64
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex // serializes eviction within this process
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// CacheStats reports the cache counters and size
+type CacheStats struct {
+	Dir      string `json:"dir"`
+	Hits     int64  `json:"hits"`
+	Misses   int64  `json:"misses"`
+	Entries  int    `json:"entries"`
+	Bytes    int64  `json:"bytes"`
+	MaxBytes int64  `json:"max_bytes"`
+}
+
+// DefaultCacheDir returns the audio cache under the user cache directory
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "claude-code-tts", "audio")
+}
+
+// NewCache opens the cache described by cfg. It returns nil, nil when
+// caching is disabled.
+func NewCache(cfg config.CacheConfig) (*Cache, error) {
+	if cfg.Disabled {
+		return nil, nil
+	}
+	c := &Cache{
+		dir:      config.ExpandPath(cfg.Dir),
+		maxBytes: DefaultCacheMaxBytes,
+		ttl:      DefaultCacheTTL,
+		now:      time.Now,
+	}
+	if c.dir == "" {
+		c.dir = DefaultCacheDir()
+	}
+	if cfg.MaxMB > 0 {
+		c.maxBytes = int64(cfg.MaxMB) << 20
+	}
+	if cfg.TTLHours > 0 {
+		c.ttl = time.Duration(cfg.TTLHours) * time.Hour
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	return c, nil
+}
+
+// normalizeText collapses whitespace so trivially different strings share
+// an entry
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// cacheKey hashes everything that changes the audio for a provider,
+// including its settings in the config file
+func cacheKey(provider, model string, settings json.RawMessage, req Request) string {
+	data, _ := json.Marshal(struct {
+		Provider     string          `json:"p"`
+		Model        string          `json:"m"`
+		Settings     json.RawMessage `json:"c"`
+		Voice        Voice           `json:"v"`
+		Speed        float64         `json:"s"`
+		Format       Format          `json:"f"`
+		Instructions string          `json:"i"`
+		Text         string          `json:"t"`
+	}{provider, model, settings, req.Voice, req.Speed, req.Format, req.Instructions, normalizeText(req.Text)})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// entryPath returns the file holding key, if any, and when it was created
+func (c *Cache) entryPath(key string) (string, time.Time, bool) {
+	matches, _ := filepath.Glob(filepath.Join(c.dir, key+".*"))
+	for _, path := range matches {
+		if created, _, ok := parseEntryName(filepath.Base(path)); ok {
+			return path, created, true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// parseEntryName splits <key>.<created>.<format>
+func parseEntryName(name string) (time.Time, Format, bool) {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 {
+		return time.Time{}, "", false
+	}
+	unix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return time.Unix(unix, 0), Format(parts[2]), true
+}
+
+// Get returns the cached audio for key. Expired entries are removed.
+func (c *Cache) Get(key string) (*Audio, bool) {
+	path, created, ok := c.entryPath(key)
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	now := c.now()
+	if now.Sub(created) > c.ttl {
+		os.Remove(path)
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+	_, format, _ := parseEntryName(filepath.Base(path))
+	// Mark as recently used
+	_ = os.Chtimes(path, now, now)
+	c.hits.Add(1)
+	return &Audio{Data: data, Format: format}, true
+}
+
+// Put stores audio under key and evicts old entries if the cache is over
+// its size limit
+func (c *Cache) Put(key string, audio *Audio) error {
+	if audio == nil || len(audio.Data) == 0 || audio.Format == FormatNone {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if _, err := tmp.Write(audio.Data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	tmp.Close()
+
+	name := fmt.Sprintf("%s.%d.%s", key, c.now().Unix(), audio.Format)
+	// Rename is atomic, so a concurrent reader never sees a partial file
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, name)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	c.evict()
+	return nil
+}
+
+// cacheEntry is a file found while scanning the cache
+type cacheEntry struct {
+	path    string
+	size    int64
+	used    time.Time
+	created time.Time
+}
+
+// scan lists the cache entries
+func (c *Cache) scan() []cacheEntry {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil
+	}
+	entries := make([]cacheEntry, 0, len(files))
+	for _, f := range files {
+		created, _, ok := parseEntryName(f.Name())
+		if !ok || f.IsDir() {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, cacheEntry{
+			path:    filepath.Join(c.dir, f.Name()),
+			size:    info.Size(),
+			used:    info.ModTime(),
+			created: created,
+		})
+	}
+	return entries
+}
+
+// evict removes expired entries, then the least recently used ones until
+// the cache fits in maxBytes
+func (c *Cache) evict() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	var total int64
+	live := make([]cacheEntry, 0)
+	for _, e := range c.scan() {
+		if now.Sub(e.created) > c.ttl {
+			os.Remove(e.path)
+			continue
+		}
+		total += e.size
+		live = append(live, e)
+	}
+
+	sort.Slice(live, func(i, j int) bool { return live[i].used.Before(live[j].used) })
+	for _, e := range live {
+		if total <= c.maxBytes {
+			break
+		}
+		if os.Remove(e.path) == nil {
+			total -= e.size
+		}
+	}
+}
+
+// Stats returns the hit/miss counters of this process and the cache size
+func (c *Cache) Stats() CacheStats {
+	stats := CacheStats{
+		Dir:      c.dir,
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+		MaxBytes: c.maxBytes,
+	}
+	for _, e := range c.scan() {
+		stats.Entries++
+		stats.Bytes += e.size
+	}
+	return stats
+}
+
+// cachedSynthesizer answers repeated requests from a Cache
+type cachedSynthesizer struct {
+	wrapper
+	cache    *Cache
+	settings json.RawMessage // the provider's settings in cfg
+}
+
+// WithCache puts cache in front of s. The providers of a Chain are wrapped
+// one by one, so fallback audio is cached under the fallback's name; each
+// provider's settings in cfg are part of its keys, so changing them does
+// not replay old audio. A nil cache returns s unchanged.
+func WithCache(s Synthesizer, cfg *config.Config, cache *Cache) Synthesizer {
+	if cache == nil {
+		return s
+	}
+	return wrapProviders(s, func(p Synthesizer) Synthesizer {
+		settings, _ := json.Marshal(cfg.Lookup(p.Name()))
+		return &cachedSynthesizer{wrapper: wrapper{p}, cache: cache, settings: settings}
+	})
+}
+
+// Synthesize returns cached audio when available, otherwise synthesizes
+// and stores the result. Cache write failures are ignored.
+func (cs *cachedSynthesizer) Synthesize(ctx context.Context, req Request) (*Audio, error) {
+	key := cacheKey(cs.synth.Name(), providerModel(cs.synth), cs.settings, req)
+	if audio, ok := cs.cache.Get(key); ok {
+		return audio, nil
+	}
+
+	audio, err := cs.synth.Synthesize(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	_ = cs.cache.Put(key, audio)
+	return audio, nil
+}
+
+// Stream returns cached audio as a stream when available. Otherwise the
+// provider's stream is recorded as it is read, and cached once it has
+// been read to the end.
+func (cs *cachedSynthesizer) Stream(ctx context.Context, req Request) (*AudioStream, error) {
+	key := cacheKey(cs.synth.Name(), providerModel(cs.synth), cs.settings, req)
+	if audio, ok := cs.cache.Get(key); ok {
+		return bufferedStream(audio), nil
+	}
+
+	stream, err := Stream(ctx, cs.synth, req)
+	if err != nil {
+		return nil, err
+	}
+	format, sampleRate := stream.Format, stream.SampleRate
+	stream.Body = &recordingBody{ReadCloser: stream.Body, done: func(data []byte) {
+		audio := &Audio{Data: data, Format: format}
+		if format == FormatPCM {
+			// Raw samples cannot be played from a file; store them as WAV
+			audio = &Audio{Data: pcmToWAV(data, sampleRate, 1, 16), Format: FormatWAV}
+		}
+		_ = cs.cache.Put(key, audio)
+	}}
+	return stream, nil
+}
+
+// recordingBody keeps a copy of everything read and hands it to done once
+// the body has been read to the end
+type recordingBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	done func([]byte)
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF && b.done != nil {
+		b.done(b.buf.Bytes())
+		b.done = nil
+	}
+	return n, err
+}
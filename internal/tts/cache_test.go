@@ -0,0 +1,241 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// testCache returns a cache in a temp dir with a controllable clock
+func testCache(t *testing.T, cfg config.CacheConfig) (*Cache, *time.Time) {
+	t.Helper()
+	cfg.Dir = t.TempDir()
+	c, err := NewCache(cfg)
+	if err != nil {
+		t.Fatalf("NewCache: %v", err)
+	}
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestNewCache_Disabled(t *testing.T) {
+	c, err := NewCache(config.CacheConfig{Disabled: true})
+	if err != nil || c != nil {
+		t.Fatalf("expected no cache, got %v, %v", c, err)
+	}
+
+	s := &scriptedSynthesizer{name: "openai"}
+	if WithCache(s, &config.Config{}, nil) != Synthesizer(s) {
+		t.Error("expected a nil cache to leave the synthesizer unchanged")
+	}
+}
+
+func TestCache_HitAndMiss(t *testing.T) {
+	c, _ := testCache(t, config.CacheConfig{})
+	s := &scriptedSynthesizer{name: "openai", voices: OpenAIVoices()}
+	cached := WithCache(s, &config.Config{}, c)
+
+	req := Request{Text: "Build  completed\n", Voice: VoiceNova, Speed: 1.5}
+	for i := 0; i < 2; i++ {
+		audio, err := cached.Synthesize(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(audio.Data) != "openai" || audio.Format != FormatMP3 {
+			t.Errorf("unexpected audio %q (%s)", audio.Data, audio.Format)
+		}
+	}
+	// Whitespace differences share an entry
+	if _, err := cached.Synthesize(context.Background(), Request{Text: "Build completed", Voice: VoiceNova, Speed: 1.5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(s.calls) != 1 {
+		t.Errorf("expected 1 provider call, got %d", len(s.calls))
+	}
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 || stats.Bytes != int64(len("openai")) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	base := Request{Text: "hello", Voice: VoiceNova}
+	key := cacheKey("openai", ModelTTS1, nil, base)
+
+	variants := map[string]string{
+		"provider":     cacheKey("espeak", ModelTTS1, nil, base),
+		"model":        cacheKey("openai", ModelTTS1HD, nil, base),
+		"voice":        cacheKey("openai", ModelTTS1, nil, Request{Text: "hello", Voice: VoiceOnyx}),
+		"speed":        cacheKey("openai", ModelTTS1, nil, Request{Text: "hello", Voice: VoiceNova, Speed: 2}),
+		"format":       cacheKey("openai", ModelTTS1, nil, Request{Text: "hello", Voice: VoiceNova, Format: FormatOpus}),
+		"instructions": cacheKey("openai", ModelTTS1, nil, Request{Text: "hello", Voice: VoiceNova, Instructions: "calm"}),
+		"text":         cacheKey("openai", ModelTTS1, nil, Request{Text: "Hello", Voice: VoiceNova}),
+	}
+	for field, k := range variants {
+		if k == key {
+			t.Errorf("expected %s to change the key", field)
+		}
+	}
+	if cacheKey("openai", ModelTTS1, json.RawMessage(`{"type":"openai","instructions":"slow"}`), base) == key {
+		t.Error("expected the provider settings to change the key")
+	}
+	if cacheKey("openai", ModelTTS1, nil, Request{Text: " hello ", Voice: VoiceNova}) != key {
+		t.Error("expected surrounding whitespace to be ignored")
+	}
+}
+
+func TestWithCache_ProviderSettings(t *testing.T) {
+	c, _ := testCache(t, config.CacheConfig{})
+	s := &scriptedSynthesizer{name: "espeak", voices: []Voice{"en-us"}}
+	load := func(settings string) *config.Config {
+		var cfg config.Config
+		if err := json.Unmarshal([]byte(`{"providers": {"espeak": `+settings+`}}`), &cfg); err != nil {
+			t.Fatal(err)
+		}
+		return &cfg
+	}
+
+	req := Request{Text: "hello", Voice: "en-us"}
+	for _, settings := range []string{`{"pitch": 50}`, `{"pitch": 50}`, `{"pitch": 20}`} {
+		if _, err := WithCache(s, load(settings), c).Synthesize(context.Background(), req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(s.calls) != 2 {
+		t.Errorf("expected changed settings to miss the cache, got %d provider calls", len(s.calls))
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	c, now := testCache(t, config.CacheConfig{TTLHours: 1})
+	if err := c.Put("k", &Audio{Data: []byte("audio"), Format: FormatWAV}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, ok := c.Get("k"); !ok {
+		t.Fatal("expected a hit before the TTL")
+	}
+
+	*now = now.Add(2 * time.Hour)
+	if _, ok := c.Get("k"); ok {
+		t.Fatal("expected expired entry to miss")
+	}
+	if c.Stats().Entries != 0 {
+		t.Error("expected expired entry to be removed")
+	}
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c, _ := testCache(t, config.CacheConfig{})
+	c.maxBytes = 10
+
+	old := time.Now().Add(-time.Hour)
+	for _, key := range []string{"a", "b"} {
+		if err := c.Put(key, &Audio{Data: []byte("1234"), Format: FormatMP3}); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		path, _, _ := c.entryPath(key)
+		os.Chtimes(path, old, old)
+		old = old.Add(time.Minute)
+	}
+	// Using "a" makes "b" the least recently used
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected a hit for a")
+	}
+
+	if err := c.Put("c", &Audio{Data: []byte("1234"), Format: FormatMP3}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, _, ok := c.entryPath("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, _, ok := c.entryPath(key); !ok {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+}
+
+func TestCache_SkipsPlayedAudio(t *testing.T) {
+	c, _ := testCache(t, config.CacheConfig{})
+	if err := c.Put("k", &Audio{Format: FormatNone}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	files, _ := os.ReadDir(c.dir)
+	if len(files) != 0 {
+		t.Errorf("expected nothing stored, got %d files", len(files))
+	}
+}
+
+func TestWithCache_Chain(t *testing.T) {
+	c, _ := testCache(t, config.CacheConfig{})
+	primary := &scriptedSynthesizer{name: "openai", voices: OpenAIVoices(), err: &APIError{StatusCode: 503, Kind: ErrServerError}}
+	fallback := &scriptedSynthesizer{name: "espeak", voices: []Voice{"en-us"}}
+	chain := NewChain(config.BreakerConfig{FailureThreshold: 10}, primary, fallback)
+
+	cached := WithCache(chain, &config.Config{}, c)
+	if cached != Synthesizer(chain) {
+		t.Fatal("expected the chain to be kept so its health is still reported")
+	}
+
+	req := Request{Text: "hi", Voice: VoiceNova}
+	if _, err := cached.Synthesize(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Once the primary recovers it is used again, not the cached fallback audio
+	primary.err = nil
+	audio, err := cached.Synthesize(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(audio.Data) != "openai" || audio.Provider != "openai" {
+		t.Errorf("expected fresh audio from openai, got %q from %q", audio.Data, audio.Provider)
+	}
+	if chain.Name() != "openai -> espeak" {
+		t.Errorf("unexpected chain name %q", chain.Name())
+	}
+}
+
+func TestCache_Stream(t *testing.T) {
+	c, _ := testCache(t, config.CacheConfig{})
+	s := &streamingSynthesizer{scriptedSynthesizer{name: "openai"}}
+	cached := WithCache(s, &config.Config{}, c)
+	req := Request{Text: "Streamed", Voice: VoiceNova, Format: FormatPCM}
+
+	// A stream closed early is not cached
+	stream, err := cached.(Streamer).Stream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stream.Body.Close()
+	if c.Stats().Entries != 0 {
+		t.Fatal("expected a partial stream not to be cached")
+	}
+
+	// A stream read to the end is cached, as WAV since raw PCM has no header
+	stream, _ = cached.(Streamer).Stream(context.Background(), req)
+	if data, _ := io.ReadAll(stream.Body); string(data) != "openai" {
+		t.Fatalf("unexpected stream data %q", data)
+	}
+	stream.Body.Close()
+
+	stream, err = cached.(Streamer).Stream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := io.ReadAll(stream.Body)
+	if stream.Format != FormatWAV || string(data[:4]) != "RIFF" || !strings.HasSuffix(string(data), "openai") {
+		t.Errorf("expected cached WAV audio, got %s", stream.Format)
+	}
+	if len(s.calls) != 2 {
+		t.Errorf("expected 2 provider calls, got %d", len(s.calls))
+	}
+}
@@ -0,0 +1,233 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// HealthReporter is implemented by synthesizers that track the health of
+// the providers behind them
+type HealthReporter interface {
+	Health() []ProviderHealth
+}
+
+// chainLink is one provider of a Chain with its breaker
+type chainLink struct {
+	synth   Synthesizer
+	breaker *Breaker
+}
+
+// Chain tries providers in order until one succeeds. Each provider has a
+// circuit breaker, so one that keeps failing is skipped until its cooldown
+// has passed.
+type Chain struct {
+	links []chainLink
+}
+
+// NewChain creates a failover chain over the given providers
+func NewChain(cfg config.BreakerConfig, synths ...Synthesizer) *Chain {
+	c := &Chain{}
+	for _, s := range synths {
+		c.links = append(c.links, chainLink{synth: s, breaker: NewBreaker(cfg)})
+	}
+	return c
+}
+
+// newChainFromConfig builds the active provider followed by its fallbacks,
+// skipping duplicates
+func newChainFromConfig(cfg *config.Config) (*Chain, error) {
+	seen := make(map[string]bool)
+	var synths []Synthesizer
+	for _, name := range append([]string{cfg.Provider}, cfg.Fallbacks...) {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		s, err := NewProvider(cfg, name)
+		if err != nil {
+			return nil, fmt.Errorf("provider %q: %w", name, err)
+		}
+		synths = append(synths, s)
+	}
+	return NewChain(cfg.Breaker, synths...), nil
+}
+
+// Link returns the provider named name as a chain of its own that shares
+// its breaker, or nil if the chain has no such provider
+func (c *Chain) Link(name string) *Chain {
+	for _, l := range c.links {
+		if l.synth.Name() == name {
+			return &Chain{links: []chainLink{l}}
+		}
+	}
+	return nil
+}
+
+// Name returns the provider names in failover order
+func (c *Chain) Name() string {
+	names := make([]string, 0, len(c.links))
+	for _, l := range c.links {
+		names = append(names, l.synth.Name())
+	}
+	return strings.Join(names, " -> ")
+}
+
+// Voices returns the voices of every provider, the first provider's
+// default first. It returns nil (accept any voice) if the first provider
+// does not list its voices.
+func (c *Chain) Voices() []Voice {
+	if len(c.links) == 0 {
+		return nil
+	}
+	if vl, ok := c.links[0].synth.(VoiceLister); !ok || len(vl.Voices()) == 0 {
+		return nil
+	}
+
+	var voices []Voice
+	for _, l := range c.links {
+		vl, ok := l.synth.(VoiceLister)
+		if !ok {
+			continue
+		}
+		for _, v := range vl.Voices() {
+			if !containsVoice(voices, v) {
+				voices = append(voices, v)
+			}
+		}
+	}
+	return voices
+}
+
+// MaxTextLength returns the smallest limit of the providers, so any of
+// them can take a chunk
+func (c *Chain) MaxTextLength() int {
+	limit := 0
+	for _, l := range c.links {
+		if n := MaxTextLength(l.synth); n > 0 && (limit == 0 || n < limit) {
+			limit = n
+		}
+	}
+	return limit
+}
+
+// PlaysAudio reports whether any provider speaks itself, since any of
+// them may take a request
+func (c *Chain) PlaysAudio() bool {
+	for _, l := range c.links {
+		if PlaysAudio(l.synth) {
+			return true
+		}
+	}
+	return false
+}
+
+// Health returns the breaker state of each provider
+func (c *Chain) Health() []ProviderHealth {
+	health := make([]ProviderHealth, 0, len(c.links))
+	for _, l := range c.links {
+		health = append(health, l.breaker.health(l.synth.Name()))
+	}
+	return health
+}
+
+// Synthesize tries each provider whose breaker allows it. A voice the
+// provider does not offer is replaced by its default. Invalid input and
+// an exhausted budget do not count against a provider's health.
+func (c *Chain) Synthesize(ctx context.Context, req Request) (*Audio, error) {
+	var audio *Audio
+	name, err := c.try(ctx, req, func(s Synthesizer, r Request) (err error) {
+		audio, err = s.Synthesize(ctx, r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if audio.Provider == "" {
+		audio.Provider = name
+	}
+	return audio, nil
+}
+
+// Stream opens a stream from the first provider that answers, like
+// Synthesize. Providers that cannot stream return their complete audio.
+func (c *Chain) Stream(ctx context.Context, req Request) (*AudioStream, error) {
+	var stream *AudioStream
+	name, err := c.try(ctx, req, func(s Synthesizer, r Request) (err error) {
+		stream, err = Stream(ctx, s, r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if stream.Provider == "" {
+		stream.Provider = name
+	}
+	return stream, nil
+}
+
+// try calls each provider in turn until call succeeds, and returns the
+// name of the provider that did
+func (c *Chain) try(ctx context.Context, req Request, call func(Synthesizer, Request) error) (string, error) {
+	var failures []string
+	var lastErr error
+
+	for _, l := range c.links {
+		name := l.synth.Name()
+		if !l.breaker.Allow() {
+			failures = append(failures, name+": "+ErrCircuitOpen.Error())
+			continue
+		}
+
+		r := req
+		if !SupportsVoice(l.synth, r.Voice) {
+			r.Voice = DefaultVoice(l.synth)
+		}
+
+		err := call(l.synth, r)
+		if err == nil {
+			l.breaker.Success()
+			return name, nil
+		}
+		if ctx.Err() != nil {
+			l.breaker.Release()
+			return "", err
+		}
+
+		if errors.Is(err, ErrInvalidInput) || errors.Is(err, ErrBudgetExceeded) {
+			l.breaker.Release()
+		} else {
+			l.breaker.Failure(err)
+		}
+		failures = append(failures, name+": "+err.Error())
+		lastErr = err
+	}
+
+	if len(c.links) == 1 && lastErr != nil {
+		return "", lastErr
+	}
+	if lastErr == nil {
+		return "", &chainError{msg: "all providers unavailable", failures: failures, last: ErrCircuitOpen}
+	}
+	return "", &chainError{msg: "all providers failed", failures: failures, last: lastErr}
+}
+
+// chainError reports every provider's failure and unwraps to the last
+// provider error, so errors.Is sees its class
+type chainError struct {
+	msg      string
+	failures []string
+	last     error
+}
+
+func (e *chainError) Error() string {
+	return e.msg + ": " + strings.Join(e.failures, "; ")
+}
+
+func (e *chainError) Unwrap() error {
+	return e.last
+}
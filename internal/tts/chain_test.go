@@ -0,0 +1,229 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// scriptedSynthesizer fails with err (if set) and records the requests
+type scriptedSynthesizer struct {
+	name   string
+	voices []Voice
+	err    error
+	calls  []Request
+}
+
+func (s *scriptedSynthesizer) Name() string { return s.name }
+
+func (s *scriptedSynthesizer) Voices() []Voice { return s.voices }
+
+func (s *scriptedSynthesizer) Synthesize(ctx context.Context, req Request) (*Audio, error) {
+	s.calls = append(s.calls, req)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &Audio{Data: []byte(s.name), Format: FormatMP3}, nil
+}
+
+func TestChain_FailsOver(t *testing.T) {
+	primary := &scriptedSynthesizer{name: "openai", voices: OpenAIVoices(), err: &APIError{StatusCode: 503, Kind: ErrServerError, Message: "outage"}}
+	fallback := &scriptedSynthesizer{name: "espeak", voices: []Voice{"en-us", "fr"}}
+	chain := NewChain(config.BreakerConfig{}, primary, fallback)
+
+	audio, err := chain.Synthesize(context.Background(), Request{Text: "hi", Voice: VoiceNova})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(audio.Data) != "espeak" || audio.Provider != "espeak" {
+		t.Errorf("expected audio from espeak, got %q (provider %q)", audio.Data, audio.Provider)
+	}
+	// nova is not an espeak voice, so the fallback's default is used
+	if fallback.calls[0].Voice != "en-us" {
+		t.Errorf("expected fallback default voice, got %q", fallback.calls[0].Voice)
+	}
+}
+
+func TestChain_BreakerSkipsFailingProvider(t *testing.T) {
+	primary := &scriptedSynthesizer{name: "openai", err: &APIError{StatusCode: 500, Kind: ErrServerError, Message: "down"}}
+	fallback := &scriptedSynthesizer{name: "espeak"}
+	chain := NewChain(config.BreakerConfig{FailureThreshold: 2}, primary, fallback)
+
+	for i := 0; i < 4; i++ {
+		if _, err := chain.Synthesize(context.Background(), Request{Text: "hi"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(primary.calls) != 2 {
+		t.Errorf("expected the breaker to stop calls after 2 failures, got %d", len(primary.calls))
+	}
+
+	health := chain.Health()
+	if health[0].Name != "openai" || health[0].State != BreakerOpen {
+		t.Errorf("expected openai breaker open, got %+v", health[0])
+	}
+	if health[1].State != BreakerClosed {
+		t.Errorf("expected espeak breaker closed, got %+v", health[1])
+	}
+}
+
+func TestChain_AllFail(t *testing.T) {
+	primary := &scriptedSynthesizer{name: "openai", err: &APIError{StatusCode: 503, Kind: ErrServerError, Message: "outage"}}
+	fallback := &scriptedSynthesizer{name: "kokoro", err: &APIError{StatusCode: 401, Kind: ErrUnauthorized, Message: "bad key"}}
+	chain := NewChain(config.BreakerConfig{}, primary, fallback)
+
+	_, err := chain.Synthesize(context.Background(), Request{Text: "hi"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "openai: outage") || !strings.Contains(err.Error(), "kokoro: bad key") {
+		t.Errorf("expected every failure in the error, got %v", err)
+	}
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected the last provider's error class, got %v", err)
+	}
+}
+
+func TestChain_AllOpen(t *testing.T) {
+	primary := &scriptedSynthesizer{name: "openai", err: errors.New("down")}
+	chain := NewChain(config.BreakerConfig{FailureThreshold: 1}, primary, &scriptedSynthesizer{name: "espeak", err: errors.New("missing")})
+
+	_, _ = chain.Synthesize(context.Background(), Request{Text: "hi"})
+	_, err := chain.Synthesize(context.Background(), Request{Text: "hi"})
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestChain_Link(t *testing.T) {
+	fallback := &scriptedSynthesizer{name: "azure", err: errors.New("down")}
+	chain := NewChain(config.BreakerConfig{FailureThreshold: 1}, &scriptedSynthesizer{name: "openai"}, fallback)
+
+	link := chain.Link("azure")
+	if link == nil || link.Name() != "azure" || chain.Link("polly") != nil {
+		t.Fatalf("expected a link for azure only, got %v", link)
+	}
+	_, _ = link.Synthesize(context.Background(), Request{Text: "hi"})
+	if h := chain.Health()[1]; h.State != "open" {
+		t.Errorf("expected the link to share the chain's breaker, got %+v", h)
+	}
+	if _, err := link.Synthesize(context.Background(), Request{Text: "hi"}); !errors.Is(err, ErrCircuitOpen) || len(fallback.calls) != 1 {
+		t.Errorf("expected the tripped provider to be skipped, got %v after %d calls", err, len(fallback.calls))
+	}
+}
+
+func TestChain_InvalidInputKeepsBreakerClosed(t *testing.T) {
+	primary := &scriptedSynthesizer{name: "openai", err: &APIError{StatusCode: 400, Kind: ErrInvalidInput, Message: "too long"}}
+	chain := NewChain(config.BreakerConfig{FailureThreshold: 1}, primary, &scriptedSynthesizer{name: "espeak"})
+
+	_, _ = chain.Synthesize(context.Background(), Request{Text: "hi"})
+	if h := chain.Health()[0]; h.State != BreakerClosed {
+		t.Errorf("expected invalid input not to trip the breaker, got %+v", h)
+	}
+}
+
+func TestChain_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	primary := &scriptedSynthesizer{name: "openai", err: context.Canceled}
+	fallback := &scriptedSynthesizer{name: "espeak"}
+	chain := NewChain(config.BreakerConfig{FailureThreshold: 1}, primary, fallback)
+
+	if _, err := chain.Synthesize(ctx, Request{Text: "hi"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation, got %v", err)
+	}
+	if len(fallback.calls) != 0 {
+		t.Error("expected no failover after cancellation")
+	}
+	if h := chain.Health()[0]; h.State != BreakerClosed {
+		t.Errorf("expected cancellation not to trip the breaker, got %+v", h)
+	}
+}
+
+func TestChain_NameAndVoices(t *testing.T) {
+	chain := NewChain(config.BreakerConfig{},
+		&scriptedSynthesizer{name: "openai", voices: []Voice{VoiceAlloy, VoiceNova}},
+		&scriptedSynthesizer{name: "espeak", voices: []Voice{"en-us", VoiceNova}},
+	)
+
+	if chain.Name() != "openai -> espeak" {
+		t.Errorf("unexpected name %q", chain.Name())
+	}
+	voices := chain.Voices()
+	want := []Voice{VoiceAlloy, VoiceNova, "en-us"}
+	if len(voices) != len(want) {
+		t.Fatalf("expected %v, got %v", want, voices)
+	}
+	for i := range want {
+		if voices[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, voices)
+		}
+	}
+}
+
+func TestNew_WithFallbacks(t *testing.T) {
+	cfg := config.Default()
+	cfg.Provider = "stub"
+	cfg.Fallbacks = []string{"stub", "other"}
+	cfg.Providers["other"] = mustProviderConfig(t, `{"type": "stub"}`)
+
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	chain, ok := s.(*Chain)
+	if !ok {
+		t.Fatalf("expected a *Chain, got %T", s)
+	}
+	if chain.Name() != "stub -> other" {
+		t.Errorf("expected duplicates to be skipped, got %q", chain.Name())
+	}
+
+	cfg.Fallbacks = []string{"no-such-type"}
+	if _, err := New(cfg); err == nil {
+		t.Error("expected error for an unknown fallback")
+	}
+}
+
+// streamingSynthesizer streams its name, or fails with err
+type streamingSynthesizer struct {
+	scriptedSynthesizer
+}
+
+func (s *streamingSynthesizer) Stream(ctx context.Context, req Request) (*AudioStream, error) {
+	s.calls = append(s.calls, req)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &AudioStream{Body: io.NopCloser(strings.NewReader(s.name)), Format: FormatPCM, SampleRate: 24000}, nil
+}
+
+func TestChain_Stream(t *testing.T) {
+	primary := &streamingSynthesizer{scriptedSynthesizer{name: "openai", voices: OpenAIVoices(), err: &APIError{StatusCode: 503, Kind: ErrServerError}}}
+	fallback := &scriptedSynthesizer{name: "espeak", voices: []Voice{"en-us"}}
+	chain := NewChain(config.BreakerConfig{}, primary, fallback)
+
+	// A fallback that cannot stream returns its complete audio
+	stream, err := chain.Stream(context.Background(), Request{Text: "hi", Voice: VoiceNova})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := io.ReadAll(stream.Body)
+	if string(data) != "espeak" || stream.Provider != "espeak" || stream.Format != FormatMP3 {
+		t.Errorf("expected buffered audio from espeak, got %q from %q (%s)", data, stream.Provider, stream.Format)
+	}
+
+	primary.err = nil
+	stream, err = chain.Stream(context.Background(), Request{Text: "hi", Voice: VoiceNova})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stream.Provider != "openai" || stream.Format != FormatPCM {
+		t.Errorf("expected a PCM stream from openai, got %s from %q", stream.Format, stream.Provider)
+	}
+}
@@ -0,0 +1,173 @@
+package tts
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TextLimiter is implemented by synthesizers that accept a limited number
+// of characters per request
+type TextLimiter interface {
+	MaxTextLength() int
+}
+
+// MaxTextLength returns the most characters s accepts per request, or 0
+// if it has no limit
+func MaxTextLength(s Synthesizer) int {
+	if tl, ok := s.(TextLimiter); ok {
+		return tl.MaxTextLength()
+	}
+	return 0
+}
+
+// paragraphBreak matches a blank line between paragraphs
+var paragraphBreak = regexp.MustCompile(`\n\s*\n`)
+
+// splitters break text at progressively weaker boundaries
+var splitters = []func(string) []string{
+	splitParagraphs,
+	func(text string) []string { return splitAfter(text, isSentenceEnd) },
+	func(text string) []string { return splitAfter(text, isClauseEnd) },
+	strings.Fields,
+}
+
+// Chunk splits text into pieces of at most limit characters, breaking at
+// paragraph boundaries first, then sentences, then clauses, then words.
+// Neighbouring pieces are packed together while they fit. Text within the
+// limit, SSML, or a limit of 0 yields a single chunk.
+func Chunk(text string, limit int) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if limit <= 0 || IsSSML(text) || utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+	return chunkLevel(text, limit, 0)
+}
+
+// maxLeadSentence is the longest first sentence SplitFirstSentence
+// splits off; a longer one would not start playing much sooner
+const maxLeadSentence = 200
+
+// SplitFirstSentence splits the first sentence off the first chunk, so it
+// can be synthesized on its own and start playing while the rest is
+// synthesized. Chunks with a single sentence, a long first sentence or
+// SSML are returned unchanged.
+func SplitFirstSentence(chunks []string) []string {
+	if len(chunks) == 0 || IsSSML(chunks[0]) {
+		return chunks
+	}
+	sentences := splitAfter(chunks[0], isSentenceEnd)
+	if len(sentences) < 2 || utf8.RuneCountInString(sentences[0]) > maxLeadSentence || !strings.HasPrefix(chunks[0], sentences[0]) {
+		return chunks
+	}
+	rest := strings.TrimSpace(chunks[0][len(sentences[0]):])
+	return append([]string{sentences[0], rest}, chunks[1:]...)
+}
+
+// chunkLevel splits text with splitters[level], recursing into pieces
+// that are still too long
+func chunkLevel(text string, limit, level int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+	if level == len(splitters) {
+		return splitRunes(text, limit)
+	}
+
+	sep := " "
+	if level == 0 {
+		sep = "\n\n"
+	}
+
+	var chunks []string
+	current := ""
+	flush := func() {
+		if current != "" {
+			chunks = append(chunks, current)
+			current = ""
+		}
+	}
+	for _, piece := range splitters[level](text) {
+		n := utf8.RuneCountInString(piece)
+		switch {
+		case n > limit:
+			flush()
+			chunks = append(chunks, chunkLevel(piece, limit, level+1)...)
+		case current == "":
+			current = piece
+		case utf8.RuneCountInString(current)+len(sep)+n <= limit:
+			current += sep + piece
+		default:
+			flush()
+			current = piece
+		}
+	}
+	flush()
+	return chunks
+}
+
+// splitParagraphs splits text at blank lines
+func splitParagraphs(text string) []string {
+	var pieces []string
+	for _, p := range paragraphBreak.Split(text, -1) {
+		if p = strings.TrimSpace(p); p != "" {
+			pieces = append(pieces, p)
+		}
+	}
+	return pieces
+}
+
+// splitAfter splits text after each rune matching isEnd (and any closing
+// quotes or brackets) that is followed by whitespace
+func splitAfter(text string, isEnd func(rune) bool) []string {
+	var pieces []string
+	runes := []rune(text)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		if !isEnd(runes[i]) {
+			continue
+		}
+		end := i + 1
+		for end < len(runes) && strings.ContainsRune(`"')]»”’`, runes[end]) {
+			end++
+		}
+		if end < len(runes) && !unicode.IsSpace(runes[end]) {
+			continue
+		}
+		if p := strings.TrimSpace(string(runes[start:end])); p != "" {
+			pieces = append(pieces, p)
+		}
+		start = end
+		i = end - 1
+	}
+	if p := strings.TrimSpace(string(runes[start:])); p != "" {
+		pieces = append(pieces, p)
+	}
+	return pieces
+}
+
+// isSentenceEnd reports whether r ends a sentence
+func isSentenceEnd(r rune) bool {
+	return strings.ContainsRune(".!?…。！？", r)
+}
+
+// isClauseEnd reports whether r ends a clause
+func isClauseEnd(r rune) bool {
+	return strings.ContainsRune(",;:—–，；：", r)
+}
+
+// splitRunes cuts text into pieces of exactly limit characters, the last
+// piece possibly shorter; used for words longer than the limit
+func splitRunes(text string, limit int) []string {
+	var pieces []string
+	runes := []rune(text)
+	for len(runes) > limit {
+		pieces = append(pieces, string(runes[:limit]))
+		runes = runes[limit:]
+	}
+	return append(pieces, string(runes))
+}
@@ -0,0 +1,185 @@
+package tts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "within limit",
+			text:  "  Build completed.  ",
+			limit: 100,
+			want:  []string{"Build completed."},
+		},
+		{
+			name:  "no limit",
+			text:  "One. Two. Three.",
+			limit: 0,
+			want:  []string{"One. Two. Three."},
+		},
+		{
+			name:  "empty",
+			text:  " \n ",
+			limit: 10,
+			want:  nil,
+		},
+		{
+			name:  "paragraphs",
+			text:  "First paragraph.\n\nSecond paragraph.\n  \nThird.",
+			limit: 25,
+			want:  []string{"First paragraph.", "Second paragraph.\n\nThird."},
+		},
+		{
+			name:  "sentences packed",
+			text:  "One. Two! Three? Four.",
+			limit: 10,
+			want:  []string{"One. Two!", "Three?", "Four."},
+		},
+		{
+			name:  "closing quotes stay with the sentence",
+			text:  `He said "stop." Then left.`,
+			limit: 16,
+			want:  []string{`He said "stop."`, "Then left."},
+		},
+		{
+			name:  "decimals are not sentence ends",
+			text:  "Version 1.5 shipped. It works.",
+			limit: 22,
+			want:  []string{"Version 1.5 shipped.", "It works."},
+		},
+		{
+			name:  "clauses",
+			text:  "When the tests pass, the build is tagged; then it ships",
+			limit: 25,
+			want:  []string{"When the tests pass,", "the build is tagged;", "then it ships"},
+		},
+		{
+			name:  "words",
+			text:  "alpha beta gamma delta",
+			limit: 11,
+			want:  []string{"alpha beta", "gamma delta"},
+		},
+		{
+			name:  "long word",
+			text:  "abcdefghij",
+			limit: 4,
+			want:  []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:  "counts characters, not bytes",
+			text:  "Ça va. Très bien.",
+			limit: 17,
+			want:  []string{"Ça va. Très bien."},
+		},
+		{
+			name:  "CJK sentences",
+			text:  "今日は晴れです。明日は雨です。",
+			limit: 8,
+			want:  []string{"今日は晴れです。", "明日は雨です。"},
+		},
+		{
+			name:  "SSML is never split",
+			text:  "<speak>One. Two. Three.</speak>",
+			limit: 5,
+			want:  []string{"<speak>One. Two. Three.</speak>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Chunk(tt.text, tt.limit)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Chunk(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitFirstSentence(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []string
+	}{
+		{
+			name:   "splits the first sentence",
+			chunks: []string{"Done. The build passed.\n\nAll tests ran.", "Next chunk."},
+			want:   []string{"Done.", "The build passed.\n\nAll tests ran.", "Next chunk."},
+		},
+		{
+			name:   "single sentence",
+			chunks: []string{"Build completed."},
+			want:   []string{"Build completed."},
+		},
+		{
+			name:   "long first sentence",
+			chunks: []string{strings.Repeat("word ", 50) + "end. Short."},
+			want:   []string{strings.Repeat("word ", 50) + "end. Short."},
+		},
+		{
+			name:   "SSML",
+			chunks: []string{"<speak>One. Two.</speak>"},
+			want:   []string{"<speak>One. Two.</speak>"},
+		},
+		{
+			name:   "empty",
+			chunks: nil,
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitFirstSentence(tt.chunks)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitFirstSentence(%q) = %q, want %q", tt.chunks, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunk_LongText(t *testing.T) {
+	paragraph := strings.Repeat("The worker pool synthesizes each chunk in parallel, then plays them in order. ", 20)
+	text := strings.Repeat(paragraph+"\n\n", 5)
+
+	chunks := Chunk(text, 500)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	for i, c := range chunks {
+		if n := utf8.RuneCountInString(c); n > 500 {
+			t.Errorf("chunk %d has %d characters", i, n)
+		}
+		if !strings.HasSuffix(c, ".") {
+			t.Errorf("chunk %d does not end at a sentence: %q", i, c[len(c)-20:])
+		}
+	}
+	if strings.Join(strings.Fields(strings.Join(chunks, " ")), " ") != strings.Join(strings.Fields(text), " ") {
+		t.Error("expected the chunks to contain the whole text in order")
+	}
+}
+
+func TestMaxTextLength(t *testing.T) {
+	if n := MaxTextLength(NewClient()); n != openAIMaxInput {
+		t.Errorf("expected OpenAI limit %d, got %d", openAIMaxInput, n)
+	}
+	if n := MaxTextLength(&stubSynthesizer{}); n != 0 {
+		t.Errorf("expected no limit, got %d", n)
+	}
+
+	chain := NewChain(config.BreakerConfig{}, &stubSynthesizer{name: "local"}, NewClient(), &Polly{})
+	if n := chain.MaxTextLength(); n != 3000 {
+		t.Errorf("expected the chain to use the smallest limit, got %d", n)
+	}
+}
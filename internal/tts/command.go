@@ -0,0 +1,237 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ybouhjira/claude-code-tts/internal/audio"
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func init() {
+	Register("command", newCommandProvider)
+}
+
+// Command template placeholders
+const (
+	placeholderText  = "{text}"
+	placeholderVoice = "{voice}"
+	placeholderOut   = "{out}"
+)
+
+// CommandConfig holds the settings of a command-template provider
+type CommandConfig struct {
+	// Command is the command line template, e.g. "say -v {voice} -o {out}".
+	// Without {text} the text is written to stdin; without {out} the audio
+	// is read from stdout.
+	Command string   `json:"command"`
+	Format  string   `json:"format"` // audio format produced (default: wav)
+	Voice   string   `json:"voice"`  // default voice substituted for {voice}
+	Voices  []string `json:"voices"` // voices accepted by the engine (default: any)
+}
+
+// Command runs a user-defined command line to synthesize speech
+type Command struct {
+	name   string
+	args   []string
+	format Format
+	voice  string
+	voices []Voice
+}
+
+// NewCommand parses the command template and creates the synthesizer
+func NewCommand(cfg CommandConfig) (*Command, error) {
+	args, err := splitArgs(cfg.Command)
+	if err != nil {
+		return nil, fmt.Errorf("command: invalid template: %w", err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("command: template is empty")
+	}
+	args[0] = config.ExpandPath(args[0])
+
+	format := Format(cfg.Format)
+	if format == "" {
+		format = FormatWAV
+	}
+	if format == FormatNone && strings.Contains(cfg.Command, placeholderOut) {
+		return nil, fmt.Errorf("command: format %q cannot be combined with %s", FormatNone, placeholderOut)
+	}
+	if format != FormatNone && !slices.Contains(audio.Formats(), string(format)) {
+		return nil, fmt.Errorf("command: unsupported format %q (use %s or none)", format, strings.Join(audio.Formats(), ", "))
+	}
+
+	c := &Command{
+		name:   "command",
+		args:   args,
+		format: format,
+		voice:  cfg.Voice,
+	}
+	if cfg.Voice != "" {
+		c.voices = append(c.voices, Voice(cfg.Voice))
+	}
+	for _, v := range cfg.Voices {
+		if v != cfg.Voice {
+			c.voices = append(c.voices, Voice(v))
+		}
+	}
+	return c, nil
+}
+
+// newCommandProvider is the registry factory for the "command" provider type
+func newCommandProvider(pc config.ProviderConfig) (Synthesizer, error) {
+	var cfg CommandConfig
+	if err := pc.Decode(&cfg); err != nil {
+		return nil, err
+	}
+	c, err := NewCommand(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if pc.Name != "" {
+		c.name = pc.Name
+	}
+	return c, nil
+}
+
+// Name returns the provider name
+func (c *Command) Name() string {
+	return c.name
+}
+
+// Voices returns the configured voices, or nil to accept any voice
+func (c *Command) Voices() []Voice {
+	return c.voices
+}
+
+// PlaysAudio reports whether the engine speaks itself (format none)
+func (c *Command) PlaysAudio() bool {
+	return c.format == FormatNone
+}
+
+// expand substitutes the placeholders in the template arguments. An
+// argument that starts with text like "-o file" would be read as an
+// option, so the text gets a leading space there unless "--" came first.
+func (c *Command) expand(text string, voice Voice, out string) []string {
+	if voice == "" {
+		voice = Voice(c.voice)
+	}
+	replace := func(arg, text string) string {
+		return strings.NewReplacer(
+			placeholderText, text,
+			placeholderVoice, string(voice),
+			placeholderOut, out,
+		).Replace(arg)
+	}
+
+	args := make([]string, len(c.args))
+	options := true
+	for i, arg := range c.args {
+		if options && i > 0 && strings.HasPrefix(arg, placeholderText) && strings.HasPrefix(text, "-") {
+			args[i] = replace(arg, " "+text)
+		} else {
+			args[i] = replace(arg, text)
+		}
+		if arg == "--" {
+			options = false
+		}
+	}
+	return args
+}
+
+// usesPlaceholder reports whether any template argument contains p
+func (c *Command) usesPlaceholder(p string) bool {
+	for _, arg := range c.args {
+		if strings.Contains(arg, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Synthesize runs the command and collects its audio from stdout or {out}
+func (c *Command) Synthesize(ctx context.Context, r Request) (*Audio, error) {
+	var out string
+	if c.usesPlaceholder(placeholderOut) {
+		f, err := os.CreateTemp("", "tts-cmd-*."+string(c.format))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+		out = f.Name()
+		f.Close()
+		defer os.Remove(out)
+	}
+
+	var stdin io.Reader
+	if !c.usesPlaceholder(placeholderText) {
+		stdin = strings.NewReader(r.Text)
+	}
+
+	args := c.expand(r.Text, r.Voice, out)
+	stdout, err := runCommand(ctx, args[0], args[1:], stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.format == FormatNone {
+		return &Audio{Format: FormatNone}, nil
+	}
+
+	data := stdout
+	if out != "" {
+		if data, err = os.ReadFile(out); err != nil {
+			return nil, fmt.Errorf("failed to read command output: %w", err)
+		}
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%s produced no audio", args[0])
+	}
+	return &Audio{Data: data, Format: c.format}, nil
+}
+
+// splitArgs splits a command line into arguments, honoring single and
+// double quotes. No shell is involved, so the spoken text is never
+// interpreted as shell syntax.
+func splitArgs(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		quote   rune
+		inArg   bool
+	)
+
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
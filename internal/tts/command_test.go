@@ -0,0 +1,162 @@
+package tts
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{"festival --tts", []string{"festival", "--tts"}, false},
+		{"say  -o {out}", []string{"say", "-o", "{out}"}, false},
+		{`my-tts --name "two words" '{text}'`, []string{"my-tts", "--name", "two words", "{text}"}, false},
+		{`cmd ""`, []string{"cmd", ""}, false},
+		{`cmd "unterminated`, nil, true},
+		{"", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := splitArgs(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitArgs(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCommand_Validation(t *testing.T) {
+	if _, err := NewCommand(CommandConfig{}); err == nil {
+		t.Error("expected error for empty template")
+	}
+	if _, err := NewCommand(CommandConfig{Command: "engine -o {out}", Format: "none"}); err == nil {
+		t.Error("expected error for format none with {out}")
+	}
+	if _, err := NewCommand(CommandConfig{Command: "engine -o {out}", Format: "mp4v"}); err == nil {
+		t.Error("expected error for a format the player cannot play")
+	}
+
+	c, err := NewCommand(CommandConfig{Command: "festival --tts"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.format != FormatWAV {
+		t.Errorf("expected default format wav, got %s", c.format)
+	}
+	if c.Voices() != nil {
+		t.Errorf("expected no voice list, got %v", c.Voices())
+	}
+}
+
+func TestCommand_Expand(t *testing.T) {
+	c, err := NewCommand(CommandConfig{Command: "say -v {voice} -o {out} --text={text}", Voice: "Samantha"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := c.expand("it's $(rm -rf) fine", "", "/tmp/out.aiff")
+	want := []string{"say", "-v", "Samantha", "-o", "/tmp/out.aiff", "--text=it's $(rm -rf) fine"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expand() = %q, want %q", got, want)
+	}
+
+	got = c.expand("hi", "Alex", "")
+	if got[2] != "Alex" {
+		t.Errorf("expected explicit voice, got %q", got[2])
+	}
+}
+
+func TestCommand_ExpandOptionLikeText(t *testing.T) {
+	tests := []struct {
+		template string
+		text     string
+		want     []string
+	}{
+		{"say {text}", "-o/home/u/.bashrc hi", []string{"say", " -o/home/u/.bashrc hi"}},
+		{"say -- {text}", "-o/home/u/.bashrc hi", []string{"say", "--", "-o/home/u/.bashrc hi"}},
+		{"say --text={text}", "-5 degrees", []string{"say", "--text=-5 degrees"}},
+		{"say {text}", "minus -5", []string{"say", "minus -5"}},
+	}
+	for _, tt := range tests {
+		c, err := NewCommand(CommandConfig{Command: tt.template})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := c.expand(tt.text, "", ""); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expand(%q) = %q, want %q", tt.template, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCommand_SynthesizeStdout(t *testing.T) {
+	bin := writeFakeEngine(t, `cat`)
+
+	c, err := NewCommand(CommandConfig{Command: bin, Format: "mp3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audio, err := c.Synthesize(context.Background(), Request{Text: "from stdin"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if audio.Format != FormatMP3 || string(audio.Data) != "from stdin" {
+		t.Errorf("unexpected audio: %s %q", audio.Format, audio.Data)
+	}
+}
+
+func TestCommand_SynthesizeOutFile(t *testing.T) {
+	bin := writeFakeEngine(t, `printf '%s:%s' "$2" "$4" > "$6"`)
+
+	c, err := NewCommand(CommandConfig{Command: bin + " --voice {voice} --text {text} -o {out}", Format: "aiff", Voice: "v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audio, err := c.Synthesize(context.Background(), Request{Text: "hello there"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if audio.Format != "aiff" {
+		t.Errorf("expected aiff, got %s", audio.Format)
+	}
+	if string(audio.Data) != "v1:hello there" {
+		t.Errorf("unexpected audio %q", audio.Data)
+	}
+}
+
+func TestCommand_SynthesizeSelfPlaying(t *testing.T) {
+	bin := writeFakeEngine(t, `cat > /dev/null`)
+
+	c, err := NewCommand(CommandConfig{Command: bin + " --tts", Format: "none"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audio, err := c.Synthesize(context.Background(), Request{Text: "played by the engine"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if audio.Format != FormatNone || len(audio.Data) != 0 {
+		t.Errorf("expected no audio to play, got %s (%d bytes)", audio.Format, len(audio.Data))
+	}
+}
+
+func TestCommand_SynthesizeNoOutput(t *testing.T) {
+	bin := writeFakeEngine(t, `cat > /dev/null`)
+
+	c, _ := NewCommand(CommandConfig{Command: bin})
+	_, err := c.Synthesize(context.Background(), Request{Text: "lost"})
+	if err == nil || !strings.Contains(err.Error(), "no audio") {
+		t.Errorf("expected 'no audio' error, got: %v", err)
+	}
+}
@@ -0,0 +1,333 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func init() {
+	Register("elevenlabs", newElevenLabsProvider)
+}
+
+const (
+	elevenLabsBaseURL = "https://api.elevenlabs.io"
+	// elevenLabsDefaultVoice is the "Rachel" premade voice
+	elevenLabsDefaultVoice = "21m00Tcm4TlvDq8ikWAM"
+	elevenLabsDefaultModel = "eleven_multilingual_v2"
+)
+
+// ElevenLabsConfig holds the settings of the ElevenLabs provider
+type ElevenLabsConfig struct {
+	BaseURL         string   `json:"base_url"`         // API root (default: https://api.elevenlabs.io)
+	APIKeyEnv       string   `json:"api_key_env"`      // variable holding the key (default: ELEVENLABS_API_KEY)
+	Model           string   `json:"model"`            // model_id (default: eleven_multilingual_v2)
+	Voice           string   `json:"voice"`            // default voice ID or name
+	OutputFormat    string   `json:"output_format"`    // e.g. mp3_44100_128
+	Stability       *float64 `json:"stability"`        // 0-1
+	SimilarityBoost *float64 `json:"similarity_boost"` // 0-1
+	Style           *float64 `json:"style"`            // 0-1
+	SpeakerBoost    *bool    `json:"speaker_boost"`
+}
+
+// ElevenLabsVoice is a voice returned by /v1/voices
+type ElevenLabsVoice struct {
+	VoiceID  string `json:"voice_id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// ElevenLabs handles ElevenLabs text-to-speech requests
+type ElevenLabs struct {
+	name       string
+	apiKey     string
+	cfg        ElevenLabsConfig
+	httpClient *http.Client
+
+	voices voiceCache[ElevenLabsVoice]
+}
+
+// NewElevenLabs creates an ElevenLabs client
+func NewElevenLabs(cfg ElevenLabsConfig) (*ElevenLabs, error) {
+	if cfg.APIKeyEnv == "" {
+		cfg.APIKeyEnv = "ELEVENLABS_API_KEY"
+	}
+	apiKey := os.Getenv(cfg.APIKeyEnv)
+	if apiKey == "" {
+		return nil, fmt.Errorf("%s environment variable is required", cfg.APIKeyEnv)
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = elevenLabsBaseURL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.Model == "" {
+		cfg.Model = elevenLabsDefaultModel
+	}
+	if cfg.Voice == "" {
+		cfg.Voice = elevenLabsDefaultVoice
+	}
+	if cfg.OutputFormat == "" {
+		cfg.OutputFormat = "mp3_44100_128"
+	}
+	for name, v := range map[string]*float64{"stability": cfg.Stability, "similarity_boost": cfg.SimilarityBoost, "style": cfg.Style} {
+		if v != nil && (*v < 0 || *v > 1) {
+			return nil, fmt.Errorf("elevenlabs: %s must be between 0 and 1, got %g", name, *v)
+		}
+	}
+
+	return &ElevenLabs{
+		name:   "elevenlabs",
+		apiKey: apiKey,
+		cfg:    cfg,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}, nil
+}
+
+// newElevenLabsProvider is the registry factory for the "elevenlabs" provider type
+func newElevenLabsProvider(pc config.ProviderConfig) (Synthesizer, error) {
+	var cfg ElevenLabsConfig
+	if err := pc.Decode(&cfg); err != nil {
+		return nil, err
+	}
+	e, err := NewElevenLabs(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if pc.Name != "" {
+		e.name = pc.Name
+	}
+	return e, nil
+}
+
+// Name returns the provider name
+func (e *ElevenLabs) Name() string {
+	return e.name
+}
+
+// Model returns the model_id sent with each request
+func (e *ElevenLabs) Model() string {
+	return e.cfg.Model
+}
+
+// ListVoices fetches the voices available to the account
+func (e *ElevenLabs) ListVoices(ctx context.Context) ([]ElevenLabsVoice, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", e.cfg.BaseURL+"/v1/voices", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("xi-api-key", e.apiKey)
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseElevenLabsError(resp)
+	}
+
+	var body struct {
+		Voices []ElevenLabsVoice `json:"voices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode voices: %w", err)
+	}
+	return body.Voices, nil
+}
+
+// cachedVoices fetches the voice list until it succeeds; failures yield
+// an empty list
+func (e *ElevenLabs) cachedVoices() []ElevenLabsVoice {
+	return e.voices.get(e.ListVoices)
+}
+
+// MaxTextLength returns the per-request character limit of the
+// multilingual models (flash and turbo models accept more)
+func (e *ElevenLabs) MaxTextLength() int {
+	return 5000
+}
+
+// Voices returns the account's voice names, default voice first.
+// It returns nil (accept any voice ID) if the list cannot be fetched.
+func (e *ElevenLabs) Voices() []Voice {
+	available := e.cachedVoices()
+	if len(available) == 0 {
+		return nil
+	}
+
+	defaultID := e.resolveVoice(Voice(e.cfg.Voice))
+	voices := make([]Voice, 0, len(available)+1)
+	for _, v := range available {
+		if v.VoiceID == defaultID {
+			voices = append([]Voice{Voice(v.Name)}, voices...)
+		} else {
+			voices = append(voices, Voice(v.Name))
+		}
+	}
+	// Voice IDs are always accepted as well
+	for _, v := range available {
+		voices = append(voices, Voice(v.VoiceID))
+	}
+	return voices
+}
+
+// resolveVoice maps a voice name to its ID; unknown values are used as IDs
+func (e *ElevenLabs) resolveVoice(voice Voice) string {
+	if voice == "" {
+		voice = Voice(e.cfg.Voice)
+	}
+	for _, v := range e.cachedVoices() {
+		if strings.EqualFold(v.Name, string(voice)) {
+			return v.VoiceID
+		}
+	}
+	return string(voice)
+}
+
+// elevenLabsVoiceSettings is the voice_settings request object
+type elevenLabsVoiceSettings struct {
+	Stability       *float64 `json:"stability,omitempty"`
+	SimilarityBoost *float64 `json:"similarity_boost,omitempty"`
+	Style           *float64 `json:"style,omitempty"`
+	SpeakerBoost    *bool    `json:"use_speaker_boost,omitempty"`
+	Speed           *float64 `json:"speed,omitempty"`
+}
+
+// ElevenLabs voices speak from 0.7 to 1.2 times their normal speed
+const (
+	elevenLabsMinSpeed = 0.7
+	elevenLabsMaxSpeed = 1.2
+)
+
+// elevenLabsRequest is the text-to-speech request payload
+type elevenLabsRequest struct {
+	Text          string                   `json:"text"`
+	ModelID       string                   `json:"model_id"`
+	VoiceSettings *elevenLabsVoiceSettings `json:"voice_settings,omitempty"`
+}
+
+// Synthesize converts text to speech and returns MP3 audio data. The
+// speed is limited to the 0.7-1.2 the voices support.
+func (e *ElevenLabs) Synthesize(ctx context.Context, r Request) (*Audio, error) {
+	reqBody := elevenLabsRequest{
+		Text:    r.Text,
+		ModelID: e.cfg.Model,
+	}
+	var speed *float64
+	if r.Speed > 0 && r.Speed != 1 {
+		s := max(elevenLabsMinSpeed, min(elevenLabsMaxSpeed, r.Speed))
+		speed = &s
+	}
+	if e.cfg.Stability != nil || e.cfg.SimilarityBoost != nil || e.cfg.Style != nil || e.cfg.SpeakerBoost != nil || speed != nil {
+		reqBody.VoiceSettings = &elevenLabsVoiceSettings{
+			Stability:       e.cfg.Stability,
+			SimilarityBoost: e.cfg.SimilarityBoost,
+			Style:           e.cfg.Style,
+			SpeakerBoost:    e.cfg.SpeakerBoost,
+			Speed:           speed,
+		}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/text-to-speech/%s?output_format=%s",
+		e.cfg.BaseURL, url.PathEscape(e.resolveVoice(r.Voice)), url.QueryEscape(e.cfg.OutputFormat))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("xi-api-key", e.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "audio/mpeg")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parseElevenLabsError(resp)
+	}
+
+	audioData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return elevenLabsAudio(audioData, e.cfg.OutputFormat), nil
+}
+
+// elevenLabsAudio wraps response data according to its output_format,
+// e.g. mp3_44100_128 or pcm_24000. Raw PCM is converted to WAV.
+func elevenLabsAudio(data []byte, outputFormat string) *Audio {
+	codec, rest, _ := strings.Cut(outputFormat, "_")
+	if codec == "pcm" {
+		rate, _, _ := strings.Cut(rest, "_")
+		if sampleRate, err := strconv.Atoi(rate); err == nil {
+			return &Audio{Data: pcmToWAV(data, sampleRate, 1, 16), Format: FormatWAV}
+		}
+	}
+	return &Audio{Data: data, Format: Format(codec)}
+}
+
+// parseElevenLabsError extracts the message from an ElevenLabs error body.
+// "detail" is either a string, an object with status/message, or a list of
+// validation errors.
+func parseElevenLabsError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	var parsed struct {
+		Detail json.RawMessage `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil && len(parsed.Detail) > 0 {
+		var msg string
+		if json.Unmarshal(parsed.Detail, &msg) == nil && msg != "" {
+			return newAPIError(resp, "ElevenLabs API error (status %d): %s", resp.StatusCode, msg)
+		}
+
+		var detail struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(parsed.Detail, &detail) == nil && detail.Message != "" {
+			apiErr := newAPIError(resp, "ElevenLabs API error (status %d, %s): %s", resp.StatusCode, detail.Status, detail.Message)
+			apiErr.Code = detail.Status
+			// Character quota is reported as a 401
+			if detail.Status == "quota_exceeded" {
+				apiErr.Kind = ErrQuotaExceeded
+			}
+			return apiErr
+		}
+
+		var list []struct {
+			Msg string `json:"msg"`
+		}
+		if json.Unmarshal(parsed.Detail, &list) == nil && len(list) > 0 {
+			msgs := make([]string, 0, len(list))
+			for _, item := range list {
+				msgs = append(msgs, item.Msg)
+			}
+			return newAPIError(resp, "ElevenLabs API error (status %d): %s", resp.StatusCode, strings.Join(msgs, "; "))
+		}
+	}
+
+	return newAPIError(resp, "ElevenLabs API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
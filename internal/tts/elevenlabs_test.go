@@ -0,0 +1,230 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newElevenLabsTestServer stubs the ElevenLabs voices and TTS endpoints
+func newElevenLabsTestServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *ElevenLabs) {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/voices", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("xi-api-key") != "test-key" {
+			t.Errorf("expected xi-api-key header, got %q", r.Header.Get("xi-api-key"))
+		}
+		_, _ = w.Write([]byte(`{"voices": [
+			{"voice_id": "id-adam", "name": "Adam", "category": "premade"},
+			{"voice_id": "21m00Tcm4TlvDq8ikWAM", "name": "Rachel", "category": "premade"}
+		]}`))
+	})
+	mux.HandleFunc("/v1/text-to-speech/", handler)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	t.Setenv("ELEVENLABS_API_KEY", "test-key")
+	stability, similarity := 0.3, 0.8
+	e, err := NewElevenLabs(ElevenLabsConfig{
+		BaseURL:         server.URL,
+		Stability:       &stability,
+		SimilarityBoost: &similarity,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, e
+}
+
+func TestNewElevenLabs_RequiresKey(t *testing.T) {
+	t.Setenv("ELEVENLABS_API_KEY", "")
+
+	_, err := NewElevenLabs(ElevenLabsConfig{})
+	if err == nil || !strings.Contains(err.Error(), "ELEVENLABS_API_KEY") {
+		t.Errorf("expected ELEVENLABS_API_KEY error, got: %v", err)
+	}
+}
+
+func TestNewElevenLabs_CustomKeyEnv(t *testing.T) {
+	t.Setenv("TEAM_XI_KEY", "team-key")
+
+	e, err := NewElevenLabs(ElevenLabsConfig{APIKeyEnv: "TEAM_XI_KEY"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.apiKey != "team-key" {
+		t.Errorf("expected key from TEAM_XI_KEY, got %q", e.apiKey)
+	}
+	if e.cfg.Model != "eleven_multilingual_v2" || e.cfg.Voice != elevenLabsDefaultVoice {
+		t.Errorf("unexpected defaults: %+v", e.cfg)
+	}
+}
+
+func TestNewElevenLabs_InvalidSettings(t *testing.T) {
+	t.Setenv("ELEVENLABS_API_KEY", "k")
+	bad := 1.5
+
+	if _, err := NewElevenLabs(ElevenLabsConfig{Stability: &bad}); err == nil {
+		t.Error("expected error for stability > 1")
+	}
+}
+
+func TestElevenLabs_Voices(t *testing.T) {
+	_, e := newElevenLabsTestServer(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	voices := e.Voices()
+	if len(voices) != 4 {
+		t.Fatalf("expected 2 names and 2 IDs, got %v", voices)
+	}
+	if voices[0] != "Rachel" {
+		t.Errorf("expected default voice Rachel first, got %s", voices[0])
+	}
+	if !SupportsVoice(e, "Adam") || !SupportsVoice(e, "id-adam") || SupportsVoice(e, "Nobody") {
+		t.Error("expected names and IDs to be supported")
+	}
+}
+
+func TestElevenLabs_VoicesRetriedAfterFailure(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"voices": [{"voice_id": "id-adam", "name": "Adam"}]}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("ELEVENLABS_API_KEY", "test-key")
+	e, err := NewElevenLabs(ElevenLabsConfig{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := e.resolveVoice("Adam"); got != "Adam" {
+		t.Errorf("expected the name to pass through while the list is unavailable, got %q", got)
+	}
+	if got := e.resolveVoice("Adam"); got != "Adam" || calls != 1 {
+		t.Errorf("expected no new request within the retry interval, got %q after %d calls", got, calls)
+	}
+
+	e.voices.failedAt = time.Time{}
+	if got := e.resolveVoice("Adam"); got != "id-adam" {
+		t.Errorf("expected the name to resolve once the list is fetched, got %q", got)
+	}
+	e.resolveVoice("Adam")
+	if calls != 2 {
+		t.Errorf("expected the successful list to be cached, got %d calls", calls)
+	}
+}
+
+func TestElevenLabs_Synthesize(t *testing.T) {
+	_, e := newElevenLabsTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if r.URL.Path != "/v1/text-to-speech/id-adam" {
+			t.Errorf("expected voice name resolved to ID, got path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("output_format") != "mp3_44100_128" {
+			t.Errorf("unexpected output_format %q", r.URL.Query().Get("output_format"))
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var req elevenLabsRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Fatalf("failed to unmarshal request: %v", err)
+		}
+		if req.Text != "Hello" || req.ModelID != "eleven_multilingual_v2" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		if req.VoiceSettings == nil || *req.VoiceSettings.Stability != 0.3 || *req.VoiceSettings.SimilarityBoost != 0.8 {
+			t.Errorf("expected voice settings to be sent, got %s", body)
+		}
+		if req.VoiceSettings.Style != nil {
+			t.Error("expected unset style to be omitted")
+		}
+
+		_, _ = w.Write([]byte("xi-audio"))
+	})
+
+	audio, err := e.Synthesize(context.Background(), Request{Text: "Hello", Voice: "adam"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(audio.Data) != "xi-audio" || audio.Format != FormatMP3 {
+		t.Errorf("unexpected audio: %s %q", audio.Format, audio.Data)
+	}
+}
+
+func TestElevenLabs_Speed(t *testing.T) {
+	var speeds []*float64
+	_, e := newElevenLabsTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var req elevenLabsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to unmarshal request: %v", err)
+		}
+		speeds = append(speeds, req.VoiceSettings.Speed)
+		_, _ = w.Write([]byte("xi-audio"))
+	})
+
+	for _, speed := range []float64{0, 1.1, 3} {
+		if _, err := e.Synthesize(context.Background(), Request{Text: "Hello", Speed: speed}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if speeds[0] != nil {
+		t.Errorf("expected no speed by default, got %v", *speeds[0])
+	}
+	if speeds[1] == nil || *speeds[1] != 1.1 || speeds[2] == nil || *speeds[2] != elevenLabsMaxSpeed {
+		t.Errorf("expected speeds 1.1 and %v, got %v and %v", elevenLabsMaxSpeed, speeds[1], speeds[2])
+	}
+}
+
+func TestElevenLabs_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"object detail", 401, `{"detail": {"status": "invalid_api_key", "message": "Invalid API key"}}`, "invalid_api_key): Invalid API key"},
+		{"string detail", 404, `{"detail": "Voice not found"}`, "Voice not found"},
+		{"validation list", 422, `{"detail": [{"loc": ["body", "text"], "msg": "field required"}]}`, "field required"},
+		{"plain body", 500, `upstream timeout`, "upstream timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, e := newElevenLabsTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			_, err := e.Synthesize(context.Background(), Request{Text: "Hello"})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error to contain %q, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestElevenLabsAudio(t *testing.T) {
+	if a := elevenLabsAudio([]byte("x"), "mp3_44100_128"); a.Format != FormatMP3 || string(a.Data) != "x" {
+		t.Errorf("expected mp3 passthrough, got %s", a.Format)
+	}
+
+	a := elevenLabsAudio([]byte{0, 0}, "pcm_16000")
+	if a.Format != FormatWAV || len(a.Data) != 46 {
+		t.Errorf("expected PCM wrapped as WAV, got %s (%d bytes)", a.Format, len(a.Data))
+	}
+}
@@ -0,0 +1,140 @@
+package tts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Error classes returned by providers. Use errors.Is to test for them.
+var (
+	// ErrUnauthorized means the credentials were missing or rejected (permanent)
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited means too many requests were sent (retryable)
+	ErrRateLimited = errors.New("rate limited")
+	// ErrQuotaExceeded means the account is out of credit or quota (permanent)
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrInvalidInput means the request itself was rejected (permanent)
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrServerError means the provider failed or timed out (retryable)
+	ErrServerError = errors.New("server error")
+	// ErrBudgetExceeded means a configured spending cap was reached
+	// (permanent until the day or month rolls over)
+	ErrBudgetExceeded = errors.New("budget exceeded")
+)
+
+// APIError is a non-success response from a TTS HTTP API
+type APIError struct {
+	StatusCode int
+	Message    string
+	// Code is the provider's error code, e.g. insufficient_quota
+	Code string
+	// Kind is one of the Err* classes, or nil if unclassified
+	Kind error
+	// RetryAfter is how long the server asked us to wait (zero if unknown)
+	RetryAfter time.Duration
+}
+
+// newAPIError builds an APIError for resp with a formatted message,
+// classified by status code
+func newAPIError(resp *http.Response, format string, args ...interface{}) *APIError {
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    fmt.Sprintf(format, args...),
+		Kind:       classifyStatus(resp.StatusCode),
+		RetryAfter: retryAfter(resp.Header, time.Now()),
+	}
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// Unwrap exposes the error class to errors.Is
+func (e *APIError) Unwrap() error {
+	return e.Kind
+}
+
+// classifyStatus maps an HTTP status code to an error class
+func classifyStatus(status int) error {
+	switch {
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return ErrUnauthorized
+	case status == http.StatusPaymentRequired:
+		return ErrQuotaExceeded
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusRequestTimeout, status >= 500:
+		return ErrServerError
+	case status >= 400:
+		return ErrInvalidInput
+	}
+	return nil
+}
+
+// IsRetryable reports whether err is worth retrying: rate limits and
+// server errors from an API, or a round trip that timed out, was refused
+// or reset, or was cut off mid-response
+func IsRetryable(err error) bool {
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServerError) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// ErrorKind returns a short name for the class of err, e.g. quota_exceeded,
+// or "" if it is unclassified
+func ErrorKind(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, ErrQuotaExceeded):
+		return "quota_exceeded"
+	case errors.Is(err, ErrInvalidInput):
+		return "invalid_input"
+	case errors.Is(err, ErrServerError):
+		return "server_error"
+	case errors.Is(err, ErrBudgetExceeded):
+		return "budget_exceeded"
+	}
+	return ""
+}
+
+// retryAfter reads the wait requested by Retry-After (seconds or an HTTP
+// date) or, failing that, the longest OpenAI x-ratelimit-reset-* value
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	if v := strings.TrimSpace(h.Get("Retry-After")); v != "" {
+		if secs, err := strconv.ParseFloat(v, 64); err == nil && secs >= 0 {
+			return time.Duration(secs * float64(time.Second))
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			if d := t.Sub(now); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+
+	var wait time.Duration
+	for _, name := range []string{"X-Ratelimit-Reset-Requests", "X-Ratelimit-Reset-Tokens"} {
+		if d, err := time.ParseDuration(strings.TrimSpace(h.Get(name))); err == nil && d > wait {
+			wait = d
+		}
+	}
+	return wait
+}
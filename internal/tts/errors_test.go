@@ -0,0 +1,169 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+	}{
+		{"none", nil, 0},
+		{"seconds", map[string]string{"Retry-After": "3"}, 3 * time.Second},
+		{"fractional seconds", map[string]string{"Retry-After": "0.5"}, 500 * time.Millisecond},
+		{"http date", map[string]string{"Retry-After": now.Add(10 * time.Second).Format(http.TimeFormat)}, 10 * time.Second},
+		{"past date", map[string]string{"Retry-After": now.Add(-time.Minute).Format(http.TimeFormat)}, 0},
+		{"ratelimit reset", map[string]string{"x-ratelimit-reset-requests": "1s", "x-ratelimit-reset-tokens": "6m0s"}, 6 * time.Minute},
+		{"retry-after wins", map[string]string{"Retry-After": "2", "x-ratelimit-reset-requests": "20ms"}, 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			if got := retryAfter(h, now); got != tt.want {
+				t.Errorf("retryAfter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyStatus_Retryable(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{400, false},
+		{401, false},
+		{404, false},
+		{408, true},
+		{429, true},
+		{500, true},
+		{503, true},
+	}
+
+	for _, tt := range tests {
+		err := &APIError{StatusCode: tt.status, Kind: classifyStatus(tt.status)}
+		if got := IsRetryable(err); got != tt.want {
+			t.Errorf("IsRetryable() for status %d = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestParseOpenAIError(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantKind  error
+		wantCode  string
+		wantMsg   string
+		retryable bool
+	}{
+		{"invalid key", 401, `{"error": {"message": "Incorrect API key provided", "type": "invalid_request_error", "code": "invalid_api_key"}}`,
+			ErrUnauthorized, "invalid_api_key", "API error (status 401, invalid_api_key): Incorrect API key provided", false},
+		{"rate limit", 429, `{"error": {"message": "Rate limit reached", "type": "requests", "code": "rate_limit_exceeded"}}`,
+			ErrRateLimited, "rate_limit_exceeded", "API error (status 429, rate_limit_exceeded): Rate limit reached", true},
+		{"quota", 429, `{"error": {"message": "You exceeded your current quota", "type": "insufficient_quota", "code": "insufficient_quota"}}`,
+			ErrQuotaExceeded, "insufficient_quota", "API error (status 429, insufficient_quota): You exceeded your current quota", false},
+		{"invalid input", 400, `{"error": {"message": "Input is too long", "type": "invalid_request_error", "code": null}}`,
+			ErrInvalidInput, "invalid_request_error", "API error (status 400, invalid_request_error): Input is too long", false},
+		{"server error", 500, `{"error": {"message": "The server had an error"}}`,
+			ErrServerError, "", "API error (status 500): The server had an error", true},
+		{"plain text", 502, "Bad Gateway\n", ErrServerError, "", "API error (status 502): Bad Gateway", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(tt.body))}
+			err := parseOpenAIError(resp)
+
+			if !errors.Is(err, tt.wantKind) {
+				t.Errorf("expected %v, got kind %v", tt.wantKind, err.Kind)
+			}
+			if err.Code != tt.wantCode {
+				t.Errorf("expected code %q, got %q", tt.wantCode, err.Code)
+			}
+			if err.Error() != tt.wantMsg {
+				t.Errorf("expected message %q, got %q", tt.wantMsg, err.Error())
+			}
+			if IsRetryable(err) != tt.retryable {
+				t.Errorf("IsRetryable = %v, want %v", IsRetryable(err), tt.retryable)
+			}
+		})
+	}
+}
+
+func TestIsRetryable_Transport(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"timeout", &url.Error{Op: "Post", URL: "u", Err: context.DeadlineExceeded}, true},
+		{"connection reset", &url.Error{Op: "Post", URL: "u", Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, true},
+		{"connection refused", &url.Error{Op: "Post", URL: "u", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, true},
+		{"truncated body", fmt.Errorf("read audio: %w", io.ErrUnexpectedEOF), true},
+		{"unknown host", &url.Error{Op: "Post", URL: "u", Err: &net.DNSError{Err: "no such host", Name: "api.example"}}, false},
+		{"unsupported scheme", &url.Error{Op: "Post", URL: "u", Err: errors.New("unsupported protocol scheme")}, false},
+		{"canceled", &url.Error{Op: "Post", URL: "u", Err: context.Canceled}, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: IsRetryable = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{errors.New("boom"), ""},
+		{&APIError{Kind: ErrQuotaExceeded}, "quota_exceeded"},
+		{fmt.Errorf("wrapped: %w", &APIError{Kind: ErrRateLimited}), "rate_limited"},
+		{ErrUnauthorized, "unauthorized"},
+		{ErrInvalidInput, "invalid_input"},
+		{ErrServerError, "server_error"},
+		{fmt.Errorf("%w: daily limit", ErrBudgetExceeded), "budget_exceeded"},
+	}
+
+	for _, tt := range tests {
+		if got := ErrorKind(tt.err); got != tt.want {
+			t.Errorf("ErrorKind(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestProviderErrorClasses(t *testing.T) {
+	polly := &http.Response{StatusCode: 400, Header: http.Header{"X-Amzn-Errortype": {"ThrottlingException:http://internal.amazon.com/"}},
+		Body: io.NopCloser(strings.NewReader(`{"message": "Rate exceeded"}`))}
+	if err := parsePollyError(polly); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected Polly throttling to be rate limited, got %v", err)
+	}
+
+	eleven := &http.Response{StatusCode: 401, Header: http.Header{},
+		Body: io.NopCloser(strings.NewReader(`{"detail": {"status": "quota_exceeded", "message": "This request exceeds your quota"}}`))}
+	if err := parseElevenLabsError(eleven); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected ElevenLabs quota error, got %v", err)
+	}
+}
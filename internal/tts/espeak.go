@@ -0,0 +1,133 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func init() {
+	Register("espeak", newEspeakProvider)
+}
+
+// EspeakVoices returns the espeak-ng voices offered by default.
+// espeak-ng accepts many more; variants are written as voice+variant (e.g. en-us+f3).
+func EspeakVoices() []Voice {
+	return []Voice{
+		"en-us", "en-gb", "en-us+f3", "en-us+m3",
+		"fr", "de", "es", "it", "pt", "pt-br", "nl", "pl", "ru",
+	}
+}
+
+// EspeakConfig holds the settings of the espeak-ng engine
+type EspeakConfig struct {
+	Binary string `json:"binary"` // espeak-ng executable (default: espeak-ng)
+	Voice  string `json:"voice"`  // default voice (default: en-us)
+	Rate   int    `json:"rate"`   // words per minute (default: 175)
+	Pitch  *int   `json:"pitch"`  // 0-99 (default: 50)
+}
+
+// Espeak shells out to espeak-ng. It needs no network or model files,
+// which makes it the engine of last resort.
+type Espeak struct {
+	name string
+	cfg  EspeakConfig
+}
+
+// NewEspeak creates an espeak-ng synthesizer
+func NewEspeak(cfg EspeakConfig) (*Espeak, error) {
+	if cfg.Binary == "" {
+		cfg.Binary = "espeak-ng"
+	}
+	cfg.Binary = config.ExpandPath(cfg.Binary)
+	if cfg.Voice == "" {
+		cfg.Voice = "en-us"
+	}
+	if cfg.Rate == 0 {
+		cfg.Rate = 175
+	}
+	if cfg.Rate < 80 || cfg.Rate > 450 {
+		return nil, fmt.Errorf("espeak: rate must be between 80 and 450 words per minute, got %d", cfg.Rate)
+	}
+	if cfg.Pitch == nil {
+		pitch := 50
+		cfg.Pitch = &pitch
+	}
+	if *cfg.Pitch < 0 || *cfg.Pitch > 99 {
+		return nil, fmt.Errorf("espeak: pitch must be between 0 and 99, got %d", *cfg.Pitch)
+	}
+	return &Espeak{name: "espeak", cfg: cfg}, nil
+}
+
+// newEspeakProvider is the registry factory for the "espeak" provider type
+func newEspeakProvider(pc config.ProviderConfig) (Synthesizer, error) {
+	var cfg EspeakConfig
+	if err := pc.Decode(&cfg); err != nil {
+		return nil, err
+	}
+	e, err := NewEspeak(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if pc.Name != "" {
+		e.name = pc.Name
+	}
+	return e, nil
+}
+
+// Name returns the provider name
+func (e *Espeak) Name() string {
+	return e.name
+}
+
+// Voices returns the configured default voice followed by EspeakVoices
+func (e *Espeak) Voices() []Voice {
+	voices := []Voice{Voice(e.cfg.Voice)}
+	for _, v := range EspeakVoices() {
+		if string(v) != e.cfg.Voice {
+			voices = append(voices, v)
+		}
+	}
+	return voices
+}
+
+// voiceFor maps a requested voice to an espeak-ng voice.
+// OpenAI voices (e.g. "nova") fall back to the default so espeak can stand
+// in for a failed cloud provider.
+func (e *Espeak) voiceFor(voice Voice) string {
+	if voice == "" || containsVoice(OpenAIVoices(), voice) {
+		return e.cfg.Voice
+	}
+	return string(voice)
+}
+
+// args builds the espeak-ng command line; a non-zero speed scales the
+// configured rate within espeak's 80-450 wpm range
+func (e *Espeak) args(voice Voice, speed float64) []string {
+	rate := e.cfg.Rate
+	if speed > 0 {
+		rate = int(float64(rate) * speed)
+		rate = max(80, min(450, rate))
+	}
+	return []string{
+		"--stdout", "--stdin",
+		"-v", e.voiceFor(voice),
+		"-s", strconv.Itoa(rate),
+		"-p", strconv.Itoa(*e.cfg.Pitch),
+	}
+}
+
+// Synthesize runs espeak-ng and returns WAV audio
+func (e *Espeak) Synthesize(ctx context.Context, r Request) (*Audio, error) {
+	data, err := runCommand(ctx, e.cfg.Binary, e.args(r.Voice, r.Speed), strings.NewReader(r.Text))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("espeak-ng produced no audio")
+	}
+	return &Audio{Data: data, Format: FormatWAV}, nil
+}
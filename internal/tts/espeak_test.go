@@ -0,0 +1,163 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewEspeak_Defaults(t *testing.T) {
+	e, err := NewEspeak(EspeakConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.cfg.Binary != "espeak-ng" {
+		t.Errorf("expected binary 'espeak-ng', got %q", e.cfg.Binary)
+	}
+	if e.cfg.Voice != "en-us" || e.cfg.Rate != 175 || *e.cfg.Pitch != 50 {
+		t.Errorf("unexpected defaults: %+v", e.cfg)
+	}
+	if e.Name() != "espeak" {
+		t.Errorf("expected name 'espeak', got %q", e.Name())
+	}
+}
+
+func TestNewEspeak_Validation(t *testing.T) {
+	high, negative := 150, -1
+	tests := []struct {
+		name string
+		cfg  EspeakConfig
+	}{
+		{"rate too low", EspeakConfig{Rate: 10}},
+		{"rate too high", EspeakConfig{Rate: 1000}},
+		{"pitch too high", EspeakConfig{Pitch: &high}},
+		{"negative pitch", EspeakConfig{Pitch: &negative}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewEspeak(tt.cfg); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestEspeak_Voices(t *testing.T) {
+	e, _ := NewEspeak(EspeakConfig{Voice: "fr"})
+
+	voices := e.Voices()
+	if voices[0] != "fr" {
+		t.Errorf("expected configured voice first, got %s", voices[0])
+	}
+	count := 0
+	for _, v := range voices {
+		if v == "fr" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected 'fr' to be listed once, got %d", count)
+	}
+}
+
+func TestEspeak_Args(t *testing.T) {
+	pitch := 30
+	e, _ := NewEspeak(EspeakConfig{Rate: 200, Pitch: &pitch})
+
+	tests := []struct {
+		voice Voice
+		want  string
+	}{
+		{"", "-v en-us"},
+		{VoiceNova, "-v en-us"}, // OpenAI voices fall back to the default
+		{"de", "-v de"},
+		{"en-us+f3", "-v en-us+f3"},
+	}
+
+	for _, tt := range tests {
+		args := strings.Join(e.args(tt.voice, 0), " ")
+		if !strings.Contains(args, tt.want) {
+			t.Errorf("args(%q) = %q, want %q", tt.voice, args, tt.want)
+		}
+		if !strings.Contains(args, "--stdout") || !strings.Contains(args, "-s 200") || !strings.Contains(args, "-p 30") {
+			t.Errorf("args(%q) missing output, rate or pitch: %q", tt.voice, args)
+		}
+	}
+}
+
+func TestEspeak_ZeroPitch(t *testing.T) {
+	var cfg EspeakConfig
+	if err := json.Unmarshal([]byte(`{"pitch": 0}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	e, err := NewEspeak(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args := strings.Join(e.args("", 0), " "); !strings.Contains(args, "-p 0") {
+		t.Errorf("expected pitch 0 to be kept, got %q", args)
+	}
+}
+
+func TestEspeak_ArgsSpeed(t *testing.T) {
+	e, _ := NewEspeak(EspeakConfig{Rate: 200})
+
+	if args := strings.Join(e.args("", 1.5), " "); !strings.Contains(args, "-s 300") {
+		t.Errorf("expected rate scaled to 300, got %q", args)
+	}
+	if args := strings.Join(e.args("", 4), " "); !strings.Contains(args, "-s 450") {
+		t.Errorf("expected rate clamped to 450, got %q", args)
+	}
+}
+
+func TestEspeak_Synthesize(t *testing.T) {
+	bin := writeFakeEngine(t, `printf 'RIFF'; cat`)
+
+	e, err := NewEspeak(EspeakConfig{Binary: bin})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audio, err := e.Synthesize(context.Background(), Request{Text: "offline", Voice: VoiceAlloy})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if audio.Format != FormatWAV {
+		t.Errorf("expected wav, got %s", audio.Format)
+	}
+	if string(audio.Data) != "RIFFoffline" {
+		t.Errorf("unexpected output %q", audio.Data)
+	}
+}
+
+func TestEspeak_SynthesizeMissingBinary(t *testing.T) {
+	e, _ := NewEspeak(EspeakConfig{Binary: "/nonexistent/espeak-ng"})
+
+	if _, err := e.Synthesize(context.Background(), Request{Text: "hi"}); err == nil {
+		t.Error("expected error for missing binary")
+	}
+}
+
+func TestEspeak_SynthesizeCancelled(t *testing.T) {
+	bin := writeFakeEngine(t, `sleep 10`)
+
+	e, err := NewEspeak(EspeakConfig{Binary: bin})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := e.Synthesize(ctx, Request{Text: "slow"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("expected the engine process to be killed")
+	}
+}
@@ -0,0 +1,36 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// runCommand runs an engine binary, feeding stdin and returning stdout.
+// The process is killed when ctx is cancelled. On failure the error
+// includes the engine's stderr output.
+func runCommand(ctx context.Context, name string, args []string, stdin io.Reader) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = stdin
+	// Don't wait for children that inherited stdout after a kill
+	cmd.WaitDelay = time.Second
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s failed: %w: %s", name, err, msg)
+		}
+		return nil, fmt.Errorf("%s failed: %w", name, err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,429 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func init() {
+	Register("google", newGoogleProvider)
+}
+
+const (
+	googleTTSEndpoint = "https://texttospeech.googleapis.com"
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleScope       = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// GoogleConfig holds the settings of the Google Cloud Text-to-Speech provider
+type GoogleConfig struct {
+	Credentials   string  `json:"credentials"`    // service account key file (default: GOOGLE_APPLICATION_CREDENTIALS)
+	LanguageCode  string  `json:"language_code"`  // default: en-US
+	Voice         string  `json:"voice"`          // voice name, e.g. en-US-Neural2-F
+	SpeakingRate  float64 `json:"speaking_rate"`  // 0.25-4.0 (default: 1.0)
+	Pitch         float64 `json:"pitch"`          // semitones, -20.0-20.0
+	AudioEncoding string  `json:"audio_encoding"` // MP3, LINEAR16 or OGG_OPUS (default: MP3)
+	Endpoint      string  `json:"endpoint"`       // override https://texttospeech.googleapis.com
+}
+
+// googleServiceAccount is the subset of a service account key file we need
+type googleServiceAccount struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// Google handles Google Cloud Text-to-Speech requests
+type Google struct {
+	name       string
+	cfg        GoogleConfig
+	account    googleServiceAccount
+	key        *rsa.PrivateKey
+	httpClient *http.Client
+	now        func() time.Time
+
+	tokenMu     sync.Mutex
+	token       string
+	tokenExpiry time.Time
+
+	voices voiceCache[Voice]
+}
+
+// googleEncodings maps supported audio encodings to their playback format
+var googleEncodings = map[string]Format{
+	"MP3":      FormatMP3,
+	"LINEAR16": FormatWAV, // LINEAR16 responses include a WAV header
+	"OGG_OPUS": "ogg",
+}
+
+// NewGoogle creates a Google Cloud TTS client from a service account key
+func NewGoogle(cfg GoogleConfig) (*Google, error) {
+	if cfg.Credentials == "" {
+		cfg.Credentials = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	if cfg.Credentials == "" {
+		return nil, fmt.Errorf("google: credentials file is required (set credentials or GOOGLE_APPLICATION_CREDENTIALS)")
+	}
+	if cfg.LanguageCode == "" {
+		cfg.LanguageCode = "en-US"
+	}
+	if cfg.SpeakingRate == 0 {
+		cfg.SpeakingRate = 1.0
+	}
+	if cfg.SpeakingRate < 0.25 || cfg.SpeakingRate > 4 {
+		return nil, fmt.Errorf("google: speaking_rate must be between 0.25 and 4.0, got %g", cfg.SpeakingRate)
+	}
+	if cfg.Pitch < -20 || cfg.Pitch > 20 {
+		return nil, fmt.Errorf("google: pitch must be between -20 and 20, got %g", cfg.Pitch)
+	}
+	if cfg.AudioEncoding == "" {
+		cfg.AudioEncoding = "MP3"
+	}
+	if _, ok := googleEncodings[cfg.AudioEncoding]; !ok {
+		return nil, fmt.Errorf("google: unsupported audio_encoding %q (use MP3, LINEAR16 or OGG_OPUS)", cfg.AudioEncoding)
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = googleTTSEndpoint
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+
+	account, key, err := loadGoogleServiceAccount(config.ExpandPath(cfg.Credentials))
+	if err != nil {
+		return nil, fmt.Errorf("google: %w", err)
+	}
+
+	return &Google{
+		name:    "google",
+		cfg:     cfg,
+		account: account,
+		key:     key,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// newGoogleProvider is the registry factory for the "google" provider type
+func newGoogleProvider(pc config.ProviderConfig) (Synthesizer, error) {
+	var cfg GoogleConfig
+	if err := pc.Decode(&cfg); err != nil {
+		return nil, err
+	}
+	g, err := NewGoogle(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if pc.Name != "" {
+		g.name = pc.Name
+	}
+	return g, nil
+}
+
+// loadGoogleServiceAccount reads a service account key file and its RSA key
+func loadGoogleServiceAccount(path string) (googleServiceAccount, *rsa.PrivateKey, error) {
+	var account googleServiceAccount
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return account, nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+	if err := json.Unmarshal(data, &account); err != nil {
+		return account, nil, fmt.Errorf("failed to parse credentials: %w", err)
+	}
+	if account.Type != "service_account" || account.ClientEmail == "" {
+		return account, nil, fmt.Errorf("credentials are not a service account key")
+	}
+	if account.TokenURI == "" {
+		account.TokenURI = googleTokenURL
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return account, nil, fmt.Errorf("service account private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if key, err1 := x509.ParsePKCS1PrivateKey(block.Bytes); err1 == nil {
+			return account, key, nil
+		}
+		return account, nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return account, nil, fmt.Errorf("service account private key is not RSA")
+	}
+	return account, key, nil
+}
+
+// signedJWT builds the RS256 assertion exchanged for an access token
+func (g *Google) signedJWT(now time.Time) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	if g.account.PrivateKeyID != "" {
+		header["kid"] = g.account.PrivateKeyID
+	}
+	claims := map[string]interface{}{
+		"iss":   g.account.ClientEmail,
+		"scope": googleScope,
+		"aud":   g.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	signingInput := enc.EncodeToString(headerJSON) + "." + enc.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, g.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return signingInput + "." + enc.EncodeToString(sig), nil
+}
+
+// accessToken returns a cached OAuth token, exchanging a new JWT when the
+// current one is missing or about to expire
+func (g *Google) accessToken(ctx context.Context) (string, error) {
+	g.tokenMu.Lock()
+	defer g.tokenMu.Unlock()
+
+	now := g.now()
+	if g.token != "" && now.Add(time.Minute).Before(g.tokenExpiry) {
+		return g.token, nil
+	}
+
+	assertion, err := g.signedJWT(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", g.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		apiErr := newAPIError(resp, "token exchange failed (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		// A revoked or deleted key is refused as an invalid grant
+		if resp.StatusCode == http.StatusBadRequest && strings.Contains(string(body), "invalid_grant") {
+			apiErr.Kind = ErrUnauthorized
+		}
+		return "", apiErr
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("invalid token response: %s", strings.TrimSpace(string(body)))
+	}
+
+	g.token = token.AccessToken
+	g.tokenExpiry = now.Add(time.Duration(token.ExpiresIn) * time.Second)
+	return g.token, nil
+}
+
+// do sends an authenticated request to the Text-to-Speech API
+func (g *Google) do(ctx context.Context, method, path string, payload []byte) (*http.Response, error) {
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.cfg.Endpoint+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// Name returns the provider name
+func (g *Google) Name() string {
+	return g.name
+}
+
+// MaxTextLength returns the characters that always fit Google's 5000-byte
+// input limit, even when every character takes three bytes in UTF-8
+func (g *Google) MaxTextLength() int {
+	return 1666
+}
+
+// Voices returns the voice names for the configured language, default
+// voice first. It returns nil if the voice list cannot be fetched.
+func (g *Google) Voices() []Voice {
+	return g.voices.get(g.fetchVoices)
+}
+
+// fetchVoices requests the voices of the configured language
+func (g *Google) fetchVoices(ctx context.Context) ([]Voice, error) {
+	resp, err := g.do(ctx, "GET", "/v1/voices?languageCode="+url.QueryEscape(g.cfg.LanguageCode), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google: voice list returned status %d", resp.StatusCode)
+	}
+
+	var list struct {
+		Voices []struct {
+			Name string `json:"name"`
+		} `json:"voices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("google: failed to decode voices: %w", err)
+	}
+	if len(list.Voices) == 0 {
+		// Keep accepting any voice rather than none
+		return nil, nil
+	}
+
+	voices := make([]Voice, 0, len(list.Voices)+1)
+	if g.cfg.Voice != "" {
+		voices = append(voices, Voice(g.cfg.Voice))
+	}
+	for _, v := range list.Voices {
+		if v.Name != g.cfg.Voice {
+			voices = append(voices, Voice(v.Name))
+		}
+	}
+	return voices, nil
+}
+
+// googleSynthesizeRequest is the text:synthesize request payload
+type googleSynthesizeRequest struct {
+	Input struct {
+		Text string `json:"text,omitempty"`
+		SSML string `json:"ssml,omitempty"`
+	} `json:"input"`
+	Voice struct {
+		LanguageCode string `json:"languageCode"`
+		Name         string `json:"name,omitempty"`
+	} `json:"voice"`
+	AudioConfig struct {
+		AudioEncoding string  `json:"audioEncoding"`
+		SpeakingRate  float64 `json:"speakingRate,omitempty"`
+		Pitch         float64 `json:"pitch,omitempty"`
+	} `json:"audioConfig"`
+}
+
+// Synthesize converts text or SSML to speech and decodes the base64
+// audioContent into playable audio
+func (g *Google) Synthesize(ctx context.Context, r Request) (*Audio, error) {
+	var reqBody googleSynthesizeRequest
+	if IsSSML(r.Text) {
+		reqBody.Input.SSML = r.Text
+	} else {
+		reqBody.Input.Text = r.Text
+	}
+
+	reqBody.Voice.LanguageCode = g.cfg.LanguageCode
+	reqBody.Voice.Name = g.cfg.Voice
+	if r.Voice != "" && !containsVoice(OpenAIVoices(), r.Voice) {
+		reqBody.Voice.Name = string(r.Voice)
+		// Voice names start with their language code, e.g. fr-FR-Neural2-A
+		if strings.Count(string(r.Voice), "-") >= 2 {
+			reqBody.Voice.LanguageCode = voiceLanguage(string(r.Voice))
+		}
+	}
+	reqBody.AudioConfig.AudioEncoding = g.cfg.AudioEncoding
+	reqBody.AudioConfig.SpeakingRate = g.cfg.SpeakingRate
+	if r.Speed > 0 {
+		reqBody.AudioConfig.SpeakingRate = r.Speed
+	}
+	reqBody.AudioConfig.Pitch = g.cfg.Pitch
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := g.do(ctx, "POST", "/v1/text:synthesize", jsonData)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+				Status  string `json:"status"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Error.Message != "" {
+			return nil, newAPIError(resp, "Google TTS API error (status %d, %s): %s", resp.StatusCode, apiErr.Error.Status, apiErr.Error.Message)
+		}
+		return nil, newAPIError(resp, "Google TTS API error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		AudioContent string `json:"audioContent"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	audioData, err := base64.StdEncoding.DecodeString(result.AudioContent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode audioContent: %w", err)
+	}
+	return &Audio{Data: audioData, Format: googleEncodings[g.cfg.AudioEncoding]}, nil
+}
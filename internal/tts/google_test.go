@@ -0,0 +1,274 @@
+package tts
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// googleTestEnv is a stand-in for the OAuth token endpoint and TTS API
+type googleTestEnv struct {
+	server      *httptest.Server
+	key         *rsa.PrivateKey
+	credentials string
+	tokenCalls  atomic.Int32
+	lastRequest googleSynthesizeRequest
+	status      int
+	response    string
+
+	tokenStatus   int // if set, the token endpoint fails with it
+	tokenResponse string
+}
+
+func newGoogleTestEnv(t *testing.T) *googleTestEnv {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &googleTestEnv{key: key, status: http.StatusOK}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		env.tokenCalls.Add(1)
+		if env.tokenStatus != 0 {
+			w.WriteHeader(env.tokenStatus)
+			_, _ = w.Write([]byte(env.tokenResponse))
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			t.Errorf("unexpected grant_type %q", r.Form.Get("grant_type"))
+		}
+		env.verifyJWT(t, r.Form.Get("assertion"))
+		_, _ = w.Write([]byte(`{"access_token": "ya29.test", "expires_in": 3600, "token_type": "Bearer"}`))
+	})
+	mux.HandleFunc("/v1/text:synthesize", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ya29.test" {
+			t.Errorf("expected bearer token, got %q", r.Header.Get("Authorization"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &env.lastRequest)
+		w.WriteHeader(env.status)
+		_, _ = w.Write([]byte(env.response))
+	})
+	env.server = httptest.NewServer(mux)
+	t.Cleanup(env.server.Close)
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := map[string]string{
+		"type":           "service_account",
+		"client_email":   "tts@project.iam.gserviceaccount.com",
+		"private_key_id": "kid-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":      env.server.URL + "/token",
+	}
+	data, _ := json.Marshal(account)
+	env.credentials = filepath.Join(t.TempDir(), "sa.json")
+	if err := os.WriteFile(env.credentials, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+// verifyJWT checks the RS256 signature and claims of the assertion
+func (env *googleTestEnv) verifyJWT(t *testing.T, jwt string) {
+	t.Helper()
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("malformed JWT %q", jwt)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&env.key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+		t.Errorf("invalid JWT signature: %v", err)
+	}
+
+	claimsJSON, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims map[string]interface{}
+	_ = json.Unmarshal(claimsJSON, &claims)
+	if claims["iss"] != "tts@project.iam.gserviceaccount.com" || claims["scope"] != googleScope {
+		t.Errorf("unexpected claims %v", claims)
+	}
+	if claims["aud"] != env.server.URL+"/token" {
+		t.Errorf("expected aud to be the token URI, got %v", claims["aud"])
+	}
+}
+
+func (env *googleTestEnv) client(t *testing.T, cfg GoogleConfig) *Google {
+	t.Helper()
+	cfg.Credentials = env.credentials
+	cfg.Endpoint = env.server.URL
+	g, err := NewGoogle(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestNewGoogle_Validation(t *testing.T) {
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	if _, err := NewGoogle(GoogleConfig{}); err == nil {
+		t.Error("expected error without credentials")
+	}
+
+	env := newGoogleTestEnv(t)
+	tests := []struct {
+		name string
+		cfg  GoogleConfig
+	}{
+		{"speaking rate", GoogleConfig{SpeakingRate: 5}},
+		{"pitch", GoogleConfig{Pitch: -30}},
+		{"encoding", GoogleConfig{AudioEncoding: "FLAC"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Credentials = env.credentials
+			if _, err := NewGoogle(tt.cfg); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+
+	notSA := filepath.Join(t.TempDir(), "user.json")
+	_ = os.WriteFile(notSA, []byte(`{"type": "authorized_user"}`), 0600)
+	if _, err := NewGoogle(GoogleConfig{Credentials: notSA}); err == nil {
+		t.Error("expected error for non service account credentials")
+	}
+}
+
+func TestGoogle_Synthesize(t *testing.T) {
+	env := newGoogleTestEnv(t)
+	env.response = `{"audioContent": "` + base64.StdEncoding.EncodeToString([]byte("google-mp3")) + `"}`
+	g := env.client(t, GoogleConfig{Voice: "en-US-Neural2-F", SpeakingRate: 1.25, Pitch: -2})
+
+	audio, err := g.Synthesize(context.Background(), Request{Text: "Hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(audio.Data) != "google-mp3" || audio.Format != FormatMP3 {
+		t.Errorf("unexpected audio %s %q", audio.Format, audio.Data)
+	}
+
+	req := env.lastRequest
+	if req.Input.Text != "Hello" || req.Input.SSML != "" {
+		t.Errorf("unexpected input %+v", req.Input)
+	}
+	if req.Voice.LanguageCode != "en-US" || req.Voice.Name != "en-US-Neural2-F" {
+		t.Errorf("unexpected voice %+v", req.Voice)
+	}
+	if req.AudioConfig.AudioEncoding != "MP3" || req.AudioConfig.SpeakingRate != 1.25 || req.AudioConfig.Pitch != -2 {
+		t.Errorf("unexpected audio config %+v", req.AudioConfig)
+	}
+
+	// A second call reuses the cached token
+	if _, err := g.Synthesize(context.Background(), Request{Text: "<speak>Again</speak>", Voice: "fr-FR-Neural2-A"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if env.tokenCalls.Load() != 1 {
+		t.Errorf("expected 1 token exchange, got %d", env.tokenCalls.Load())
+	}
+	if env.lastRequest.Input.SSML != "<speak>Again</speak>" {
+		t.Error("expected SSML input")
+	}
+	if env.lastRequest.Voice.LanguageCode != "fr-FR" || env.lastRequest.Voice.Name != "fr-FR-Neural2-A" {
+		t.Errorf("expected language from voice name, got %+v", env.lastRequest.Voice)
+	}
+}
+
+func TestGoogle_TokenRefresh(t *testing.T) {
+	env := newGoogleTestEnv(t)
+	env.response = `{"audioContent": ""}`
+	g := env.client(t, GoogleConfig{})
+
+	now := time.Now()
+	g.now = func() time.Time { return now }
+	if _, err := g.Synthesize(context.Background(), Request{Text: "a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tokens are refreshed shortly before they expire
+	now = now.Add(59*time.Minute + 30*time.Second)
+	if _, err := g.Synthesize(context.Background(), Request{Text: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if env.tokenCalls.Load() != 2 {
+		t.Errorf("expected token refresh, got %d exchanges", env.tokenCalls.Load())
+	}
+}
+
+func TestGoogle_LinearEncoding(t *testing.T) {
+	env := newGoogleTestEnv(t)
+	env.response = `{"audioContent": "` + base64.StdEncoding.EncodeToString([]byte("RIFF....")) + `"}`
+	g := env.client(t, GoogleConfig{AudioEncoding: "LINEAR16"})
+
+	audio, err := g.Synthesize(context.Background(), Request{Text: "Hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audio.Format != FormatWAV {
+		t.Errorf("expected wav for LINEAR16, got %s", audio.Format)
+	}
+}
+
+func TestGoogle_SynthesizeError(t *testing.T) {
+	env := newGoogleTestEnv(t)
+	env.status = http.StatusBadRequest
+	env.response = `{"error": {"code": 400, "message": "Invalid voice name", "status": "INVALID_ARGUMENT"}}`
+	g := env.client(t, GoogleConfig{})
+
+	_, err := g.Synthesize(context.Background(), Request{Text: "Hello"})
+	if err == nil || !strings.Contains(err.Error(), "INVALID_ARGUMENT") || !strings.Contains(err.Error(), "Invalid voice name") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGoogle_TokenExchangeError(t *testing.T) {
+	tests := []struct {
+		status    int
+		body      string
+		kind      error
+		retryable bool
+	}{
+		{http.StatusUnauthorized, `{"error": "unauthorized_client"}`, ErrUnauthorized, false},
+		{http.StatusForbidden, `{"error": "access_denied"}`, ErrUnauthorized, false},
+		{http.StatusBadRequest, `{"error": "invalid_grant", "error_description": "Invalid JWT Signature."}`, ErrUnauthorized, false},
+		{http.StatusServiceUnavailable, `backend error`, ErrServerError, true},
+	}
+	for _, tt := range tests {
+		env := newGoogleTestEnv(t)
+		env.tokenStatus = tt.status
+		env.tokenResponse = tt.body
+		g := env.client(t, GoogleConfig{})
+
+		_, err := g.Synthesize(context.Background(), Request{Text: "Hello"})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || !errors.Is(err, tt.kind) || IsRetryable(err) != tt.retryable {
+			t.Errorf("status %d: expected a %v API error, got %v", tt.status, tt.kind, err)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package tts
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// Rate limit defaults, per provider
+const (
+	DefaultRequestsPerMinute = 60
+	DefaultCharsPerMinute    = 100000
+)
+
+// tokenBucket allows a burst of up to a minute's worth of tokens and
+// refills at the per-minute rate
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64 // tokens per second
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+}
+
+// newTokenBucket returns a full bucket refilling perMinute tokens a minute
+func newTokenBucket(perMinute int, now func() time.Time) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(perMinute),
+		rate:     float64(perMinute) / 60,
+		tokens:   float64(perMinute),
+		last:     now(),
+		now:      now,
+	}
+}
+
+// reserve takes n tokens and returns how long to wait before using them.
+// The bucket may go into debt, so callers are served in the order they
+// reserve. Requests larger than the bucket wait for a full bucket.
+func (b *tokenBucket) reserve(n float64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	b.tokens -= math.Min(n, b.capacity)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// limitedSynthesizer rate limits a provider and charges its requests to
+// the budget
+type limitedSynthesizer struct {
+	wrapper
+	requests *tokenBucket
+	chars    *tokenBucket
+	budget   *Budget
+}
+
+// WithLimits puts a requests-per-minute and characters-per-minute limiter
+// in front of each provider of s, and checks every request against budget
+// when it is not nil. cfg.RateLimit applies to every provider unless the
+// provider's own settings set requests_per_minute or chars_per_minute.
+func WithLimits(s Synthesizer, cfg *config.Config, budget *Budget) (Synthesizer, error) {
+	var err error
+	s = wrapProviders(s, func(p Synthesizer) Synthesizer {
+		limits := cfg.RateLimit
+		if decodeErr := cfg.Lookup(p.Name()).Decode(&limits); decodeErr != nil && err == nil {
+			err = decodeErr
+		}
+		if limits.RequestsPerMinute <= 0 {
+			limits.RequestsPerMinute = DefaultRequestsPerMinute
+		}
+		if limits.CharsPerMinute <= 0 {
+			limits.CharsPerMinute = DefaultCharsPerMinute
+		}
+		return &limitedSynthesizer{
+			wrapper:  wrapper{p},
+			requests: newTokenBucket(limits.RequestsPerMinute, time.Now),
+			chars:    newTokenBucket(limits.CharsPerMinute, time.Now),
+			budget:   budget,
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Synthesize waits for the rate limits and calls the provider if the
+// budget allows it
+func (ls *limitedSynthesizer) Synthesize(ctx context.Context, req Request) (*Audio, error) {
+	chars, usd, err := ls.admit(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	audio, err := ls.synth.Synthesize(ctx, req)
+	if err != nil {
+		ls.refund(chars, usd)
+		return nil, err
+	}
+	return audio, nil
+}
+
+// Stream is Synthesize for streams; the request is refunded unless the
+// provider accepts it
+func (ls *limitedSynthesizer) Stream(ctx context.Context, req Request) (*AudioStream, error) {
+	chars, usd, err := ls.admit(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := Stream(ctx, ls.synth, req)
+	if err != nil {
+		ls.refund(chars, usd)
+		return nil, err
+	}
+	return stream, nil
+}
+
+// admit reserves the request's cost in the budget, then waits until both
+// rate limits allow the request. It returns the request's size and
+// estimated cost.
+func (ls *limitedSynthesizer) admit(ctx context.Context, req Request) (int, float64, error) {
+	chars := utf8.RuneCountInString(req.Text)
+	var usd float64
+	if ls.budget != nil {
+		usd = ls.budget.Cost(ls.synth, chars)
+		if err := ls.budget.Reserve(chars, usd); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	wait := ls.requests.reserve(1)
+	if d := ls.chars.reserve(float64(chars)); d > wait {
+		wait = d
+	}
+	if wait > 0 {
+		if err := sleepContext(ctx, wait); err != nil {
+			ls.refund(chars, usd)
+			return 0, 0, err
+		}
+	}
+	return chars, usd, nil
+}
+
+// refund gives back the reservation of a request the provider did not
+// accept
+func (ls *limitedSynthesizer) refund(chars int, usd float64) {
+	if ls.budget != nil {
+		ls.budget.Refund(chars, usd)
+	}
+}
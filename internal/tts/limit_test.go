@@ -0,0 +1,98 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(60, func() time.Time { return now })
+
+	for i := 0; i < 60; i++ {
+		if d := b.reserve(1); d != 0 {
+			t.Fatalf("request %d: expected the burst to pass, waited %v", i, d)
+		}
+	}
+	if d := b.reserve(1); d != time.Second {
+		t.Errorf("expected a 1s wait once the bucket is empty, got %v", d)
+	}
+	if d := b.reserve(1); d != 2*time.Second {
+		t.Errorf("expected waiters to queue, got %v", d)
+	}
+
+	now = now.Add(time.Minute)
+	if d := b.reserve(1); d != 0 {
+		t.Errorf("expected the bucket to refill, waited %v", d)
+	}
+	// Requests larger than the bucket take at most a full bucket
+	now = now.Add(time.Minute)
+	if d := b.reserve(500); d != 0 {
+		t.Errorf("expected a full bucket to admit a large request, waited %v", d)
+	}
+}
+
+func TestWithLimits_WaitsForRateLimit(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimit.RequestsPerMinute = 1
+	s, err := WithLimits(&stubSynthesizer{name: "stub"}, cfg, nil)
+	if err != nil {
+		t.Fatalf("WithLimits: %v", err)
+	}
+
+	if _, err := s.Synthesize(context.Background(), Request{Text: "one"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.Synthesize(ctx, Request{Text: "two"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the second request to wait for the limit, got %v", err)
+	}
+}
+
+func TestWithLimits_ProviderOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tts.json")
+	data := `{
+		"rate_limit": {"requests_per_minute": 10, "chars_per_minute": 500},
+		"providers": {"stub": {"type": "stub", "chars_per_minute": 50}}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := WithLimits(&stubSynthesizer{name: "stub", voices: OpenAIVoices()}, cfg, nil)
+	if err != nil {
+		t.Fatalf("WithLimits: %v", err)
+	}
+
+	ls := s.(*limitedSynthesizer)
+	if ls.requests.capacity != 10 || ls.chars.capacity != 50 {
+		t.Errorf("expected 10 requests and 50 chars per minute, got %v and %v", ls.requests.capacity, ls.chars.capacity)
+	}
+	if s.Name() != "stub" || len(s.(VoiceLister).Voices()) != len(OpenAIVoices()) {
+		t.Error("expected the limiter to keep the provider's name and voices")
+	}
+}
+
+func TestWithLimits_Defaults(t *testing.T) {
+	s, err := WithLimits(NewClient(), config.Default(), nil)
+	if err != nil {
+		t.Fatalf("WithLimits: %v", err)
+	}
+	ls := s.(*limitedSynthesizer)
+	if ls.requests.capacity != DefaultRequestsPerMinute || ls.chars.capacity != DefaultCharsPerMinute {
+		t.Errorf("unexpected default limits %v and %v", ls.requests.capacity, ls.chars.capacity)
+	}
+	if MaxTextLength(s) != openAIMaxInput || providerModel(s) != "tts-1" {
+		t.Error("expected the limiter to keep the provider's limit and model")
+	}
+}
@@ -2,14 +2,23 @@ package tts
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
 	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
 )
 
+func init() {
+	Register("openai", newOpenAIProvider)
+}
+
 // Voice represents available OpenAI TTS voices
 type Voice string
 
@@ -22,52 +31,283 @@ const (
 	VoiceShimmer Voice = "shimmer"
 )
 
-// ValidVoices returns all valid voice options
-func ValidVoices() []Voice {
+// OpenAIVoices returns the voices supported by the OpenAI API
+func OpenAIVoices() []Voice {
 	return []Voice{VoiceAlloy, VoiceEcho, VoiceFable, VoiceOnyx, VoiceNova, VoiceShimmer}
 }
 
+// ValidVoices returns all valid voice options: the OpenAI voices
+// followed by the espeak-ng voices
+func ValidVoices() []Voice {
+	return append(OpenAIVoices(), EspeakVoices()...)
+}
+
 // IsValidVoice checks if the given voice is valid
 func IsValidVoice(v string) bool {
-	for _, valid := range ValidVoices() {
-		if string(valid) == v {
+	return containsVoice(ValidVoices(), Voice(v))
+}
+
+// containsVoice reports whether voices contains v
+func containsVoice(voices []Voice, v Voice) bool {
+	for _, valid := range voices {
+		if valid == v {
 			return true
 		}
 	}
 	return false
 }
 
+// OpenAI TTS models
+const (
+	ModelTTS1         = "tts-1"
+	ModelTTS1HD       = "tts-1-hd"
+	ModelGPT4oMiniTTS = "gpt-4o-mini-tts"
+)
+
+// openAIBaseURL is the public OpenAI API root
+const openAIBaseURL = "https://api.openai.com/v1"
+
+// OpenAIModels returns the speech models offered by the OpenAI API
+func OpenAIModels() []string {
+	return []string{ModelTTS1, ModelTTS1HD, ModelGPT4oMiniTTS}
+}
+
+// OpenAIConfig holds the settings of the OpenAI provider. Pointing BaseURL
+// at an OpenAI-compatible server (Kokoro-FastAPI, openedai-speech, LocalAI)
+// or an Azure OpenAI deployment reuses the same client.
+type OpenAIConfig struct {
+	BaseURL    string            `json:"base_url"`    // API root (default: OPENAI_BASE_URL or https://api.openai.com/v1)
+	Model      string            `json:"model"`       // tts-1, tts-1-hd or gpt-4o-mini-tts (default: tts-1)
+	APIKeyEnv  string            `json:"api_key_env"` // variable holding the key (default: OPENAI_API_KEY)
+	APIVersion string            `json:"api_version"` // Azure OpenAI api-version; sends the key as api-key
+	Headers    map[string]string `json:"headers"`     // extra request headers
+	Voices     []string          `json:"voices"`      // voices of a compatible server, default first
+}
+
 // Client handles OpenAI TTS API requests
 type Client struct {
+	name       string
 	apiKey     string
 	httpClient *http.Client
 	model      string
+	baseURL    string
+	apiVersion string
+	headers    map[string]string
+	voices     []Voice
 }
 
-// NewClient creates a new TTS client
+// NewClient creates a TTS client for the public OpenAI API
 func NewClient() *Client {
 	return &Client{
+		name:   "openai",
 		apiKey: os.Getenv("OPENAI_API_KEY"),
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		model: "tts-1",
+		model:   ModelTTS1,
+		baseURL: openAIBaseURL,
+	}
+}
+
+// NewOpenAI creates a client for the OpenAI API or a compatible server.
+// An API key is only required when talking to OpenAI or Azure OpenAI;
+// self-hosted servers usually accept unauthenticated requests.
+func NewOpenAI(cfg OpenAIConfig) (*Client, error) {
+	if cfg.APIKeyEnv == "" {
+		cfg.APIKeyEnv = "OPENAI_API_KEY"
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = os.Getenv("OPENAI_BASE_URL")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = openAIBaseURL
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.Model == "" {
+		cfg.Model = ModelTTS1
+	}
+
+	official := cfg.BaseURL == openAIBaseURL
+	// Compatible servers name their models freely (e.g. "kokoro")
+	if official && !containsString(OpenAIModels(), cfg.Model) {
+		return nil, fmt.Errorf("openai: unknown model %q (available: %v)", cfg.Model, OpenAIModels())
+	}
+
+	apiKey := os.Getenv(cfg.APIKeyEnv)
+	if apiKey == "" && (official || cfg.APIVersion != "") {
+		return nil, fmt.Errorf("%s environment variable is required", cfg.APIKeyEnv)
+	}
+
+	var voices []Voice
+	for _, v := range cfg.Voices {
+		voices = append(voices, Voice(v))
+	}
+
+	c := NewClient()
+	c.apiKey = apiKey
+	c.model = cfg.Model
+	c.baseURL = cfg.BaseURL
+	c.apiVersion = cfg.APIVersion
+	c.headers = cfg.Headers
+	c.voices = voices
+	return c, nil
+}
+
+// newOpenAIProvider is the registry factory for the "openai" provider type
+func newOpenAIProvider(pc config.ProviderConfig) (Synthesizer, error) {
+	var cfg OpenAIConfig
+	if err := pc.Decode(&cfg); err != nil {
+		return nil, err
+	}
+	c, err := NewOpenAI(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if pc.Name != "" {
+		c.name = pc.Name
+	}
+	return c, nil
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Name returns the provider name
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Model returns the speech model sent with each request
+func (c *Client) Model() string {
+	return c.model
+}
+
+// openAIMaxInput is the longest input the speech endpoint accepts
+const openAIMaxInput = 4096
+
+// MaxTextLength returns the speech endpoint's input limit
+func (c *Client) MaxTextLength() int {
+	return openAIMaxInput
+}
+
+// Voices returns the configured voices of a compatible server, or the
+// voices supported by the OpenAI API
+func (c *Client) Voices() []Voice {
+	if len(c.voices) > 0 {
+		return c.voices
 	}
+	return OpenAIVoices()
 }
 
 // ttsRequest represents the API request payload
 type ttsRequest struct {
-	Model string `json:"model"`
-	Input string `json:"input"`
-	Voice string `json:"voice"`
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	Speed          float64 `json:"speed,omitempty"`
+	ResponseFormat string  `json:"response_format,omitempty"`
+	Instructions   string  `json:"instructions,omitempty"`
+}
+
+// openAIPCMSampleRate is the rate of response_format "pcm" (16-bit mono)
+const openAIPCMSampleRate = 24000
+
+// supportsInstructions reports whether the model accepts instructions;
+// tts-1 and tts-1-hd reject them
+func (c *Client) supportsInstructions() bool {
+	return c.model != ModelTTS1 && c.model != ModelTTS1HD
+}
+
+// endpoint returns the speech URL, with api-version for Azure OpenAI
+func (c *Client) endpoint() string {
+	u := c.baseURL + "/audio/speech"
+	if c.apiVersion != "" {
+		u += "?api-version=" + url.QueryEscape(c.apiVersion)
+	}
+	return u
+}
+
+// Synthesize converts text to speech and returns audio in the requested
+// format (MP3 by default). PCM responses are wrapped as WAV.
+func (c *Client) Synthesize(ctx context.Context, r Request) (*Audio, error) {
+	format := r.Format
+	if format == "" {
+		format = FormatMP3
+	}
+
+	resp, err := c.post(ctx, c.httpClient, r, format)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	audioData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if format == FormatPCM {
+		return &Audio{Data: pcmToWAV(audioData, openAIPCMSampleRate, 1, 16), Format: FormatWAV}, nil
+	}
+	return &Audio{Data: audioData, Format: format}, nil
+}
+
+// Stream returns the audio as the API sends it, in the requested format
+// (raw PCM by default, which needs no decoding before playback). The
+// client timeout applies until the response headers arrive; after that
+// only ctx bounds the download, which can last as long as playback.
+func (c *Client) Stream(ctx context.Context, r Request) (*AudioStream, error) {
+	format := r.Format
+	if format == "" {
+		format = FormatPCM
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	hc := *c.httpClient
+	hc.Timeout = 0
+	var timer *time.Timer
+	if c.httpClient.Timeout > 0 {
+		timer = time.AfterFunc(c.httpClient.Timeout, cancel)
+	}
+
+	resp, err := c.post(ctx, &hc, r, format)
+	if timer != nil && !timer.Stop() {
+		// Same error as a client timeout, so the request is retried
+		err = fmt.Errorf("API request failed: %w", &url.Error{Op: "Post", URL: c.endpoint(), Err: context.DeadlineExceeded})
+		if resp != nil {
+			resp.Body.Close()
+		}
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	stream := &AudioStream{Body: &cancelBody{resp.Body, cancel}, Format: format}
+	if format == FormatPCM {
+		stream.SampleRate = openAIPCMSampleRate
+	}
+	return stream, nil
 }
 
-// Synthesize converts text to speech and returns MP3 audio data
-func (c *Client) Synthesize(text string, voice Voice) ([]byte, error) {
+// post sends the speech request and returns the successful response
+func (c *Client) post(ctx context.Context, hc *http.Client, r Request, format Format) (*http.Response, error) {
 	reqBody := ttsRequest{
-		Model: c.model,
-		Input: text,
-		Voice: string(voice),
+		Model:          c.model,
+		Input:          r.Text,
+		Voice:          string(r.Voice),
+		Speed:          r.Speed,
+		ResponseFormat: string(format),
+	}
+	if c.supportsInstructions() {
+		reqBody.Instructions = r.Instructions
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -75,29 +315,69 @@ func (c *Client) Synthesize(text string, voice Voice) ([]byte, error) {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", "https://api.openai.com/v1/audio/speech", bytes.NewReader(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(), bytes.NewReader(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	switch {
+	case c.apiKey == "":
+	case c.apiVersion != "":
+		req.Header.Set("api-key", c.apiKey)
+	default:
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
 	req.Header.Set("Content-Type", "application/json")
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := hc.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("API request failed: %w", err)
 	}
-	defer resp.Body.Close()
-
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		defer resp.Body.Close()
+		return nil, parseOpenAIError(resp)
 	}
+	return resp, nil
+}
 
-	audioData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+// parseOpenAIError builds a classified error from an OpenAI error body:
+// {"error": {"message": ..., "type": ..., "code": ...}}.
+// Compatible servers that return plain text keep the raw body as message.
+func parseOpenAIError(resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+
+	var parsed struct {
+		Error struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			Code    string `json:"code"`
+		} `json:"error"`
 	}
+	if json.Unmarshal(body, &parsed) != nil || parsed.Error.Message == "" {
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+		return newAPIError(resp, "API error (status %d): %s", resp.StatusCode, msg)
+	}
+
+	code := parsed.Error.Code
+	if code == "" {
+		code = parsed.Error.Type
+	}
+	apiErr := newAPIError(resp, "API error (status %d, %s): %s", resp.StatusCode, code, parsed.Error.Message)
+	if code == "" {
+		apiErr.Message = fmt.Sprintf("API error (status %d): %s", resp.StatusCode, parsed.Error.Message)
+	}
+	apiErr.Code = code
 
-	return audioData, nil
+	// A 429 is either a rate limit (wait and retry) or an exhausted
+	// quota (retrying only burns more requests)
+	if code == "insufficient_quota" || code == "billing_hard_limit_reached" {
+		apiErr.Kind = ErrQuotaExceeded
+	}
+	return apiErr
 }
@@ -1,17 +1,21 @@
 package tts
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestValidVoices(t *testing.T) {
 	voices := ValidVoices()
 
-	expected := []Voice{VoiceAlloy, VoiceEcho, VoiceFable, VoiceOnyx, VoiceNova, VoiceShimmer}
+	// OpenAI voices come first, followed by the espeak-ng voices
+	expected := append([]Voice{VoiceAlloy, VoiceEcho, VoiceFable, VoiceOnyx, VoiceNova, VoiceShimmer}, EspeakVoices()...)
 	if len(voices) != len(expected) {
 		t.Errorf("expected %d voices, got %d", len(expected), len(voices))
 	}
@@ -23,6 +27,17 @@ func TestValidVoices(t *testing.T) {
 	}
 }
 
+func TestOpenAIVoices(t *testing.T) {
+	voices := OpenAIVoices()
+
+	if len(voices) != 6 {
+		t.Errorf("expected 6 OpenAI voices, got %d", len(voices))
+	}
+	if NewClient().Voices()[0] != VoiceAlloy {
+		t.Error("expected alloy to be the default OpenAI voice")
+	}
+}
+
 func TestIsValidVoice(t *testing.T) {
 	tests := []struct {
 		voice    string
@@ -34,6 +49,8 @@ func TestIsValidVoice(t *testing.T) {
 		{"onyx", true},
 		{"nova", true},
 		{"shimmer", true},
+		{"en-us", true},
+		{"fr", true},
 		{"invalid", false},
 		{"", false},
 		{"ALLOY", false}, // case sensitive
@@ -108,21 +125,16 @@ func TestSynthesize_Success(t *testing.T) {
 		apiKey:     "test-api-key",
 		httpClient: server.Client(),
 		model:      "tts-1",
+		baseURL:    server.URL,
 	}
 
-	// Override the URL by creating a custom transport
-	originalURL := "https://api.openai.com/v1/audio/speech"
-	_ = originalURL // We'll use a mock server instead
-
-	// For this test, we need to create a client that uses our test server
-	// We'll test the request building logic separately
-	audio, err := synthesizeWithURL(client, "Hello, world!", VoiceNova, server.URL)
+	audio, err := client.Synthesize(context.Background(), Request{Text: "Hello, world!", Voice: VoiceNova})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if string(audio) != string(expectedAudio) {
-		t.Errorf("expected audio %q, got %q", expectedAudio, audio)
+	if string(audio.Data) != string(expectedAudio) {
+		t.Errorf("expected audio %q, got %q", expectedAudio, audio.Data)
 	}
 }
 
@@ -137,72 +149,241 @@ func TestSynthesize_APIError(t *testing.T) {
 		apiKey:     "invalid-key",
 		httpClient: server.Client(),
 		model:      "tts-1",
+		baseURL:    server.URL,
 	}
 
-	_, err := synthesizeWithURL(client, "Hello", VoiceAlloy, server.URL)
+	_, err := client.Synthesize(context.Background(), Request{Text: "Hello", Voice: VoiceAlloy})
 	if err == nil {
 		t.Error("expected error for API failure")
 	}
 }
 
-// synthesizeWithURL is a test helper that allows overriding the API URL
-func synthesizeWithURL(c *Client, text string, voice Voice, url string) ([]byte, error) {
-	reqBody := ttsRequest{
-		Model: c.model,
-		Input: text,
-		Voice: string(voice),
+func TestNewOpenAI_Defaults(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "test-key")
+	t.Setenv("OPENAI_BASE_URL", "")
+
+	client, err := NewOpenAI(OpenAIConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.baseURL != "https://api.openai.com/v1" {
+		t.Errorf("expected default base URL, got %q", client.baseURL)
+	}
+	if client.Model() != ModelTTS1 {
+		t.Errorf("expected model tts-1, got %q", client.Model())
+	}
+}
+
+func TestNewOpenAI_RequiresKeyForOpenAI(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("OPENAI_BASE_URL", "")
+
+	if _, err := NewOpenAI(OpenAIConfig{}); err == nil {
+		t.Error("expected error without OPENAI_API_KEY")
+	}
+	if _, err := NewOpenAI(OpenAIConfig{BaseURL: "http://localhost:8880/v1"}); err != nil {
+		t.Errorf("self-hosted server should not require a key: %v", err)
+	}
+	if _, err := NewOpenAI(OpenAIConfig{BaseURL: "https://res.openai.azure.com/openai/deployments/tts", APIVersion: "2025-03-01-preview"}); err == nil {
+		t.Error("expected error for Azure OpenAI without a key")
+	}
+}
+
+func TestNewOpenAI_Model(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "test-key")
+	t.Setenv("OPENAI_BASE_URL", "")
+
+	for _, model := range OpenAIModels() {
+		if _, err := NewOpenAI(OpenAIConfig{Model: model}); err != nil {
+			t.Errorf("model %q: unexpected error: %v", model, err)
+		}
+	}
+	if _, err := NewOpenAI(OpenAIConfig{Model: "tts-2"}); err == nil {
+		t.Error("expected error for unknown OpenAI model")
 	}
+	// Compatible servers use their own model names
+	if _, err := NewOpenAI(OpenAIConfig{BaseURL: "http://localhost:8880/v1", Model: "kokoro"}); err != nil {
+		t.Errorf("unexpected error for compatible server model: %v", err)
+	}
+}
+
+func TestOpenAI_CompatibleServer(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/audio/speech" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("expected no Authorization header, got %q", auth)
+		}
+		if r.Header.Get("X-Team") != "docs" {
+			t.Errorf("expected extra header, got %q", r.Header.Get("X-Team"))
+		}
+		var req ttsRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Model != "kokoro" || req.Voice != "af_bella" {
+			t.Errorf("unexpected request %+v", req)
+		}
+		_, _ = w.Write([]byte("audio"))
+	}))
+	defer server.Close()
 
-	jsonData, err := json.Marshal(reqBody)
+	client, err := NewOpenAI(OpenAIConfig{
+		BaseURL: server.URL + "/v1/",
+		Model:   "kokoro",
+		Headers: map[string]string{"X-Team": "docs"},
+		Voices:  []string{"af_bella", "am_adam"},
+	})
 	if err != nil {
-		return nil, err
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if DefaultVoice(client) != "af_bella" {
+		t.Errorf("expected configured default voice, got %q", DefaultVoice(client))
 	}
+	if SupportsVoice(client, VoiceNova) {
+		t.Error("expected configured voices to replace the OpenAI voices")
+	}
+	if _, err := client.Synthesize(context.Background(), Request{Text: "Hello", Voice: "af_bella"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestOpenAI_AzureDeployment(t *testing.T) {
+	t.Setenv("AZURE_OPENAI_API_KEY", "azure-key")
 
-	req, err := http.NewRequest("POST", url, io.NopCloser(
-		io.Reader(
-			&jsonReader{data: jsonData},
-		),
-	))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openai/deployments/tts-hd/audio/speech" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if v := r.URL.Query().Get("api-version"); v != "2025-03-01-preview" {
+			t.Errorf("expected api-version, got %q", v)
+		}
+		if r.Header.Get("api-key") != "azure-key" {
+			t.Errorf("expected api-key header, got %q", r.Header.Get("api-key"))
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("expected no bearer token for Azure OpenAI")
+		}
+		_, _ = w.Write([]byte("audio"))
+	}))
+	defer server.Close()
+
+	client, err := NewOpenAI(OpenAIConfig{
+		BaseURL:    server.URL + "/openai/deployments/tts-hd",
+		Model:      ModelTTS1HD,
+		APIKeyEnv:  "AZURE_OPENAI_API_KEY",
+		APIVersion: "2025-03-01-preview",
+	})
 	if err != nil {
-		return nil, err
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Synthesize(context.Background(), Request{Text: "Hello", Voice: VoiceNova}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
+}
+
+func TestOpenAI_SpeedFormatInstructions(t *testing.T) {
+	var got ttsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = ttsRequest{}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte{0, 0, 1, 0})
+	}))
+	defer server.Close()
 
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
-	req.Header.Set("Content-Type", "application/json")
+	client := &Client{httpClient: server.Client(), model: ModelGPT4oMiniTTS, baseURL: server.URL}
 
-	resp, err := c.httpClient.Do(req)
+	audio, err := client.Synthesize(context.Background(), Request{Text: "Deploy done", Voice: VoiceNova, Speed: 1.5, Format: FormatOpus, Instructions: "calm"})
 	if err != nil {
-		return nil, err
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Speed != 1.5 || got.ResponseFormat != "opus" || got.Instructions != "calm" {
+		t.Errorf("unexpected request %+v", got)
+	}
+	if audio.Format != FormatOpus {
+		t.Errorf("expected opus, got %s", audio.Format)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, &apiError{status: resp.StatusCode, body: string(body)}
+	// PCM is wrapped as WAV so players can handle it
+	audio, err = client.Synthesize(context.Background(), Request{Text: "x", Voice: VoiceNova, Format: FormatPCM})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if audio.Format != FormatWAV || string(audio.Data[:4]) != "RIFF" {
+		t.Errorf("expected WAV-wrapped PCM, got %s", audio.Format)
 	}
 
-	return io.ReadAll(resp.Body)
+	// tts-1 rejects instructions, so they are dropped
+	client.model = ModelTTS1
+	if _, err := client.Synthesize(context.Background(), Request{Text: "x", Voice: VoiceNova, Instructions: "urgent"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Instructions != "" || got.ResponseFormat != "mp3" {
+		t.Errorf("unexpected request for tts-1: %+v", got)
+	}
 }
 
-type jsonReader struct {
-	data []byte
-	pos  int
-}
+func TestOpenAI_Stream(t *testing.T) {
+	var got ttsRequest
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte("first"))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte("-rest"))
+	}))
+	defer server.Close()
+
+	hc := server.Client()
+	hc.Timeout = 50 * time.Millisecond // only applies until the headers arrive
+	client := &Client{httpClient: hc, model: ModelTTS1, baseURL: server.URL}
 
-func (r *jsonReader) Read(p []byte) (n int, err error) {
-	if r.pos >= len(r.data) {
-		return 0, io.EOF
+	stream, err := client.Stream(context.Background(), Request{Text: "Long answer", Voice: VoiceNova})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stream.Body.Close()
+	if got.ResponseFormat != "pcm" || stream.Format != FormatPCM || stream.SampleRate != openAIPCMSampleRate {
+		t.Errorf("expected raw PCM by default, got request %q, stream %s at %d Hz", got.ResponseFormat, stream.Format, stream.SampleRate)
 	}
-	n = copy(p, r.data[r.pos:])
-	r.pos += n
-	return n, nil
-}
 
-type apiError struct {
-	status int
-	body   string
+	// The first bytes are readable before the response is complete
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(stream.Body, buf); err != nil || string(buf) != "first" {
+		t.Fatalf("expected the first bytes early, got %q, %v", buf, err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	rest, err := io.ReadAll(stream.Body)
+	if err != nil || string(rest) != "-rest" {
+		t.Errorf("expected the rest after the client timeout, got %q, %v", rest, err)
+	}
 }
 
-func (e *apiError) Error() string {
-	return e.body
+func TestOpenAI_StreamErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api-version") == "slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":{"message":"slow down","code":"rate_limit_exceeded"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{httpClient: server.Client(), model: ModelTTS1, baseURL: server.URL}
+	_, err := client.Stream(context.Background(), Request{Text: "x", Voice: VoiceNova, Format: FormatOpus})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected a rate limit error, got %v", err)
+	}
+
+	// Headers slower than the client timeout are a retryable timeout
+	hc := server.Client()
+	hc.Timeout = 20 * time.Millisecond
+	slow := &Client{httpClient: hc, model: ModelTTS1, baseURL: server.URL, apiVersion: "slow"}
+	_, err = slow.Stream(context.Background(), Request{Text: "x", Voice: VoiceNova})
+	if err == nil || !IsRetryable(err) {
+		t.Errorf("expected a retryable timeout, got %v", err)
+	}
 }
@@ -0,0 +1,170 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func init() {
+	Register("piper", newPiperProvider)
+}
+
+// PiperConfig holds the settings of a local Piper voice
+type PiperConfig struct {
+	Binary      string  `json:"binary"`       // piper executable (default: piper)
+	Model       string  `json:"model"`        // path to the .onnx voice model
+	Config      string  `json:"config"`       // path to the model JSON (default: <model>.json)
+	Speaker     string  `json:"speaker"`      // default speaker for multi-speaker models
+	LengthScale float64 `json:"length_scale"` // phoneme length, >1 is slower
+}
+
+// piperModelConfig is the subset of the Piper model JSON we need
+type piperModelConfig struct {
+	Audio struct {
+		SampleRate int `json:"sample_rate"`
+	} `json:"audio"`
+	SpeakerIDMap map[string]int `json:"speaker_id_map"`
+}
+
+// Piper runs a local Piper binary to synthesize speech offline
+type Piper struct {
+	name       string
+	cfg        PiperConfig
+	sampleRate int
+	speakers   map[string]int
+}
+
+// NewPiper creates a Piper synthesizer and reads the model configuration
+func NewPiper(cfg PiperConfig) (*Piper, error) {
+	if cfg.Model == "" {
+		return nil, fmt.Errorf("piper: model path is required")
+	}
+	if cfg.Binary == "" {
+		cfg.Binary = "piper"
+	}
+	cfg.Binary = config.ExpandPath(cfg.Binary)
+	cfg.Model = config.ExpandPath(cfg.Model)
+	if cfg.Config == "" {
+		cfg.Config = cfg.Model + ".json"
+	}
+	cfg.Config = config.ExpandPath(cfg.Config)
+
+	if _, err := os.Stat(cfg.Model); err != nil {
+		return nil, fmt.Errorf("piper: voice model not found: %w", err)
+	}
+
+	data, err := os.ReadFile(cfg.Config)
+	if err != nil {
+		return nil, fmt.Errorf("piper: failed to read model config: %w", err)
+	}
+	var mc piperModelConfig
+	if err := json.Unmarshal(data, &mc); err != nil {
+		return nil, fmt.Errorf("piper: failed to parse model config: %w", err)
+	}
+	if mc.Audio.SampleRate <= 0 {
+		return nil, fmt.Errorf("piper: model config has no audio.sample_rate")
+	}
+	if cfg.Speaker != "" {
+		if _, ok := mc.SpeakerIDMap[cfg.Speaker]; !ok {
+			return nil, fmt.Errorf("piper: unknown speaker %q", cfg.Speaker)
+		}
+	}
+
+	return &Piper{
+		name:       "piper",
+		cfg:        cfg,
+		sampleRate: mc.Audio.SampleRate,
+		speakers:   mc.SpeakerIDMap,
+	}, nil
+}
+
+// newPiperProvider is the registry factory for the "piper" provider type
+func newPiperProvider(pc config.ProviderConfig) (Synthesizer, error) {
+	var cfg PiperConfig
+	if err := pc.Decode(&cfg); err != nil {
+		return nil, err
+	}
+	p, err := NewPiper(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if pc.Name != "" {
+		p.name = pc.Name
+	}
+	return p, nil
+}
+
+// Name returns the provider name
+func (p *Piper) Name() string {
+	return p.name
+}
+
+// Voices returns the speakers of a multi-speaker model.
+// Single-speaker models return nil and accept any voice.
+func (p *Piper) Voices() []Voice {
+	if len(p.speakers) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(p.speakers))
+	for name := range p.speakers {
+		if name != p.cfg.Speaker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	voices := make([]Voice, 0, len(p.speakers))
+	if p.cfg.Speaker != "" {
+		voices = append(voices, Voice(p.cfg.Speaker))
+	}
+	for _, name := range names {
+		voices = append(voices, Voice(name))
+	}
+	return voices
+}
+
+// args builds the piper command line for a voice; a non-zero speed
+// divides the length scale (1.0 when unset)
+func (p *Piper) args(voice Voice, speed float64) []string {
+	args := []string{"--model", p.cfg.Model, "--config", p.cfg.Config, "--output-raw"}
+
+	speaker := string(voice)
+	if speaker == "" {
+		speaker = p.cfg.Speaker
+	}
+	if id, ok := p.speakers[speaker]; ok {
+		args = append(args, "--speaker", strconv.Itoa(id))
+	}
+	lengthScale := p.cfg.LengthScale
+	if speed > 0 {
+		if lengthScale <= 0 {
+			lengthScale = 1
+		}
+		lengthScale /= speed
+	}
+	if lengthScale > 0 {
+		args = append(args, "--length-scale", strconv.FormatFloat(lengthScale, 'f', -1, 64))
+	}
+	return args
+}
+
+// Synthesize pipes the text to piper and returns WAV audio
+func (p *Piper) Synthesize(ctx context.Context, r Request) (*Audio, error) {
+	pcm, err := runCommand(ctx, p.cfg.Binary, p.args(r.Voice, r.Speed), strings.NewReader(r.Text+"\n"))
+	if err != nil {
+		return nil, err
+	}
+	if len(pcm) == 0 {
+		return nil, fmt.Errorf("piper produced no audio")
+	}
+
+	// Piper emits 16-bit mono PCM at the model's sample rate
+	return &Audio{Data: pcmToWAV(pcm, p.sampleRate, 1, 16), Format: FormatWAV}, nil
+}
@@ -0,0 +1,190 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// writePiperModel creates a fake .onnx model and its JSON config
+func writePiperModel(t *testing.T, modelConfig string) string {
+	t.Helper()
+	dir := t.TempDir()
+	model := filepath.Join(dir, "voice.onnx")
+	if err := os.WriteFile(model, []byte("onnx"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(model+".json", []byte(modelConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return model
+}
+
+// writeFakeEngine creates a shell script standing in for an engine binary
+func writeFakeEngine(t *testing.T, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake engine scripts require a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), "engine")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewPiper_RequiresModel(t *testing.T) {
+	_, err := NewPiper(PiperConfig{})
+	if err == nil || !strings.Contains(err.Error(), "model") {
+		t.Errorf("expected model error, got: %v", err)
+	}
+}
+
+func TestNewPiper_MissingModel(t *testing.T) {
+	_, err := NewPiper(PiperConfig{Model: filepath.Join(t.TempDir(), "nope.onnx")})
+	if err == nil {
+		t.Error("expected error for missing model file")
+	}
+}
+
+func TestNewPiper_ReadsModelConfig(t *testing.T) {
+	model := writePiperModel(t, `{"audio": {"sample_rate": 16000}, "speaker_id_map": {"b": 1, "a": 0}}`)
+
+	p, err := NewPiper(PiperConfig{Model: model, Speaker: "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.sampleRate != 16000 {
+		t.Errorf("expected sample rate 16000, got %d", p.sampleRate)
+	}
+	if p.cfg.Binary != "piper" {
+		t.Errorf("expected default binary 'piper', got %q", p.cfg.Binary)
+	}
+
+	voices := p.Voices()
+	if len(voices) != 2 || voices[0] != "b" || voices[1] != "a" {
+		t.Errorf("expected configured speaker first, got %v", voices)
+	}
+}
+
+func TestNewPiper_UnknownSpeaker(t *testing.T) {
+	model := writePiperModel(t, `{"audio": {"sample_rate": 22050}}`)
+
+	_, err := NewPiper(PiperConfig{Model: model, Speaker: "nobody"})
+	if err == nil {
+		t.Error("expected error for unknown speaker")
+	}
+}
+
+func TestPiper_Args(t *testing.T) {
+	model := writePiperModel(t, `{"audio": {"sample_rate": 22050}, "speaker_id_map": {"amy": 3}}`)
+	p, err := NewPiper(PiperConfig{Model: model, LengthScale: 1.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := strings.Join(p.args("amy", 0), " ")
+	for _, want := range []string{"--model " + model, "--output-raw", "--speaker 3", "--length-scale 1.5"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("expected args to contain %q, got %q", want, args)
+		}
+	}
+}
+
+func TestPiper_ArgsSpeed(t *testing.T) {
+	model := writePiperModel(t, `{"audio": {"sample_rate": 22050}}`)
+	p, err := NewPiper(PiperConfig{Model: model})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if args := strings.Join(p.args("", 0), " "); strings.Contains(args, "--length-scale") {
+		t.Errorf("expected no length scale by default, got %q", args)
+	}
+	if args := strings.Join(p.args("", 2), " "); !strings.Contains(args, "--length-scale 0.5") {
+		t.Errorf("expected length scale 0.5 at 2x speed, got %q", args)
+	}
+}
+
+func TestPiper_Synthesize(t *testing.T) {
+	model := writePiperModel(t, `{"audio": {"sample_rate": 22050}}`)
+	// Echo stdin back as "PCM" so we can check the text was piped in
+	bin := writeFakeEngine(t, "cat")
+
+	p, err := NewPiper(PiperConfig{Binary: bin, Model: model})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	audio, err := p.Synthesize(context.Background(), Request{Text: "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if audio.Format != FormatWAV {
+		t.Errorf("expected wav format, got %s", audio.Format)
+	}
+	if !bytes.HasPrefix(audio.Data, []byte("RIFF")) {
+		t.Error("expected WAV header")
+	}
+	if !bytes.HasSuffix(audio.Data, []byte("hello\n")) {
+		t.Errorf("expected PCM payload to contain the input text, got %q", audio.Data[44:])
+	}
+}
+
+func TestPiper_SynthesizeFailure(t *testing.T) {
+	model := writePiperModel(t, `{"audio": {"sample_rate": 22050}}`)
+	bin := writeFakeEngine(t, "echo 'model load failed' >&2; exit 1")
+
+	p, err := NewPiper(PiperConfig{Binary: bin, Model: model})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = p.Synthesize(context.Background(), Request{Text: "hello"})
+	if err == nil || !strings.Contains(err.Error(), "model load failed") {
+		t.Errorf("expected stderr in error, got: %v", err)
+	}
+}
+
+func TestNewPiperProvider_FromConfig(t *testing.T) {
+	model := writePiperModel(t, `{"audio": {"sample_rate": 22050}}`)
+	cfg := config.Default()
+	cfg.Provider = "offline"
+	cfg.Providers["offline"] = mustProviderConfig(t, `{"type": "piper", "model": "`+filepath.ToSlash(model)+`"}`)
+	t.Setenv("OPENAI_API_KEY", "")
+
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Name() != "offline" {
+		t.Errorf("expected name 'offline', got %q", s.Name())
+	}
+}
+
+func TestPCMToWAV_Header(t *testing.T) {
+	wav := pcmToWAV([]byte{1, 2, 3, 4}, 24000, 1, 16)
+
+	if len(wav) != 48 {
+		t.Fatalf("expected 48 bytes, got %d", len(wav))
+	}
+	if string(wav[0:4]) != "RIFF" || string(wav[8:12]) != "WAVE" || string(wav[36:40]) != "data" {
+		t.Error("malformed WAV header")
+	}
+}
+
+// mustProviderConfig parses a provider entry as it would appear in tts.json
+func mustProviderConfig(t *testing.T, data string) config.ProviderConfig {
+	t.Helper()
+	var pc config.ProviderConfig
+	if err := pc.UnmarshalJSON([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	return pc
+}
@@ -0,0 +1,282 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func init() {
+	Register("polly", newPollyProvider)
+}
+
+// Polly engines
+const (
+	PollyEngineStandard   = "standard"
+	PollyEngineNeural     = "neural"
+	PollyEngineGenerative = "generative"
+)
+
+// PollyConfig holds the settings of the Amazon Polly provider
+type PollyConfig struct {
+	Region       string `json:"region"`        // AWS region (default: from the environment/profile)
+	Profile      string `json:"profile"`       // shared credentials profile (default: AWS_PROFILE or default)
+	Engine       string `json:"engine"`        // standard, neural or generative (default: neural)
+	Voice        string `json:"voice"`         // default VoiceId (default: Joanna)
+	LanguageCode string `json:"language_code"` // for bilingual voices, e.g. en-IN
+	Endpoint     string `json:"endpoint"`      // override the regional endpoint
+}
+
+// Polly handles Amazon Polly SynthesizeSpeech requests
+type Polly struct {
+	name       string
+	cfg        PollyConfig
+	creds      AWSCredentials
+	httpClient *http.Client
+	now        func() time.Time
+
+	voices voiceCache[Voice]
+}
+
+// NewPolly creates a Polly client using the standard AWS credential chain
+func NewPolly(cfg PollyConfig) (*Polly, error) {
+	switch cfg.Engine {
+	case "":
+		cfg.Engine = PollyEngineNeural
+	case PollyEngineStandard, PollyEngineNeural, PollyEngineGenerative:
+	default:
+		return nil, fmt.Errorf("polly: unknown engine %q (use standard, neural or generative)", cfg.Engine)
+	}
+	if cfg.Voice == "" {
+		cfg.Voice = "Joanna"
+	}
+	if cfg.Region == "" {
+		cfg.Region = LoadAWSRegion(cfg.Profile)
+	}
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("polly: no AWS region configured (set region, AWS_REGION or a profile region)")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://polly.%s.amazonaws.com", cfg.Region)
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+
+	creds, err := LoadAWSCredentials(cfg.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("polly: %w", err)
+	}
+
+	return &Polly{
+		name:  "polly",
+		cfg:   cfg,
+		creds: creds,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// newPollyProvider is the registry factory for the "polly" provider type
+func newPollyProvider(pc config.ProviderConfig) (Synthesizer, error) {
+	var cfg PollyConfig
+	if err := pc.Decode(&cfg); err != nil {
+		return nil, err
+	}
+	p, err := NewPolly(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if pc.Name != "" {
+		p.name = pc.Name
+	}
+	return p, nil
+}
+
+// Name returns the provider name
+func (p *Polly) Name() string {
+	return p.name
+}
+
+// Model returns the engine, which sets the price per character
+func (p *Polly) Model() string {
+	return p.cfg.Engine
+}
+
+// do signs and sends a Polly API request
+func (p *Polly) do(ctx context.Context, method, path string, query url.Values, payload []byte) (*http.Response, error) {
+	endpoint := p.cfg.Endpoint + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	signV4(req, payload, p.creds, p.cfg.Region, "polly", p.now())
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// MaxTextLength returns Polly's limit of 3000 billed characters per request
+func (p *Polly) MaxTextLength() int {
+	return 3000
+}
+
+// Voices returns the voice IDs available for the configured engine,
+// default voice first. It returns nil if DescribeVoices fails.
+func (p *Polly) Voices() []Voice {
+	return p.voices.get(p.fetchVoices)
+}
+
+// fetchVoices calls DescribeVoices
+func (p *Polly) fetchVoices(ctx context.Context) ([]Voice, error) {
+	query := url.Values{"Engine": {p.cfg.Engine}}
+	if p.cfg.LanguageCode != "" {
+		query.Set("LanguageCode", p.cfg.LanguageCode)
+	}
+	resp, err := p.do(ctx, "GET", "/v1/voices", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("polly: voice list returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Voices []struct {
+			ID string `json:"Id"`
+		} `json:"Voices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("polly: failed to decode voices: %w", err)
+	}
+
+	voices := []Voice{Voice(p.cfg.Voice)}
+	for _, v := range body.Voices {
+		if v.ID != p.cfg.Voice {
+			voices = append(voices, Voice(v.ID))
+		}
+	}
+	return voices, nil
+}
+
+// pollyRequest is the SynthesizeSpeech request payload
+type pollyRequest struct {
+	Engine       string `json:"Engine"`
+	LanguageCode string `json:"LanguageCode,omitempty"`
+	OutputFormat string `json:"OutputFormat"`
+	Text         string `json:"Text"`
+	TextType     string `json:"TextType"`
+	VoiceID      string `json:"VoiceId"`
+}
+
+// Polly speaks from 20% to 200% of the normal rate
+const (
+	pollyMinRate = 20
+	pollyMaxRate = 200
+)
+
+// Synthesize converts text or SSML to speech and returns MP3 audio data.
+// A speed is sent as an SSML prosody rate, within the 20-200% Polly
+// supports; SSML input sets its own rate.
+func (p *Polly) Synthesize(ctx context.Context, r Request) (*Audio, error) {
+	voice := string(r.Voice)
+	if voice == "" || containsVoice(OpenAIVoices(), r.Voice) {
+		voice = p.cfg.Voice
+	}
+
+	text, textType := r.Text, "text"
+	changeRate := r.Speed > 0 && r.Speed != 1
+	switch {
+	case IsSSML(text) && changeRate:
+		return nil, fmt.Errorf("polly: speed cannot be applied to SSML, use <prosody rate> instead: %w", ErrInvalidInput)
+	case IsSSML(text):
+		textType = "ssml"
+	case changeRate:
+		rate := max(pollyMinRate, min(pollyMaxRate, int(math.Round(r.Speed*100))))
+		var escaped bytes.Buffer
+		_ = xml.EscapeText(&escaped, []byte(text))
+		text = fmt.Sprintf(`<speak><prosody rate="%d%%">%s</prosody></speak>`, rate, escaped.String())
+		textType = "ssml"
+	}
+
+	jsonData, err := json.Marshal(pollyRequest{
+		Engine:       p.cfg.Engine,
+		LanguageCode: p.cfg.LanguageCode,
+		OutputFormat: "mp3",
+		Text:         text,
+		TextType:     textType,
+		VoiceID:      voice,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := p.do(ctx, "POST", "/v1/speech", nil, jsonData)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, parsePollyError(resp)
+	}
+
+	audioData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return &Audio{Data: audioData, Format: FormatMP3}, nil
+}
+
+// parsePollyError builds an error from an AWS JSON error response
+func parsePollyError(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	errType := resp.Header.Get("X-Amzn-ErrorType")
+	errType, _, _ = strings.Cut(errType, ":")
+
+	// AWS uses both "message" and "Message"; decoding is case-insensitive
+	var parsed struct {
+		Message string `json:"message"`
+	}
+	msg := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &parsed) == nil && parsed.Message != "" {
+		msg = parsed.Message
+	}
+
+	if errType != "" {
+		apiErr := newAPIError(resp, "Polly API error (status %d, %s): %s", resp.StatusCode, errType, msg)
+		apiErr.Code = errType
+		// Polly reports throttling as a 400
+		if errType == "ThrottlingException" {
+			apiErr.Kind = ErrRateLimited
+		}
+		return apiErr
+	}
+	return newAPIError(resp, "Polly API error (status %d): %s", resp.StatusCode, msg)
+}
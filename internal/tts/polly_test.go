@@ -0,0 +1,275 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setAWSEnv isolates the AWS credential chain from the host
+func setAWSEnv(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	return dir
+}
+
+func TestSignV4_AWSTestSuite(t *testing.T) {
+	// "get-vanilla" from the AWS Signature Version 4 test suite
+	req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+	creds := AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	signV4(req, nil, creds, "us-east-1", "service", now)
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+	if req.Header.Get("X-Amz-Date") != "20150830T123600Z" {
+		t.Errorf("unexpected X-Amz-Date %q", req.Header.Get("X-Amz-Date"))
+	}
+}
+
+func TestSignV4_SessionTokenSigned(t *testing.T) {
+	req, _ := http.NewRequest("POST", "https://polly.eu-west-1.amazonaws.com/v1/speech", nil)
+	signV4(req, []byte("{}"), AWSCredentials{AccessKeyID: "id", SecretAccessKey: "s", SessionToken: "tok"}, "eu-west-1", "polly", time.Now())
+
+	if req.Header.Get("X-Amz-Security-Token") != "tok" {
+		t.Error("expected session token header")
+	}
+	if !strings.Contains(req.Header.Get("Authorization"), "x-amz-security-token") {
+		t.Error("expected session token to be signed")
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://example.com/?b=2&a=hello world&a=1", nil)
+	if got := canonicalQuery(req.URL); got != "a=1&a=hello%20world&b=2" {
+		t.Errorf("canonicalQuery() = %q", got)
+	}
+}
+
+func TestLoadAWSCredentials_Env(t *testing.T) {
+	setAWSEnv(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "env-id")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+
+	creds, err := LoadAWSCredentials("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds.AccessKeyID != "env-id" || creds.SecretAccessKey != "env-secret" {
+		t.Errorf("unexpected credentials: %+v", creds)
+	}
+}
+
+func TestLoadAWSCredentials_Profile(t *testing.T) {
+	dir := setAWSEnv(t)
+	credentials := "[default]\naws_access_key_id = default-id\naws_secret_access_key = default-secret\n\n" +
+		"# work account\n[work]\naws_access_key_id = work-id\naws_secret_access_key = work-secret\naws_session_token = work-token\n"
+	if err := os.WriteFile(filepath.Join(dir, "credentials"), []byte(credentials), 0600); err != nil {
+		t.Fatal(err)
+	}
+	configFile := "[default]\nregion = us-east-1\n[profile work]\nregion = eu-central-1\n"
+	if err := os.WriteFile(filepath.Join(dir, "config"), []byte(configFile), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err := LoadAWSCredentials("")
+	if err != nil || creds.AccessKeyID != "default-id" {
+		t.Errorf("expected default profile, got %+v (%v)", creds, err)
+	}
+
+	t.Setenv("AWS_PROFILE", "work")
+	creds, err = LoadAWSCredentials("")
+	if err != nil || creds.SessionToken != "work-token" {
+		t.Errorf("expected work profile, got %+v (%v)", creds, err)
+	}
+	if region := LoadAWSRegion(""); region != "eu-central-1" {
+		t.Errorf("expected work profile region, got %q", region)
+	}
+	if region := LoadAWSRegion("default"); region != "us-east-1" {
+		t.Errorf("expected default region, got %q", region)
+	}
+
+	if _, err := LoadAWSCredentials("missing"); err == nil {
+		t.Error("expected error for missing profile")
+	}
+}
+
+func TestNewPolly_Validation(t *testing.T) {
+	setAWSEnv(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "id")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	if _, err := NewPolly(PollyConfig{Region: "us-east-1", Engine: "turbo"}); err == nil {
+		t.Error("expected error for unknown engine")
+	}
+	if _, err := NewPolly(PollyConfig{}); err == nil {
+		t.Error("expected error without a region")
+	}
+
+	p, err := NewPolly(PollyConfig{Region: "us-west-2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.cfg.Engine != "neural" || p.cfg.Voice != "Joanna" {
+		t.Errorf("unexpected defaults: %+v", p.cfg)
+	}
+	if p.cfg.Endpoint != "https://polly.us-west-2.amazonaws.com" {
+		t.Errorf("unexpected endpoint %q", p.cfg.Endpoint)
+	}
+}
+
+func TestIsSSML(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"<speak>Hello <break time=\"1s\"/> world</speak>", true},
+		{"  <speak version=\"1.1\">Hi</speak>\n", true},
+		{"Hello <b>world</b>", false},
+		{"<speak>unterminated", false},
+	}
+	for _, tt := range tests {
+		if got := IsSSML(tt.text); got != tt.want {
+			t.Errorf("IsSSML(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func newPollyTestClient(t *testing.T, handler http.HandlerFunc, engine string) *Polly {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	setAWSEnv(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDTEST")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	p, err := NewPolly(PollyConfig{Region: "eu-west-1", Engine: engine, Endpoint: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPolly_Synthesize(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		voice    Voice
+		engine   string
+		textType string
+		voiceID  string
+	}{
+		{"plain text", "Hello", "Matthew", "standard", "text", "Matthew"},
+		{"ssml", "<speak>Hello<break/></speak>", "", "neural", "ssml", "Joanna"},
+		{"openai voice falls back", "Hello", VoiceNova, "generative", "text", "Joanna"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPollyTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != "POST" || r.URL.Path != "/v1/speech" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				auth := r.Header.Get("Authorization")
+				if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDTEST/") || !strings.Contains(auth, "/eu-west-1/polly/aws4_request") {
+					t.Errorf("expected SigV4 authorization, got %q", auth)
+				}
+
+				body, _ := io.ReadAll(r.Body)
+				var req pollyRequest
+				if err := json.Unmarshal(body, &req); err != nil {
+					t.Fatalf("failed to unmarshal request: %v", err)
+				}
+				if req.TextType != tt.textType || req.VoiceID != tt.voiceID || req.Engine != tt.engine || req.OutputFormat != "mp3" {
+					t.Errorf("unexpected request: %+v", req)
+				}
+				_, _ = w.Write([]byte("polly-audio"))
+			}, tt.engine)
+
+			audio, err := p.Synthesize(context.Background(), Request{Text: tt.text, Voice: tt.voice})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(audio.Data) != "polly-audio" || audio.Format != FormatMP3 {
+				t.Errorf("unexpected audio %s %q", audio.Format, audio.Data)
+			}
+		})
+	}
+}
+
+func TestPolly_Speed(t *testing.T) {
+	var got pollyRequest
+	p := newPollyTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("failed to unmarshal request: %v", err)
+		}
+		_, _ = w.Write([]byte("polly-audio"))
+	}, "neural")
+
+	if _, err := p.Synthesize(context.Background(), Request{Text: "Tests & lint", Speed: 1.5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.TextType != "ssml" || got.Text != `<speak><prosody rate="150%">Tests &amp; lint</prosody></speak>` {
+		t.Errorf("expected the speed as a prosody rate, got %+v", got)
+	}
+
+	_, err := p.Synthesize(context.Background(), Request{Text: "<speak>Hi</speak>", Speed: 2})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("expected a speed with SSML to be rejected, got %v", err)
+	}
+}
+
+func TestPolly_SynthesizeError(t *testing.T) {
+	p := newPollyTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Amzn-ErrorType", "InvalidSsmlException:http://internal.amazon.com/coral/")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message": "Invalid SSML request"}`))
+	}, "")
+
+	_, err := p.Synthesize(context.Background(), Request{Text: "<speak>oops</speak>"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "InvalidSsmlException") || !strings.Contains(err.Error(), "Invalid SSML request") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPolly_Voices(t *testing.T) {
+	p := newPollyTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/voices" || r.URL.Query().Get("Engine") != "neural" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"Voices": [{"Id": "Amy"}, {"Id": "Joanna"}]}`))
+	}, "")
+
+	voices := p.Voices()
+	if len(voices) != 2 || voices[0] != "Joanna" || voices[1] != "Amy" {
+		t.Errorf("expected default voice first, got %v", voices)
+	}
+}
@@ -0,0 +1,144 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// Retry policy defaults
+const (
+	DefaultMaxAttempts = 4
+	DefaultBaseDelay   = 500 * time.Millisecond
+	DefaultMaxDelay    = 20 * time.Second
+)
+
+// RetryPolicy retries transient synthesis failures with jittered
+// exponential backoff
+type RetryPolicy struct {
+	MaxAttempts int           // total tries, including the first
+	BaseDelay   time.Duration // delay before the second try
+	MaxDelay    time.Duration // cap on any single wait
+
+	sleep  func(context.Context, time.Duration) error
+	jitter func(time.Duration) time.Duration
+}
+
+// RetryAttempt describes a failed try that is about to be retried
+type RetryAttempt struct {
+	Attempt int           // 1-based number of the failed try
+	Err     error         // why it failed
+	Wait    time.Duration // delay before the next try
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultMaxAttempts,
+		BaseDelay:   DefaultBaseDelay,
+		MaxDelay:    DefaultMaxDelay,
+	}
+}
+
+// NewRetryPolicy builds a policy from the config, filling in defaults
+func NewRetryPolicy(cfg config.RetryConfig) RetryPolicy {
+	p := DefaultRetryPolicy()
+	if cfg.MaxAttempts > 0 {
+		p.MaxAttempts = cfg.MaxAttempts
+	}
+	if cfg.BaseDelayMS > 0 {
+		p.BaseDelay = time.Duration(cfg.BaseDelayMS) * time.Millisecond
+	}
+	if cfg.MaxDelayMS > 0 {
+		p.MaxDelay = time.Duration(cfg.MaxDelayMS) * time.Millisecond
+	}
+	return p
+}
+
+// backoff returns the wait before the try after the given failed attempt.
+// The exponential delay is jittered between half and all of its value,
+// and a longer Retry-After from the server takes precedence.
+func (p RetryPolicy) backoff(attempt int, err error) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	jitter := p.jitter
+	if jitter == nil {
+		jitter = func(d time.Duration) time.Duration {
+			return time.Duration(rand.Int63n(int64(d) + 1))
+		}
+	}
+	delay = delay/2 + jitter(delay/2)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+	return delay
+}
+
+// sleepContext waits for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Synthesize calls s.Synthesize until it succeeds, fails permanently or
+// runs out of attempts. onRetry, if non-nil, is called before each wait.
+// A server asking to wait longer than MaxDelay ends the retries, and so
+// does cancelling ctx.
+func (p RetryPolicy) Synthesize(ctx context.Context, s Synthesizer, req Request, onRetry func(RetryAttempt)) (*Audio, error) {
+	var audio *Audio
+	err := p.do(ctx, onRetry, func() (err error) {
+		audio, err = s.Synthesize(ctx, req)
+		return err
+	})
+	return audio, err
+}
+
+// Stream opens a stream from s like Synthesize, retrying until the
+// response starts. Failures once audio is flowing are not retried.
+func (p RetryPolicy) Stream(ctx context.Context, s Synthesizer, req Request, onRetry func(RetryAttempt)) (*AudioStream, error) {
+	var stream *AudioStream
+	err := p.do(ctx, onRetry, func() (err error) {
+		stream, err = Stream(ctx, s, req)
+		return err
+	})
+	return stream, err
+}
+
+// do runs call with the retry policy
+func (p RetryPolicy) do(ctx context.Context, onRetry func(RetryAttempt), call func() error) error {
+	sleep := p.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := call()
+		if err == nil || attempt >= p.MaxAttempts || !IsRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		wait := p.backoff(attempt, err)
+		if wait > p.MaxDelay {
+			return err
+		}
+		if onRetry != nil {
+			onRetry(RetryAttempt{Attempt: attempt, Err: err, Wait: wait})
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
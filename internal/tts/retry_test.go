@@ -0,0 +1,228 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// flakySynthesizer fails with the queued errors before succeeding
+type flakySynthesizer struct {
+	errs  []error
+	calls int
+}
+
+func (f *flakySynthesizer) Name() string { return "flaky" }
+
+func (f *flakySynthesizer) Synthesize(ctx context.Context, req Request) (*Audio, error) {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+	return &Audio{Data: []byte("ok"), Format: FormatMP3}, nil
+}
+
+// testRetryPolicy records sleeps instead of waiting and disables jitter
+func testRetryPolicy(maxAttempts int, slept *[]time.Duration) RetryPolicy {
+	p := RetryPolicy{MaxAttempts: maxAttempts, BaseDelay: 100 * time.Millisecond, MaxDelay: 2 * time.Second}
+	p.sleep = func(ctx context.Context, d time.Duration) error {
+		*slept = append(*slept, d)
+		return nil
+	}
+	p.jitter = func(d time.Duration) time.Duration { return d }
+	return p
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	p := NewRetryPolicy(config.RetryConfig{})
+	if p.MaxAttempts != DefaultMaxAttempts || p.BaseDelay != DefaultBaseDelay || p.MaxDelay != DefaultMaxDelay {
+		t.Errorf("expected defaults, got %+v", p)
+	}
+
+	p = NewRetryPolicy(config.RetryConfig{MaxAttempts: 1, BaseDelayMS: 50, MaxDelayMS: 1000})
+	if p.MaxAttempts != 1 || p.BaseDelay != 50*time.Millisecond || p.MaxDelay != time.Second {
+		t.Errorf("unexpected policy %+v", p)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	var slept []time.Duration
+	synth := &flakySynthesizer{errs: []error{
+		&APIError{StatusCode: 500, Kind: ErrServerError},
+		&APIError{StatusCode: 502, Kind: ErrServerError},
+		&APIError{StatusCode: 503, Kind: ErrServerError},
+	}}
+
+	var attempts []RetryAttempt
+	audio, err := testRetryPolicy(5, &slept).Synthesize(context.Background(), synth, Request{Text: "hi"}, func(a RetryAttempt) {
+		attempts = append(attempts, a)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(audio.Data) != "ok" || synth.calls != 4 {
+		t.Errorf("expected success on the 4th call, got %d calls", synth.calls)
+	}
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}
+	if len(slept) != len(want) {
+		t.Fatalf("expected %d sleeps, got %v", len(want), slept)
+	}
+	for i, d := range want {
+		if slept[i] != d || attempts[i].Wait != d || attempts[i].Attempt != i+1 {
+			t.Errorf("retry %d: slept %v, attempt %+v, want %v", i, slept[i], attempts[i], d)
+		}
+	}
+}
+
+func TestRetryPolicy_Jitter(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	for i := 0; i < 100; i++ {
+		d := p.backoff(2, errors.New("x"))
+		if d < time.Second || d > 2*time.Second {
+			t.Fatalf("jittered delay %v outside [1s, 2s]", d)
+		}
+	}
+}
+
+func TestRetryPolicy_MaxAttempts(t *testing.T) {
+	var slept []time.Duration
+	synth := &flakySynthesizer{errs: []error{
+		&APIError{StatusCode: 500, Kind: ErrServerError, Message: "one"},
+		&APIError{StatusCode: 500, Kind: ErrServerError, Message: "two"},
+		&APIError{StatusCode: 500, Kind: ErrServerError, Message: "three"},
+	}}
+
+	_, err := testRetryPolicy(2, &slept).Synthesize(context.Background(), synth, Request{Text: "hi"}, nil)
+	if err == nil || err.Error() != "two" {
+		t.Errorf("expected the last error, got %v", err)
+	}
+	if synth.calls != 2 {
+		t.Errorf("expected 2 calls, got %d", synth.calls)
+	}
+}
+
+func TestRetryPolicy_PermanentError(t *testing.T) {
+	var slept []time.Duration
+	synth := &flakySynthesizer{errs: []error{&APIError{StatusCode: 401, Kind: ErrUnauthorized}, errors.New("engine crashed")}}
+
+	if _, err := testRetryPolicy(5, &slept).Synthesize(context.Background(), synth, Request{Text: "hi"}, nil); err == nil {
+		t.Error("expected error")
+	}
+	if synth.calls != 1 || len(slept) != 0 {
+		t.Errorf("expected no retries for 401, got %d calls", synth.calls)
+	}
+
+	synth.calls = 0
+	if _, err := testRetryPolicy(5, &slept).Synthesize(context.Background(), synth, Request{Text: "hi"}, nil); err == nil {
+		t.Error("expected error")
+	}
+	if synth.calls != 1 {
+		t.Errorf("expected no retries for a local error, got %d calls", synth.calls)
+	}
+}
+
+func TestRetryPolicy_QuotaNotRetried(t *testing.T) {
+	var slept []time.Duration
+	synth := &flakySynthesizer{errs: []error{&APIError{StatusCode: 429, Code: "insufficient_quota", Kind: ErrQuotaExceeded}}}
+
+	_, err := testRetryPolicy(5, &slept).Synthesize(context.Background(), synth, Request{Text: "hi"}, nil)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected quota error, got %v", err)
+	}
+	if synth.calls != 1 {
+		t.Errorf("expected a quota error not to be retried, got %d calls", synth.calls)
+	}
+}
+
+func TestRetryPolicy_RetryAfter(t *testing.T) {
+	var slept []time.Duration
+	synth := &flakySynthesizer{errs: []error{&APIError{StatusCode: 429, Kind: ErrRateLimited, RetryAfter: 1500 * time.Millisecond}}}
+
+	if _, err := testRetryPolicy(3, &slept).Synthesize(context.Background(), synth, Request{Text: "hi"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(slept) != 1 || slept[0] != 1500*time.Millisecond {
+		t.Errorf("expected to honor Retry-After, slept %v", slept)
+	}
+
+	// Waits beyond MaxDelay give up instead of blocking the worker
+	slept = nil
+	synth = &flakySynthesizer{errs: []error{&APIError{StatusCode: 429, Kind: ErrRateLimited, RetryAfter: time.Minute}}}
+	if _, err := testRetryPolicy(3, &slept).Synthesize(context.Background(), synth, Request{Text: "hi"}, nil); err == nil {
+		t.Error("expected error when Retry-After exceeds MaxDelay")
+	}
+	if len(slept) != 0 {
+		t.Errorf("expected no sleep, got %v", slept)
+	}
+}
+
+func TestRetryPolicy_OpenAIRateLimit(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("x-ratelimit-reset-requests", "250ms")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error": {"message": "Rate limit reached"}}`))
+			return
+		}
+		_, _ = w.Write([]byte("audio"))
+	}))
+	defer server.Close()
+
+	client := &Client{apiKey: "k", httpClient: server.Client(), model: ModelTTS1, baseURL: server.URL}
+
+	var slept []time.Duration
+	if _, err := testRetryPolicy(3, &slept).Synthesize(context.Background(), client, Request{Text: "hi", Voice: VoiceNova}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 2 || len(slept) != 1 || slept[0] != 250*time.Millisecond {
+		t.Errorf("expected one retry after 250ms, got %d calls, slept %v", calls, slept)
+	}
+}
+
+func TestRetryPolicy_ContextCancelled(t *testing.T) {
+	synth := &flakySynthesizer{errs: []error{&APIError{StatusCode: 503, Kind: ErrServerError}}}
+	p := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := p.Synthesize(ctx, synth, Request{Text: "hi"}, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline error, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("expected the backoff wait to be interrupted")
+	}
+	if synth.calls != 1 {
+		t.Errorf("expected 1 call, got %d", synth.calls)
+	}
+}
+
+func TestRetryPolicy_Stream(t *testing.T) {
+	var slept []time.Duration
+	s := &flakySynthesizer{errs: []error{&APIError{StatusCode: 503, Kind: ErrServerError}}}
+
+	stream, err := testRetryPolicy(3, &slept).Stream(context.Background(), s, Request{Text: "hi"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := io.ReadAll(stream.Body)
+	if string(data) != "ok" || stream.Format != FormatMP3 {
+		t.Errorf("expected the synthesized audio as a stream, got %q (%s)", data, stream.Format)
+	}
+	if s.calls != 2 || len(slept) != 1 {
+		t.Errorf("expected one retry, got %d calls and %d sleeps", s.calls, len(slept))
+	}
+}
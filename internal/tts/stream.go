@@ -0,0 +1,59 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// AudioStream is audio delivered while it is being synthesized
+type AudioStream struct {
+	Body   io.ReadCloser
+	Format Format
+	// SampleRate is the rate of FormatPCM streams (16-bit mono samples
+	// per second); zero for other formats
+	SampleRate int
+	// Provider names the provider that produced the audio, like
+	// Audio.Provider; may be empty
+	Provider string
+}
+
+// Streamer is implemented by synthesizers that can return audio before
+// synthesis has finished
+type Streamer interface {
+	Stream(ctx context.Context, req Request) (*AudioStream, error)
+}
+
+// Stream opens a stream from s. Synthesizers that cannot stream
+// synthesize the whole audio first and return it as a stream.
+func Stream(ctx context.Context, s Synthesizer, req Request) (*AudioStream, error) {
+	if st, ok := s.(Streamer); ok {
+		return st.Stream(ctx, req)
+	}
+	audio, err := s.Synthesize(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return bufferedStream(audio), nil
+}
+
+// bufferedStream returns complete audio as a stream
+func bufferedStream(audio *Audio) *AudioStream {
+	return &AudioStream{
+		Body:     io.NopCloser(bytes.NewReader(audio.Data)),
+		Format:   audio.Format,
+		Provider: audio.Provider,
+	}
+}
+
+// cancelBody releases the request context when the body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
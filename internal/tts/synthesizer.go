@@ -0,0 +1,228 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// Format identifies the encoding of synthesized audio
+type Format string
+
+const (
+	FormatMP3  Format = "mp3"
+	FormatWAV  Format = "wav"
+	FormatOpus Format = "opus"
+	FormatAAC  Format = "aac"
+	FormatFLAC Format = "flac"
+	FormatPCM  Format = "pcm"
+
+	// FormatNone marks audio that the engine already played itself
+	// (e.g. "festival --tts"); there is nothing left to play back.
+	FormatNone Format = "none"
+)
+
+// Speed limits accepted by Request.Speed
+const (
+	MinSpeed = 0.25
+	MaxSpeed = 4.0
+)
+
+// ResponseFormats returns the formats a request may ask for
+func ResponseFormats() []Format {
+	return []Format{FormatMP3, FormatOpus, FormatAAC, FormatFLAC, FormatWAV, FormatPCM}
+}
+
+// Request describes a single synthesis call
+type Request struct {
+	Text  string
+	Voice Voice
+	// Speed scales the speaking rate (0.25-4.0); zero keeps the provider default
+	Speed float64
+	// Format is the preferred encoding; providers that cannot produce it
+	// return their native format instead
+	Format Format
+	// Instructions describe the delivery, e.g. "calm" or "urgent"
+	// (only used by models that support it, such as gpt-4o-mini-tts)
+	Instructions string
+}
+
+// Validate checks the optional request settings
+func (r Request) Validate() error {
+	if r.Speed != 0 && (r.Speed < MinSpeed || r.Speed > MaxSpeed) {
+		return fmt.Errorf("speed must be between %g and %g, got %g", MinSpeed, MaxSpeed, r.Speed)
+	}
+	if r.Format != "" {
+		for _, f := range ResponseFormats() {
+			if f == r.Format {
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown response format %q (available: %v)", r.Format, ResponseFormats())
+	}
+	return nil
+}
+
+// Audio is the result of a synthesis call
+type Audio struct {
+	Data   []byte
+	Format Format
+	// Provider names the provider that produced the audio when it differs
+	// from the synthesizer called (e.g. a failover chain); may be empty
+	Provider string
+}
+
+// Synthesizer converts text to speech.
+// Each TTS backend (OpenAI, local engines, ...) implements it.
+type Synthesizer interface {
+	// Name returns the configured name of the provider
+	Name() string
+	// Synthesize converts the request text to audio
+	Synthesize(ctx context.Context, req Request) (*Audio, error)
+}
+
+// IsSSML reports whether text is tagged as SSML (wrapped in <speak>)
+func IsSSML(text string) bool {
+	t := strings.TrimSpace(text)
+	return strings.HasPrefix(t, "<speak") && strings.HasSuffix(t, "</speak>")
+}
+
+// SelfPlayer is implemented by synthesizers whose engine may speak the
+// text itself while synthesizing, returning FormatNone
+type SelfPlayer interface {
+	PlaysAudio() bool
+}
+
+// PlaysAudio reports whether s may speak while synthesizing, so its calls
+// must not overlap each other or other playback
+func PlaysAudio(s Synthesizer) bool {
+	sp, ok := s.(SelfPlayer)
+	return ok && sp.PlaysAudio()
+}
+
+// VoiceLister is implemented by synthesizers with a fixed set of voices.
+// The first voice returned is the provider's default.
+type VoiceLister interface {
+	Voices() []Voice
+}
+
+// voiceListTimeout bounds a request for a provider's voice list, which
+// may run while the server starts
+const voiceListTimeout = 5 * time.Second
+
+// voiceListRetry is how long a failed voice list request is not repeated
+const voiceListRetry = 30 * time.Second
+
+// voiceCache keeps a provider's voice list once it has been fetched. A
+// failure is not kept: the list is fetched again after voiceListRetry.
+type voiceCache[T any] struct {
+	mu       sync.Mutex
+	voices   []T
+	fetched  bool
+	failedAt time.Time
+}
+
+// get returns the cached voices, fetching them first if needed. It
+// returns nil while the list cannot be fetched.
+func (c *voiceCache[T]) get(fetch func(ctx context.Context) ([]T, error)) []T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fetched || time.Since(c.failedAt) < voiceListRetry {
+		return c.voices
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), voiceListTimeout)
+	defer cancel()
+	voices, err := fetch(ctx)
+	if err != nil {
+		c.failedAt = time.Now()
+		return nil
+	}
+	c.voices, c.fetched = voices, true
+	return voices
+}
+
+// Factory builds a Synthesizer from its provider settings
+type Factory func(cfg config.ProviderConfig) (Synthesizer, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a provider type available by name.
+// It panics if called twice with the same name or with a nil factory.
+func Register(kind string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if factory == nil {
+		panic("tts: Register factory is nil")
+	}
+	if _, dup := registry[kind]; dup {
+		panic("tts: Register called twice for provider " + kind)
+	}
+	registry[kind] = factory
+}
+
+// Providers returns the sorted list of registered provider types
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	kinds := make([]string, 0, len(registry))
+	for kind := range registry {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// NewProvider creates the named provider from the configuration
+func NewProvider(cfg *config.Config, name string) (Synthesizer, error) {
+	pc := cfg.Lookup(name)
+
+	registryMu.RLock()
+	factory, ok := registry[pc.Type]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown TTS provider type %q (available: %v)", pc.Type, Providers())
+	}
+	return factory(pc)
+}
+
+// New creates the active provider from the configuration. When fallbacks
+// are configured it returns a Chain that fails over to them in order.
+func New(cfg *config.Config) (Synthesizer, error) {
+	if len(cfg.Fallbacks) == 0 {
+		return NewProvider(cfg, cfg.Provider)
+	}
+	return newChainFromConfig(cfg)
+}
+
+// DefaultVoice returns the default voice of s, or "" if it has none
+func DefaultVoice(s Synthesizer) Voice {
+	if vl, ok := s.(VoiceLister); ok {
+		if voices := vl.Voices(); len(voices) > 0 {
+			return voices[0]
+		}
+	}
+	return ""
+}
+
+// SupportsVoice reports whether s accepts the given voice.
+// Providers that do not list their voices accept any voice.
+func SupportsVoice(s Synthesizer, voice Voice) bool {
+	vl, ok := s.(VoiceLister)
+	if !ok {
+		return true
+	}
+	voices := vl.Voices()
+	return len(voices) == 0 || containsVoice(voices, voice)
+}
@@ -0,0 +1,127 @@
+package tts
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+// stubSynthesizer is a minimal Synthesizer for registry tests
+type stubSynthesizer struct {
+	name   string
+	voices []Voice
+}
+
+func (s *stubSynthesizer) Name() string { return s.name }
+
+func (s *stubSynthesizer) Synthesize(ctx context.Context, req Request) (*Audio, error) {
+	return &Audio{Data: []byte(req.Text), Format: FormatWAV}, nil
+}
+
+func (s *stubSynthesizer) Voices() []Voice { return s.voices }
+
+func init() {
+	Register("stub", func(cfg config.ProviderConfig) (Synthesizer, error) {
+		return &stubSynthesizer{name: cfg.Name}, nil
+	})
+}
+
+func TestProviders_IncludesBuiltins(t *testing.T) {
+	providers := Providers()
+
+	for _, want := range []string{"openai", "stub"} {
+		found := false
+		for _, p := range providers {
+			if p == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected provider %q to be registered, got %v", want, providers)
+		}
+	}
+}
+
+func TestRegister_DuplicatePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic on duplicate registration")
+		}
+	}()
+	Register("stub", func(cfg config.ProviderConfig) (Synthesizer, error) { return nil, nil })
+}
+
+func TestNewProvider_NamedInstance(t *testing.T) {
+	cfg := config.Default()
+	cfg.Providers["mine"] = config.ProviderConfig{Type: "stub"}
+
+	s, err := NewProvider(cfg, "mine")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Name() != "mine" {
+		t.Errorf("expected name 'mine', got %q", s.Name())
+	}
+}
+
+func TestNew_UnknownProvider(t *testing.T) {
+	cfg := config.Default()
+	cfg.Provider = "does-not-exist"
+
+	_, err := New(cfg)
+	if err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+	if !strings.Contains(err.Error(), "does-not-exist") {
+		t.Errorf("expected error to name the provider, got: %v", err)
+	}
+}
+
+func TestNew_OpenAIRequiresKey(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "")
+
+	_, err := New(config.Default())
+	if err == nil || !strings.Contains(err.Error(), "OPENAI_API_KEY") {
+		t.Errorf("expected OPENAI_API_KEY error, got: %v", err)
+	}
+}
+
+func TestDefaultVoiceAndSupportsVoice(t *testing.T) {
+	listed := &stubSynthesizer{voices: []Voice{"a", "b"}}
+	if DefaultVoice(listed) != "a" {
+		t.Errorf("expected default voice 'a', got %q", DefaultVoice(listed))
+	}
+	if !SupportsVoice(listed, "b") || SupportsVoice(listed, "c") {
+		t.Error("expected SupportsVoice to check the voice list")
+	}
+
+	open := &stubSynthesizer{}
+	if DefaultVoice(open) != "" {
+		t.Errorf("expected no default voice, got %q", DefaultVoice(open))
+	}
+	if !SupportsVoice(open, "anything") {
+		t.Error("expected providers without a voice list to accept any voice")
+	}
+}
+
+func TestRequest_Validate(t *testing.T) {
+	tests := []struct {
+		req     Request
+		wantErr bool
+	}{
+		{Request{}, false},
+		{Request{Speed: 0.25, Format: FormatFLAC}, false},
+		{Request{Speed: 4}, false},
+		{Request{Speed: 0.1}, true},
+		{Request{Speed: 4.5}, true},
+		{Request{Format: "ogg"}, true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.req, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package tts
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Usage describes one successful provider request
+type Usage struct {
+	Provider   string
+	Model      string
+	Voice      Voice
+	Chars      int
+	AudioBytes int64
+	// Latency is how long the provider took to answer: the whole
+	// synthesis, or until a stream started
+	Latency time.Duration
+	CostUSD float64
+}
+
+// meteredSynthesizer reports the usage of each request to record
+type meteredSynthesizer struct {
+	wrapper
+	costs  CostTable
+	record func(Usage)
+}
+
+// WithUsage calls record after every request that reaches a provider of s,
+// pricing it with costs. Cache hits never reach a provider, so they are
+// not recorded. A nil record returns s unchanged.
+func WithUsage(s Synthesizer, costs CostTable, record func(Usage)) Synthesizer {
+	if record == nil {
+		return s
+	}
+	return wrapProviders(s, func(p Synthesizer) Synthesizer {
+		return &meteredSynthesizer{wrapper: wrapper{p}, costs: costs, record: record}
+	})
+}
+
+// Synthesize calls the provider and records the request
+func (ms *meteredSynthesizer) Synthesize(ctx context.Context, req Request) (*Audio, error) {
+	start := time.Now()
+	audio, err := ms.synth.Synthesize(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	u := ms.usage(req, time.Since(start))
+	u.AudioBytes = int64(len(audio.Data))
+	ms.record(u)
+	return audio, nil
+}
+
+// Stream opens a stream from the provider and records the request once
+// the stream is read to the end or closed, when its size is known
+func (ms *meteredSynthesizer) Stream(ctx context.Context, req Request) (*AudioStream, error) {
+	start := time.Now()
+	stream, err := Stream(ctx, ms.synth, req)
+	if err != nil {
+		return nil, err
+	}
+	u := ms.usage(req, time.Since(start))
+	stream.Body = &countingBody{ReadCloser: stream.Body, done: func(n int64) {
+		u.AudioBytes = n
+		ms.record(u)
+	}}
+	return stream, nil
+}
+
+// usage describes req sent to the wrapped provider
+func (ms *meteredSynthesizer) usage(req Request, latency time.Duration) Usage {
+	chars := utf8.RuneCountInString(req.Text)
+	return Usage{
+		Provider: ms.synth.Name(),
+		Model:    providerModel(ms.synth),
+		Voice:    req.Voice,
+		Chars:    chars,
+		Latency:  latency,
+		CostUSD:  ms.costs.Estimate(ms.synth, chars),
+	}
+}
+
+// countingBody counts the bytes read and calls done once, at the end of
+// the body or when it is closed
+type countingBody struct {
+	io.ReadCloser
+	n    int64
+	once sync.Once
+	done func(int64)
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	if err == io.EOF {
+		b.once.Do(func() { b.done(b.n) })
+	}
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.n) })
+	return err
+}
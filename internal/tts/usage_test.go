@@ -0,0 +1,55 @@
+package tts
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+)
+
+func TestWithUsage_Synthesize(t *testing.T) {
+	var records []Usage
+	s := WithUsage(NewClient(), NewCostTable(&config.Config{}), func(u Usage) { records = append(records, u) })
+	if _, ok := s.(TextLimiter); !ok || s.Name() != "openai" {
+		t.Fatal("expected the wrapper to keep the provider's name and limit")
+	}
+
+	primary := &scriptedSynthesizer{name: "openai", err: &APIError{StatusCode: 503, Kind: ErrServerError, Message: "down"}}
+	fallback := &scriptedSynthesizer{name: "espeak"}
+	chain := WithUsage(NewChain(config.BreakerConfig{}, primary, fallback), NewCostTable(&config.Config{}), func(u Usage) { records = append(records, u) })
+
+	if _, err := chain.Synthesize(context.Background(), Request{Text: "héllo", Voice: "en-us"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected only the successful request to be recorded, got %+v", records)
+	}
+	if u := records[0]; u.Provider != "espeak" || u.Chars != 5 || u.AudioBytes != int64(len("espeak")) || u.CostUSD != 0 {
+		t.Errorf("unexpected usage %+v", u)
+	}
+}
+
+func TestWithUsage_Stream(t *testing.T) {
+	var records []Usage
+	s := WithUsage(&stubSynthesizer{name: "tts-1-server"}, NewCostTable(&config.Config{Budget: config.BudgetConfig{Costs: map[string]float64{"tts-1-server": 15}}}), func(u Usage) {
+		records = append(records, u)
+	})
+
+	stream, err := Stream(context.Background(), s, Request{Text: "streamed text"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 0 {
+		t.Fatal("expected the stream to be recorded once read")
+	}
+	data, _ := io.ReadAll(stream.Body)
+	stream.Body.Close()
+
+	if len(records) != 1 {
+		t.Fatalf("expected one record, got %d", len(records))
+	}
+	if u := records[0]; u.AudioBytes != int64(len(data)) || u.Chars != 13 || u.CostUSD != 15*13/1e6 {
+		t.Errorf("unexpected usage %+v", u)
+	}
+}
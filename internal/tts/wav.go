@@ -0,0 +1,34 @@
+package tts
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// pcmToWAV wraps raw little-endian PCM samples in a WAV header
+func pcmToWAV(pcm []byte, sampleRate, channels, bitsPerSample int) []byte {
+	blockAlign := channels * bitsPerSample / 8
+	byteRate := sampleRate * blockAlign
+
+	var buf bytes.Buffer
+	buf.Grow(44 + len(pcm))
+
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(36+len(pcm)))
+	buf.WriteString("WAVE")
+
+	buf.WriteString("fmt ")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(16))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(1)) // PCM
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(channels))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(sampleRate))
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(byteRate))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(blockAlign))
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(bitsPerSample))
+
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(pcm)))
+	buf.Write(pcm)
+
+	return buf.Bytes()
+}
@@ -0,0 +1,55 @@
+package tts
+
+// wrapper forwards the optional provider interfaces to the wrapped
+// synthesizer, so a wrapped provider keeps its voices, model and limits
+type wrapper struct {
+	synth Synthesizer
+}
+
+// Name returns the wrapped provider's name
+func (w wrapper) Name() string {
+	return w.synth.Name()
+}
+
+// Model returns the wrapped provider's model, if it has one
+func (w wrapper) Model() string {
+	return providerModel(w.synth)
+}
+
+// Voices returns the wrapped provider's voices, or nil if it accepts any
+func (w wrapper) Voices() []Voice {
+	if vl, ok := w.synth.(VoiceLister); ok {
+		return vl.Voices()
+	}
+	return nil
+}
+
+// MaxTextLength returns the wrapped provider's input limit
+func (w wrapper) MaxTextLength() int {
+	return MaxTextLength(w.synth)
+}
+
+// PlaysAudio reports whether the wrapped provider speaks itself
+func (w wrapper) PlaysAudio() bool {
+	return PlaysAudio(w.synth)
+}
+
+// providerModel returns the model of providers that expose one
+func providerModel(s Synthesizer) string {
+	if m, ok := s.(interface{ Model() string }); ok {
+		return m.Model()
+	}
+	return ""
+}
+
+// wrapProviders applies wrap to s, or to each provider of a Chain so the
+// chain keeps its breakers and health reporting
+func wrapProviders(s Synthesizer, wrap func(Synthesizer) Synthesizer) Synthesizer {
+	if chain, ok := s.(*Chain); ok {
+		for i := range chain.links {
+			chain.links[i].synth = wrap(chain.links[i].synth)
+		}
+		return chain
+	}
+	return wrap(s)
+}
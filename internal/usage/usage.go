@@ -0,0 +1,295 @@
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+	"github.com/ybouhjira/claude-code-tts/internal/tts"
+)
+
+// Summary windows
+const (
+	Day   = "day"
+	Week  = "week"
+	Month = "month"
+)
+
+// Windows lists the accepted summary windows
+func Windows() []string {
+	return []string{Day, Week, Month}
+}
+
+// Record is one synthesis in the usage log
+type Record struct {
+	Time       time.Time `json:"time"`
+	Project    string    `json:"project,omitempty"`
+	Provider   string    `json:"provider"`
+	Model      string    `json:"model,omitempty"`
+	Voice      string    `json:"voice,omitempty"`
+	Chars      int       `json:"chars"`
+	AudioBytes int64     `json:"audio_bytes"`
+	LatencyMS  int64     `json:"latency_ms"`
+	CostUSD    float64   `json:"cost_usd"`
+}
+
+// Totals adds up a set of records
+type Totals struct {
+	Requests     int     `json:"requests"`
+	Chars        int     `json:"chars"`
+	AudioBytes   int64   `json:"audio_bytes"`
+	CostUSD      float64 `json:"cost_usd"`
+	AvgLatencyMS int64   `json:"avg_latency_ms"`
+
+	latencyMS int64
+}
+
+// add counts r in the totals
+func (t *Totals) add(r Record) {
+	t.Requests++
+	t.Chars += r.Chars
+	t.AudioBytes += r.AudioBytes
+	t.CostUSD += r.CostUSD
+	t.latencyMS += r.LatencyMS
+	t.AvgLatencyMS = t.latencyMS / int64(t.Requests)
+}
+
+// Group is the totals of one project or provider
+type Group struct {
+	Name string `json:"name"`
+	Totals
+}
+
+// Summary is the usage within a window, broken down by project and by
+// provider, most expensive first
+type Summary struct {
+	Window    string    `json:"window"`
+	Since     time.Time `json:"since"`
+	Total     Totals    `json:"total"`
+	Projects  []Group   `json:"projects"`
+	Providers []Group   `json:"providers"`
+}
+
+// Store is an append-only log of syntheses, one JSON record per line.
+// The server and speak-text append to the same file.
+type Store struct {
+	path    string
+	project string
+	now     func() time.Time
+
+	mu sync.Mutex // serializes appends within this process
+}
+
+// DefaultPath returns tts-usage.jsonl next to the config file
+func DefaultPath() string {
+	return filepath.Join(filepath.Dir(config.Path()), "tts-usage.jsonl")
+}
+
+// Open returns the store described by cfg, recording under project, the
+// directory of the project in use (see config.ProjectDir).
+// It returns nil if usage logging is disabled.
+func Open(cfg config.UsageConfig, project string) *Store {
+	if cfg.Disabled {
+		return nil
+	}
+	path := DefaultPath()
+	if cfg.Path != "" {
+		path = config.ExpandPath(cfg.Path)
+	}
+	return &Store{path: path, project: project, now: time.Now}
+}
+
+// Path returns the location of the log
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Add appends a record of u
+func (s *Store) Add(u tts.Usage) error {
+	data, err := json.Marshal(Record{
+		Time:       s.now().UTC(),
+		Project:    s.project,
+		Provider:   u.Provider,
+		Model:      u.Model,
+		Voice:      string(u.Voice),
+		Chars:      u.Chars,
+		AudioBytes: u.AudioBytes,
+		LatencyMS:  u.Latency.Milliseconds(),
+		CostUSD:    u.CostUSD,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	// One write per record, so concurrent appends do not interleave
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return f.Close()
+}
+
+// Records returns the records made at or after since, oldest first.
+// Lines that cannot be parsed, like one cut short by a crash, are skipped.
+func (s *Store) Records(since time.Time) ([]Record, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		if !r.Time.Before(since) {
+			records = append(records, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+	return records, nil
+}
+
+// Summary adds up the records of the current day, week or month
+func (s *Store) Summary(window string) (*Summary, error) {
+	since, err := WindowStart(window, s.now())
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.Records(since)
+	if err != nil {
+		return nil, err
+	}
+	return Summarize(records, window, since), nil
+}
+
+// WindowStart returns the local midnight that starts the current day,
+// week (from Monday) or month
+func WindowStart(window string, now time.Time) (time.Time, error) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch window {
+	case Day:
+		return midnight, nil
+	case Week:
+		return midnight.AddDate(0, 0, -(int(now.Weekday())+6)%7), nil
+	case Month:
+		return midnight.AddDate(0, 0, 1-now.Day()), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown window %q (use day, week or month)", window)
+}
+
+// Summarize adds up records by project and by provider
+func Summarize(records []Record, window string, since time.Time) *Summary {
+	sum := &Summary{Window: window, Since: since, Projects: []Group{}, Providers: []Group{}}
+	projects := make(map[string]*Totals)
+	providers := make(map[string]*Totals)
+	for _, r := range records {
+		sum.Total.add(r)
+		project := r.Project
+		if project == "" {
+			project = "unknown"
+		}
+		group(projects, project).add(r)
+		group(providers, r.Provider).add(r)
+	}
+	sum.Projects = sorted(projects)
+	sum.Providers = sorted(providers)
+	return sum
+}
+
+// group returns the totals for name, creating them if needed
+func group(groups map[string]*Totals, name string) *Totals {
+	t, ok := groups[name]
+	if !ok {
+		t = &Totals{}
+		groups[name] = t
+	}
+	return t
+}
+
+// sorted returns the groups by cost, then characters, then name
+func sorted(groups map[string]*Totals) []Group {
+	list := make([]Group, 0, len(groups))
+	for name, t := range groups {
+		list = append(list, Group{Name: name, Totals: *t})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.CostUSD != b.CostUSD {
+			return a.CostUSD > b.CostUSD
+		}
+		if a.Chars != b.Chars {
+			return a.Chars > b.Chars
+		}
+		return a.Name < b.Name
+	})
+	return list
+}
+
+// WriteText writes the summary as tables for the terminal
+func (sum *Summary) WriteText(w io.Writer) error {
+	fmt.Fprintf(w, "TTS usage this %s (since %s)\n", sum.Window, sum.Since.Format(time.DateOnly))
+	for _, section := range []struct {
+		title  string
+		groups []Group
+	}{
+		{"PROJECT", sum.Projects},
+		{"PROVIDER", sum.Providers},
+	} {
+		fmt.Fprintln(w)
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "%s\tREQUESTS\tCHARS\tAUDIO\tAVG LATENCY\tCOST\n", section.title)
+		for _, g := range section.groups {
+			writeRow(tw, g.Name, g.Totals)
+		}
+		writeRow(tw, "total", sum.Total)
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRow writes one line of totals
+func writeRow(w io.Writer, name string, t Totals) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%dms\t$%.4f\n",
+		name, t.Requests, t.Chars, formatBytes(t.AudioBytes), t.AvgLatencyMS, t.CostUSD)
+}
+
+// formatBytes renders n in B, KB or MB
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
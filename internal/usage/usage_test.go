@@ -0,0 +1,124 @@
+package usage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ybouhjira/claude-code-tts/internal/config"
+	"github.com/ybouhjira/claude-code-tts/internal/tts"
+)
+
+// testStore returns a store in a temp dir with a settable clock
+func testStore(t *testing.T, project string) (*Store, *time.Time) {
+	t.Helper()
+	s := Open(config.UsageConfig{Path: filepath.Join(t.TempDir(), "tts-usage.jsonl")}, project)
+	now := time.Date(2026, 3, 18, 15, 0, 0, 0, time.Local) // a Wednesday
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+func TestOpen_Disabled(t *testing.T) {
+	if s := Open(config.UsageConfig{Disabled: true}, "p"); s != nil {
+		t.Error("expected no store when disabled")
+	}
+}
+
+func TestWindowStart(t *testing.T) {
+	now := time.Date(2026, 3, 18, 15, 4, 5, 0, time.Local)
+	tests := []struct {
+		window string
+		want   time.Time
+	}{
+		{Day, time.Date(2026, 3, 18, 0, 0, 0, 0, time.Local)},
+		{Week, time.Date(2026, 3, 16, 0, 0, 0, 0, time.Local)},
+		{Month, time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := WindowStart(tt.window, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("WindowStart(%q) = %v, %v; want %v", tt.window, got, err, tt.want)
+		}
+	}
+
+	sunday := time.Date(2026, 3, 22, 9, 0, 0, 0, time.Local)
+	if got, _ := WindowStart(Week, sunday); !got.Equal(time.Date(2026, 3, 16, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("expected Sunday to belong to the week starting Monday, got %v", got)
+	}
+	if _, err := WindowStart("year", now); err == nil {
+		t.Error("expected an error for an unknown window")
+	}
+}
+
+func TestStore_Summary(t *testing.T) {
+	s, now := testStore(t, "/src/api")
+	other := Open(config.UsageConfig{Path: s.Path()}, "/src/web")
+	other.now = s.now
+
+	// Last month, outside every window
+	*now = now.AddDate(0, -1, 0)
+	s.Add(tts.Usage{Provider: "openai", Chars: 9999, CostUSD: 1})
+	*now = now.AddDate(0, 1, 0)
+
+	s.Add(tts.Usage{Provider: "openai", Model: "tts-1", Voice: tts.VoiceNova, Chars: 1000, AudioBytes: 48000, Latency: 800 * time.Millisecond, CostUSD: 0.015})
+	s.Add(tts.Usage{Provider: "openai", Model: "tts-1", Chars: 2000, AudioBytes: 96000, Latency: 1200 * time.Millisecond, CostUSD: 0.03})
+	other.Add(tts.Usage{Provider: "espeak", Chars: 500, AudioBytes: 20000, Latency: 100 * time.Millisecond})
+
+	sum, err := s.Summary(Month)
+	if err != nil {
+		t.Fatalf("Summary: %v", err)
+	}
+	if sum.Total.Requests != 3 || sum.Total.Chars != 3500 || sum.Total.AudioBytes != 164000 {
+		t.Errorf("unexpected totals %+v", sum.Total)
+	}
+	if len(sum.Projects) != 2 || sum.Projects[0].Name != "/src/api" || sum.Projects[0].Chars != 3000 {
+		t.Fatalf("unexpected projects %+v", sum.Projects)
+	}
+	if api := sum.Projects[0]; api.AvgLatencyMS != 1000 || api.CostUSD < 0.0449 || api.CostUSD > 0.0451 {
+		t.Errorf("unexpected api totals %+v", api)
+	}
+	if len(sum.Providers) != 2 || sum.Providers[0].Name != "openai" || sum.Providers[1].Name != "espeak" {
+		t.Errorf("unexpected providers %+v", sum.Providers)
+	}
+}
+
+func TestStore_SkipsCorruptLines(t *testing.T) {
+	s, _ := testStore(t, "p")
+	s.Add(tts.Usage{Provider: "openai", Chars: 10})
+
+	f, err := os.OpenFile(s.Path(), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"time":"2026-03-18T`)
+	f.Close()
+
+	records, err := s.Records(time.Time{})
+	if err != nil {
+		t.Fatalf("Records: %v", err)
+	}
+	if len(records) != 1 || records[0].Chars != 10 || records[0].Project != "p" {
+		t.Errorf("unexpected records %+v", records)
+	}
+}
+
+func TestSummary_WriteText(t *testing.T) {
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	sum := Summarize([]Record{
+		{Project: "/src/api", Provider: "openai", Chars: 1200, AudioBytes: 3 << 20, LatencyMS: 900, CostUSD: 0.018},
+	}, Month, since)
+
+	var buf bytes.Buffer
+	if err := sum.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"TTS usage this month (since 2026-03-01)", "/src/api", "3.0 MB", "900ms", "$0.0180"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}